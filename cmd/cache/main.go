@@ -0,0 +1,97 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/go-pkgz/lgr"
+
+	"assemblyai-transcriber/internal/cache"
+	"assemblyai-transcriber/internal/config"
+	"assemblyai-transcriber/internal/export"
+)
+
+func run() int {
+	lgr.Setup()
+	if len(os.Args) < 2 {
+		usage()
+		return 1
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		lgr.Printf("Error loading config: %v", err)
+		return 1
+	}
+	if cfg.CacheDir == "" {
+		lgr.Printf("Error: CACHE_DIR is not set, caching is disabled")
+		return 1
+	}
+	store := cache.New(cfg.CacheDir, int64(cfg.CacheMaxSizeMB)*1024*1024)
+
+	switch os.Args[1] {
+	case "ls":
+		return list(store)
+	case "prune":
+		fs := flag.NewFlagSet("prune", flag.ExitOnError)
+		olderThan := fs.Duration("older-than", 0, "Remove entries not used for this long, e.g. 720h")
+		maxSize := fs.Int("max-size-mb", cfg.CacheMaxSizeMB, "Remove least recently used entries until the cache fits (0: no limit)")
+		_ = fs.Parse(os.Args[2:])
+		return prune(store, *olderThan, int64(*maxSize)*1024*1024)
+	default:
+		usage()
+		return 1
+	}
+}
+
+// list prints all cache entries, most recently used first
+func list(store *cache.Cache) int {
+	entries, err := store.List()
+	if err != nil {
+		lgr.Printf("Error: %v", err)
+		return 1
+	}
+
+	var total int64
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KIND\tKEY\tSIZE\tLAST USED")
+	for _, e := range entries {
+		total += e.Size
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", e.Kind, e.Key[:min(len(e.Key), 16)], export.FormatSize(e.Size),
+			e.LastUsed.Local().Format(time.DateTime))
+	}
+	if err := w.Flush(); err != nil {
+		lgr.Printf("Error: %v", err)
+		return 1
+	}
+	fmt.Printf("%d entries, %s\n", len(entries), export.FormatSize(total))
+	return 0
+}
+
+// prune removes expired and least recently used entries
+func prune(store *cache.Cache, olderThan time.Duration, maxBytes int64) int {
+	removed, err := store.Prune(olderThan, maxBytes)
+	var freed int64
+	for _, e := range removed {
+		freed += e.Size
+	}
+	lgr.Printf("Removed %d entries, freed %s", len(removed), export.FormatSize(freed))
+	if err != nil {
+		lgr.Printf("Error: %v", err)
+		return 1
+	}
+	return 0
+}
+
+func usage() {
+	lgr.Printf("Usage:")
+	lgr.Printf("  cache ls")
+	lgr.Printf("  cache prune [--older-than=720h] [--max-size-mb=N]")
+}
+
+func main() {
+	os.Exit(run())
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/go-pkgz/lgr"
+
+	"assemblyai-transcriber/internal/archive"
+	"assemblyai-transcriber/internal/config"
+	"assemblyai-transcriber/internal/database"
+)
+
+func run() int {
+	lgr.Setup()
+	if len(os.Args) < 2 {
+		usage()
+		return 1
+	}
+
+	fs := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	dbPathFlag := fs.String("db", "", "Path to database file or postgres:// URL (default: DATABASE_URL or DATABASE_PATH)")
+	toFlag := fs.Int64("to", 0, "Migrate up to this version (default: latest)")
+	formatFlag := fs.String("format", "", "Export format: json or ndjson (default: json for .json files, ndjson otherwise)")
+	switch os.Args[1] {
+	case "migrate", "down", "status", "backup", "export", "import":
+		_ = fs.Parse(os.Args[2:])
+	default:
+		usage()
+		return 1
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		lgr.Printf("Error loading config: %v", err)
+		return 1
+	}
+	dbPath := cfg.DatabaseDSN()
+	if *dbPathFlag != "" {
+		dbPath = *dbPathFlag
+	}
+
+	// the migration commands work on databases in any state and a backup keeps the schema
+	// from before the next migration; export and import work on the current schema, so
+	// they migrate the database first
+	open := database.Open
+	if os.Args[1] == "export" || os.Args[1] == "import" {
+		open = database.New
+	}
+	db, err := open(dbPath)
+	if err != nil {
+		lgr.Printf("Error opening database: %v", err)
+		return 1
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	switch os.Args[1] {
+	case "migrate":
+		return migrate(ctx, db, dbPath, *toFlag)
+	case "down":
+		name, err := db.MigrateDown(ctx)
+		if err != nil {
+			lgr.Printf("Error: %v", err)
+			return 1
+		}
+		lgr.Printf("Rolled back %s", name)
+		return 0
+	case "status":
+		return status(ctx, db)
+	case "backup":
+		return backup(ctx, db, dbPath, fs.Arg(0))
+	case "export":
+		return exportLibrary(db, fs.Arg(0), *formatFlag)
+	default:
+		return importLibrary(db, fs.Arg(0))
+	}
+}
+
+// backup copies the database to path, by default next to the database with a timestamp
+func backup(ctx context.Context, db *database.DB, dbPath, path string) int {
+	if path == "" {
+		path = strings.TrimSuffix(dbPath, filepath.Ext(dbPath)) + "-" + time.Now().Format("20060102-150405") + ".db"
+	}
+	if err := db.Backup(ctx, path); err != nil {
+		lgr.Printf("Error: %v", err)
+		return 1
+	}
+	lgr.Printf("Backed up %s to %s", dbPath, path)
+	return 0
+}
+
+// exportLibrary writes the library archive to path, or to stdout when path is empty or "-"
+func exportLibrary(db *database.DB, path, format string) int {
+	if format == "" {
+		format = archive.FormatNDJSON
+		if strings.EqualFold(filepath.Ext(path), ".json") {
+			format = archive.FormatJSON
+		}
+	}
+
+	out := os.Stdout
+	if path != "" && path != "-" {
+		f, err := os.Create(path)
+		if err != nil {
+			lgr.Printf("Error: %v", err)
+			return 1
+		}
+		defer f.Close()
+		out = f
+	}
+
+	stats, err := archive.Export(db, out, format)
+	if err != nil {
+		lgr.Printf("Error: %v", err)
+		return 1
+	}
+	// the archive itself may be written to stdout, so the summary is only logged for files
+	if out != os.Stdout {
+		if err := out.Close(); err != nil {
+			lgr.Printf("Error: %v", err)
+			return 1
+		}
+		lgr.Printf("Exported %d transcriptions and %d glossary terms to %s", stats.Transcriptions, stats.Terms, path)
+	}
+	return 0
+}
+
+// importLibrary adds the transcriptions and glossary of an archive to the database
+func importLibrary(db *database.DB, path string) int {
+	var r io.Reader = os.Stdin
+	if path != "" && path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			lgr.Printf("Error: %v", err)
+			return 1
+		}
+		defer f.Close()
+		r = f
+	}
+
+	result, err := archive.Import(db, r)
+	ids := make([]int64, 0, len(result.IDs))
+	for id := range result.IDs {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	for _, id := range ids {
+		lgr.Printf("Transcription %d is stored as %d", id, result.IDs[id])
+	}
+	lgr.Printf("Imported %d transcriptions, skipped %d already present, added %d glossary terms",
+		result.Imported, result.Skipped, result.Terms)
+	if err != nil {
+		lgr.Printf("Error: %v", err)
+		return 1
+	}
+	return 0
+}
+
+// migrate applies pending migrations up to version, or all of them when it is 0
+func migrate(ctx context.Context, db *database.DB, dbPath string, version int64) int {
+	applied, err := db.MigrateTo(ctx, version)
+	for _, name := range applied {
+		lgr.Printf("Applied %s", name)
+	}
+	if err != nil {
+		lgr.Printf("Error: %v", err)
+		return 1
+	}
+	if len(applied) == 0 {
+		lgr.Printf("%s is up to date", dbPath)
+	}
+	return 0
+}
+
+// status prints every migration with the time it was applied
+func status(ctx context.Context, db *database.DB) int {
+	migrations, err := db.MigrationStatus(ctx)
+	if err != nil {
+		lgr.Printf("Error: %v", err)
+		return 1
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tMIGRATION\tAPPLIED")
+	for _, m := range migrations {
+		applied := "pending"
+		if m.Applied {
+			applied = m.AppliedAt.Local().Format(time.DateTime)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", m.Version, m.Name, applied)
+	}
+	if err := w.Flush(); err != nil {
+		lgr.Printf("Error: %v", err)
+		return 1
+	}
+	return 0
+}
+
+func usage() {
+	lgr.Printf("Usage:")
+	lgr.Printf("  db migrate [--to=N] [--db=path]  apply pending migrations (import and the other tools do this on startup)")
+	lgr.Printf("  db down [--db=path]              roll back the latest migration")
+	lgr.Printf("  db status [--db=path]            list migrations and when they were applied")
+	lgr.Printf("  db backup [--db=path] [file]     copy a SQLite database while it is in use")
+	lgr.Printf("  db export [--format=json|ndjson] [--db=path] [file]  write the library to a portable archive (default: stdout)")
+	lgr.Printf("  db import [--db=path] file       add the transcriptions and glossary of an archive under new IDs")
+	lgr.Printf("Flags go before the file name")
+}
+
+func main() {
+	os.Exit(run())
+}
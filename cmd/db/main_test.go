@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"assemblyai-transcriber/internal/database"
+)
+
+// version returns the latest applied migration of a database
+func version(t *testing.T, path string) int64 {
+	db, err := database.Open(path)
+	require.NoError(t, err)
+	defer db.Close()
+	migrations, err := db.MigrationStatus(context.Background())
+	require.NoError(t, err)
+	var v int64
+	for _, m := range migrations {
+		if m.Applied {
+			v = m.Version
+		}
+	}
+	return v
+}
+
+func TestBackup_KeepsSchemaVersion(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "old.db")
+	db, err := database.Open(dbPath)
+	require.NoError(t, err)
+	_, err = db.MigrateTo(context.Background(), 12)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	backupPath := filepath.Join(dir, "backup.db")
+	args := os.Args
+	t.Cleanup(func() { os.Args = args })
+	os.Args = []string{"db", "backup", "-db", dbPath, backupPath}
+	require.Equal(t, 0, run())
+
+	require.Equal(t, int64(12), version(t, backupPath), "the backup holds the database before the migrations")
+	require.Equal(t, int64(12), version(t, dbPath), "backing up does not migrate the database")
+}
+
+func TestExport_MigratesOldDatabase(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "old.db")
+	db, err := database.Open(dbPath)
+	require.NoError(t, err)
+	_, err = db.MigrateTo(context.Background(), 4)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	exportPath := filepath.Join(dir, "library.json")
+	args := os.Args
+	t.Cleanup(func() { os.Args = args })
+	os.Args = []string{"db", "export", "-db", dbPath, exportPath}
+	require.Equal(t, 0, run())
+
+	require.FileExists(t, exportPath)
+	require.Greater(t, version(t, dbPath), int64(12), "the database is migrated before the export")
+}
@@ -2,63 +2,100 @@ package main
 
 import (
 	"flag"
-	"fmt"
 	"os"
-	"path/filepath"
+	"strings"
 
 	"github.com/go-pkgz/lgr"
-	"github.com/jmoiron/sqlx"
-	_ "github.com/mattn/go-sqlite3"
 
 	"assemblyai-transcriber/internal/config"
+	"assemblyai-transcriber/internal/database"
+	"assemblyai-transcriber/internal/export"
+	"assemblyai-transcriber/internal/interfaces"
 )
 
-func main() {
+func run() int {
 	lgr.Setup()
-	outDir := flag.String("out", "./translations", "Output directory for MD files")
+	outDir := flag.String("out", "./translations", "Output directory")
+	formatFlag := flag.String("format", "md", "Export format: "+strings.Join(export.FormatNames(), ", ")+
+		" (epub writes one book for the selected tag or collection)")
+	tag := flag.String("tag", "", "Export only transcriptions with this tag")
+	collection := flag.String("collection", "", "Export only transcriptions in this collection")
+	dbPath := flag.String("db", "", "Path to database file or postgres:// URL (default: DATABASE_URL or DATABASE_PATH)")
+	suffix := flag.String("suffix", "", "Name files after the source file with this suffix, e.g. {lang} writes talk_ru.md for talk.mp4; "+
+		"{lang}, {id} and {translation} are replaced (default: translation_<id>)")
+	original := flag.Bool("original", false, "Append the original transcript")
+	bilingual := flag.Bool("bilingual", false, "Show the original and the translation side by side")
+	prune := flag.Bool("prune", false, "Remove files of earlier exports whose translations were deleted, became stale or were renamed")
 	flag.Parse()
 
+	format, err := export.Lookup(*formatFlag)
+	if err != nil {
+		lgr.Printf("Error: %v", err)
+		return 1
+	}
+
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
-		lgr.Fatalf("Error loading configuration: %v", err)
+		lgr.Printf("Error loading configuration: %v", err)
+		return 1
 	}
 
 	// Initialize database
-	db, err := sqlx.Open("sqlite3", cfg.DatabasePath)
+	dsn := cfg.DatabaseDSN()
+	if *dbPath != "" {
+		dsn = *dbPath
+	}
+	db, err := database.New(dsn)
 	if err != nil {
-		lgr.Fatalf("Error opening database: %v", err)
+		lgr.Printf("Error opening database: %v", err)
+		return 1
 	}
 	defer db.Close()
 
-	// Create output directory
-        if err := os.MkdirAll(*outDir, 0o750); err != nil {
-		lgr.Fatalf("Error creating output directory: %v", err)
+	err = exportTranslations(db, *outDir, format, export.Options{
+		Suffix:     *suffix,
+		Original:   *original,
+		Bilingual:  *bilingual,
+		Tag:        *tag,
+		Collection: *collection,
+		Prune:      *prune,
+	})
+	if err != nil {
+		lgr.Printf("Error: %v", err)
+		return 1
 	}
+	return 0
+}
 
-	// Get all translations
-	var translations []struct {
-		ID             int    `db:"id"`
-		TranslatedText string `db:"translated_text"`
+// exportTranslations saves new and changed translations to files of the given format in
+// outDir and prints the changes like a diff: + created, ~ updated, - removed. The changes
+// are printed even when writing some of the files failed, the errors are returned.
+func exportTranslations(repo interfaces.Repository, outDir string, format export.Format, opts export.Options) error {
+	result, err := export.Export(repo, outDir, format, opts)
+	for _, path := range result.Created {
+		lgr.Printf("+ %s", path)
 	}
-
-	err = db.Select(&translations, "SELECT id, translated_text FROM translations")
-	if err != nil {
-		lgr.Fatalf("Error querying translations: %v", err)
+	for _, path := range result.Updated {
+		lgr.Printf("~ %s", path)
 	}
-
-	lgr.Printf("Found %d translations", len(translations))
-
-	// Save each translation to a markdown file
-	for _, t := range translations {
-		outputPath := filepath.Join(*outDir, fmt.Sprintf("translation_%d.md", t.ID))
-                err = os.WriteFile(outputPath, []byte(t.TranslatedText), 0o600)
-		if err != nil {
-			lgr.Printf("Error saving %s: %v", outputPath, err)
-			continue
-		}
-		lgr.Printf("Saved %s", outputPath)
+	for _, path := range result.Removed {
+		lgr.Printf("- %s", path)
 	}
 
+	lgr.Printf("Created %d, updated %d, removed %d, unchanged %d files.",
+		len(result.Created), len(result.Updated), len(result.Removed), len(result.Unchanged))
+	if len(result.Obsolete) > 0 {
+		lgr.Printf("%d obsolete files of deleted, stale or renamed translations are kept, run with -prune to remove them: %s",
+			len(result.Obsolete), strings.Join(result.Obsolete, ", "))
+	}
+	if err != nil {
+		return err
+	}
 	lgr.Printf("Done!")
+	return nil
+}
+
+func main() {
+	os.Exit(run())
 }
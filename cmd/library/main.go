@@ -0,0 +1,339 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/go-pkgz/lgr"
+
+	"assemblyai-transcriber/internal/config"
+	"assemblyai-transcriber/internal/database"
+	"assemblyai-transcriber/internal/export"
+	"assemblyai-transcriber/internal/language"
+)
+
+func run() int {
+	lgr.Setup()
+	if len(os.Args) < 2 {
+		usage()
+		return 1
+	}
+
+	cmd := os.Args[1]
+	fs := flag.NewFlagSet(cmd, flag.ExitOnError)
+	dbPathFlag := fs.String("db", "", "Path to database file or postgres:// URL (default: DATABASE_URL or DATABASE_PATH)")
+	idFlag := fs.Int64("id", 0, "Transcription ID")
+	sinceFlag := fs.String("since", "", "List transcriptions created on or after this date (YYYY-MM-DD)")
+	untilFlag := fs.String("until", "", "List transcriptions created before this date (YYYY-MM-DD)")
+	tagFlag := fs.String("tag", "", "List transcriptions with this tag")
+	collectionFlag := fs.String("collection", "", "Collection name")
+	translatedFlag := fs.Bool("translated", false, "List translated transcriptions only")
+	untranslatedFlag := fs.Bool("untranslated", false, "List untranslated transcriptions only")
+	langFlag := fs.String("lang", "", "List transcriptions in this language, e.g. en")
+	yesFlag := fs.Bool("yes", false, "Delete without asking for confirmation")
+	removeFlag := fs.Bool("remove", false, "Remove the transcription from the collection instead of adding it")
+	deleteFlag := fs.String("delete", "", "Delete this collection; its transcriptions are kept")
+	switch cmd {
+	case "list", "show", "delete", "tag", "untag", "tags", "collect", "collections":
+		_ = fs.Parse(os.Args[2:])
+	default:
+		usage()
+		return 1
+	}
+	needsID := cmd == "show" || cmd == "delete" || cmd == "tag" || cmd == "untag" || cmd == "collect"
+	if needsID && *idFlag == 0 {
+		usage()
+		return 1
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		lgr.Printf("Error loading config: %v", err)
+		return 1
+	}
+	dbPath := cfg.DatabaseDSN()
+	if *dbPathFlag != "" {
+		dbPath = *dbPathFlag
+	}
+
+	db, err := database.New(dbPath)
+	if err != nil {
+		lgr.Printf("Error opening database: %v", err)
+		return 1
+	}
+	defer db.Close()
+
+	switch cmd {
+	case "list":
+		filter, err := buildFilter(*sinceFlag, *untilFlag, *tagFlag, *collectionFlag, *langFlag, *translatedFlag, *untranslatedFlag)
+		if err != nil {
+			lgr.Printf("Error: %v", err)
+			return 1
+		}
+		return list(db, filter)
+	case "show":
+		return show(db, *idFlag)
+	case "delete":
+		return remove(db, *idFlag, *yesFlag)
+	case "tag", "untag":
+		return tag(db, *idFlag, fs.Args(), cmd == "untag")
+	case "tags":
+		return tags(db)
+	case "collect":
+		return collect(db, *idFlag, *collectionFlag, *removeFlag)
+	default:
+		return collections(db, *deleteFlag)
+	}
+}
+
+// buildFilter converts the list flags into a transcription filter
+func buildFilter(since, until, tag, collection, lang string, translated, untranslated bool) (database.TranscriptionFilter, error) {
+	filter := database.TranscriptionFilter{Tag: tag, Collection: collection, Language: language.Normalize(lang)}
+	if translated && untranslated {
+		return filter, errors.New("--translated and --untranslated are mutually exclusive")
+	}
+	if translated || untranslated {
+		filter.Translated = &translated
+	}
+
+	var err error
+	if since != "" {
+		if filter.Since, err = time.ParseInLocation(time.DateOnly, since, time.Local); err != nil {
+			return filter, fmt.Errorf("invalid --since: %w", err)
+		}
+	}
+	if until != "" {
+		if filter.Until, err = time.ParseInLocation(time.DateOnly, until, time.Local); err != nil {
+			return filter, fmt.Errorf("invalid --until: %w", err)
+		}
+	}
+	return filter, nil
+}
+
+// list prints the transcriptions matching the filter
+func list(db *database.DB, filter database.TranscriptionFilter) int {
+	items, err := db.FindTranscriptions(filter)
+	if err != nil {
+		lgr.Printf("Error: %v", err)
+		return 1
+	}
+	if err := export.WriteList(os.Stdout, items); err != nil {
+		lgr.Printf("Error: %v", err)
+		return 1
+	}
+	return 0
+}
+
+// show prints the metadata and text of a transcription
+func show(db *database.DB, id int64) int {
+	record, err := db.GetTranscriptionRecord(id)
+	if err != nil {
+		lgr.Printf("Error: %v", err)
+		return 1
+	}
+	items, err := db.FindTranscriptions(database.TranscriptionFilter{ID: id})
+	if err != nil {
+		lgr.Printf("Error: %v", err)
+		return 1
+	}
+	var item database.TranscriptionListItem
+	if len(items) > 0 {
+		item = items[0]
+	}
+	names, err := db.GetCollections(id)
+	if err != nil {
+		lgr.Printf("Error: %v", err)
+		return 1
+	}
+	revisions, err := db.ListRevisions(id)
+	if err != nil {
+		lgr.Printf("Error: %v", err)
+		return 1
+	}
+	segments, err := db.GetSegments(id)
+	if err != nil {
+		lgr.Printf("Error: %v", err)
+		return 1
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	row := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(w, "%s:\t%s\n", name, value)
+		}
+	}
+	row("ID", fmt.Sprint(record.ID))
+	row("File", record.FileName)
+	row("Created", record.CreatedAt.Local().Format(time.DateTime))
+	row("Language", record.Language)
+	row("Profile", record.AudioProfile)
+	if item.DurationMS > 0 {
+		row("Duration", export.FormatDuration(item.DurationMS))
+	}
+	if item.FileSize > 0 {
+		row("Size", export.FormatSize(item.FileSize))
+	}
+	row("Segments", fmt.Sprint(len(segments)))
+	row("Translated", export.TranslationStatus(item))
+	row("Tags", strings.ReplaceAll(item.Tags, ",", ", "))
+	row("Collections", strings.Join(names, ", "))
+	if len(revisions) > 0 {
+		row("Revisions", fmt.Sprint(len(revisions)))
+	}
+	if err := w.Flush(); err != nil {
+		lgr.Printf("Error: %v", err)
+		return 1
+	}
+	fmt.Printf("\n%s\n", record.Text)
+	return 0
+}
+
+// remove deletes a transcription with everything that belongs to it after confirmation
+func remove(db *database.DB, id int64, yes bool) int {
+	record, err := db.GetTranscriptionRecord(id)
+	if err != nil {
+		lgr.Printf("Error: %v", err)
+		return 1
+	}
+	if !yes {
+		fmt.Printf("Delete transcription %d (%s) with its translations? [y/N]: ", id, record.FileName)
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+			lgr.Printf("Cancelled")
+			return 0
+		}
+	}
+
+	if err := db.DeleteTranscription(id); err != nil {
+		lgr.Printf("Error: %v", err)
+		return 1
+	}
+	lgr.Printf("Deleted transcription %d (%s)", id, record.FileName)
+	return 0
+}
+
+// tag adds or removes tags of a transcription
+func tag(db *database.DB, id int64, names []string, untag bool) int {
+	if len(names) == 0 {
+		usage()
+		return 1
+	}
+	if _, err := db.GetTranscriptionRecord(id); err != nil {
+		lgr.Printf("Error: %v", err)
+		return 1
+	}
+
+	update := db.TagTranscription
+	if untag {
+		update = db.UntagTranscription
+	}
+	if err := update(id, names...); err != nil {
+		lgr.Printf("Error: %v", err)
+		return 1
+	}
+
+	current, err := db.GetTags(id)
+	if err != nil {
+		lgr.Printf("Error: %v", err)
+		return 1
+	}
+	lgr.Printf("Tags of transcription %d: %s", id, strings.Join(current, ", "))
+	return 0
+}
+
+// tags prints all tags with the number of tagged transcriptions
+func tags(db *database.DB) int {
+	all, err := db.ListTags()
+	if err != nil {
+		lgr.Printf("Error: %v", err)
+		return 1
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TAG\tTRANSCRIPTIONS")
+	for _, t := range all {
+		fmt.Fprintf(w, "%s\t%d\n", t.Name, t.Count)
+	}
+	if err := w.Flush(); err != nil {
+		lgr.Printf("Error: %v", err)
+		return 1
+	}
+	return 0
+}
+
+// collect adds a transcription to a collection or removes it
+func collect(db *database.DB, id int64, name string, remove bool) int {
+	if name == "" {
+		usage()
+		return 1
+	}
+	if _, err := db.GetTranscriptionRecord(id); err != nil {
+		lgr.Printf("Error: %v", err)
+		return 1
+	}
+
+	if remove {
+		if err := db.RemoveFromCollection(name, id); err != nil {
+			lgr.Printf("Error: %v", err)
+			return 1
+		}
+		lgr.Printf("Removed transcription %d from %s", id, name)
+		return 0
+	}
+	if err := db.AddToCollection(name, id); err != nil {
+		lgr.Printf("Error: %v", err)
+		return 1
+	}
+	lgr.Printf("Added transcription %d to %s", id, name)
+	return 0
+}
+
+// collections prints all collections, or deletes one
+func collections(db *database.DB, deleteName string) int {
+	if deleteName != "" {
+		if err := db.DeleteCollection(deleteName); err != nil {
+			lgr.Printf("Error: %v", err)
+			return 1
+		}
+		lgr.Printf("Deleted collection %s", deleteName)
+		return 0
+	}
+
+	all, err := db.ListCollections()
+	if err != nil {
+		lgr.Printf("Error: %v", err)
+		return 1
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "COLLECTION\tTRANSCRIPTIONS")
+	for _, c := range all {
+		fmt.Fprintf(w, "%s\t%d\n", c.Name, c.Count)
+	}
+	if err := w.Flush(); err != nil {
+		lgr.Printf("Error: %v", err)
+		return 1
+	}
+	return 0
+}
+
+func usage() {
+	lgr.Printf("Usage:")
+	lgr.Printf("  library list [--since=2024-05-01] [--until=2024-06-01] [--tag=T] [--collection=C] [--translated|--untranslated] [--lang=en]")
+	lgr.Printf("  library show --id=N")
+	lgr.Printf("  library delete --id=N [--yes]")
+	lgr.Printf("  library tag --id=N tag...")
+	lgr.Printf("  library untag --id=N tag...")
+	lgr.Printf("  library tags")
+	lgr.Printf("  library collect --id=N --collection=C [--remove]")
+	lgr.Printf("  library collections [--delete=C]")
+	lgr.Printf("All commands accept --db=path")
+}
+
+func main() {
+	os.Exit(run())
+}
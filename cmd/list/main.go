@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/go-pkgz/lgr"
+
+	"assemblyai-transcriber/internal/config"
+	"assemblyai-transcriber/internal/database"
+	"assemblyai-transcriber/internal/export"
+	"assemblyai-transcriber/internal/interfaces"
+)
+
+func main() {
+	lgr.Setup()
+	dbPathFlag := flag.String("db", "", "Path to database file or postgres:// URL (default: DATABASE_URL or DATABASE_PATH)")
+	flag.Parse()
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		lgr.Fatalf("Error loading configuration: %v", err)
+	}
+	dbPath := cfg.DatabaseDSN()
+	if *dbPathFlag != "" {
+		dbPath = *dbPathFlag
+	}
+
+	// Initialize database
+	db, err := database.New(dbPath)
+	if err != nil {
+		lgr.Fatalf("Error opening database: %v", err)
+	}
+	defer db.Close()
+
+	if err := list(db); err != nil {
+		lgr.Fatalf("Error: %v", err)
+	}
+}
+
+// list prints the stored transcriptions
+func list(repo interfaces.Repository) error {
+	items, err := repo.ListTranscriptions()
+	if err != nil {
+		return err
+	}
+	return export.WriteList(os.Stdout, items)
+}
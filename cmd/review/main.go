@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/go-pkgz/lgr"
+
+	"assemblyai-transcriber/internal/config"
+	"assemblyai-transcriber/internal/database"
+	"assemblyai-transcriber/internal/export"
+)
+
+func main() {
+	lgr.Setup()
+	idFlag := flag.Int64("id", 0, "Transcription ID to review")
+	dbPathFlag := flag.String("db", "", "Path to database file or postgres:// URL (default: DATABASE_URL or DATABASE_PATH)")
+	thresholdFlag := flag.Float64("threshold", export.DefaultConfidenceThreshold, "Highlight words recognized with a lower confidence (0-1)")
+	formatFlag := flag.String("format", export.ReviewMarkdown, "Report format: md or html")
+	outFlag := flag.String("out", "", "Output file (default: stdout)")
+	flag.Parse()
+
+	if *idFlag == 0 {
+		lgr.Printf("Usage: review --id=N [--threshold=0.6] [--format=md|html] [--out=review.md]")
+		flag.PrintDefaults()
+		os.Exit(1)
+	}
+	if *thresholdFlag <= 0 || *thresholdFlag > 1 {
+		lgr.Fatalf("Error: --threshold must be between 0 and 1")
+	}
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		lgr.Fatalf("Error loading configuration: %v", err)
+	}
+	dbPath := cfg.DatabaseDSN()
+	if *dbPathFlag != "" {
+		dbPath = *dbPathFlag
+	}
+
+	// Initialize database
+	db, err := database.New(dbPath)
+	if err != nil {
+		lgr.Fatalf("Error opening database: %v", err)
+	}
+	defer db.Close()
+
+	report, err := export.Review(db, *idFlag, *thresholdFlag, *formatFlag)
+	if err != nil {
+		lgr.Fatalf("Error: %v", err)
+	}
+
+	if *outFlag == "" {
+		fmt.Print(report)
+		return
+	}
+	if err := os.WriteFile(*outFlag, []byte(report), 0o600); err != nil {
+		lgr.Fatalf("Error: %v", err)
+	}
+	lgr.Printf("Saved review of transcription %d to %s", *idFlag, *outFlag)
+}
@@ -3,13 +3,19 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/go-pkgz/lgr"
 
 	"assemblyai-transcriber/internal/config"
 	"assemblyai-transcriber/internal/database"
 	"assemblyai-transcriber/internal/interfaces"
+	"assemblyai-transcriber/internal/progress"
 	"assemblyai-transcriber/internal/savetodb"
 	"assemblyai-transcriber/internal/transcribe"
 )
@@ -19,23 +25,102 @@ func run() int {
 	var (
 		transcriptFlag = flag.String("transcript", "", "Path to transcript file")
 		videoFlag      = flag.String("video", "", "Path to video file")
-		dbPathFlag     = flag.String("db", "", "Path to database file")
+		dirFlag        = flag.String("dir", "", "Directory with media files to process in batch mode")
+		patternFlag    = flag.String("pattern", "", "Comma-separated glob patterns for file names in batch mode (e.g. 'lecture_*')")
+		extFlag        = flag.String("ext", "", "Comma-separated file extensions for batch mode (default: common video and audio formats)")
+		recursiveFlag  = flag.Bool("recursive", false, "Walk subdirectories in batch mode")
+		workersFlag    = flag.Int("workers", 1, "Number of files processed in parallel in batch mode")
+		dbPathFlag     = flag.String("db", "", "Path to database file or postgres:// URL")
+		trackFlag      = flag.Int("audio-track", 0, "Audio track to transcribe, starting at 1 (default: the file's default track)")
+		langFlag       = flag.String("audio-lang", "", "Language tag of the audio track to transcribe, e.g. eng")
+		startFlag      = flag.String("start", "", "Start of the range to transcribe (e.g. 90, 1:30 or 1m30s)")
+		endFlag        = flag.String("end", "", "End of the range to transcribe (default: end of the file)")
+		noCacheFlag    = flag.Bool("no-cache", false, "Do not reuse or store cached audio and transcripts")
+		profileFlag    = flag.String("profile", "", "Audio preprocessing profile (default: AUDIO_PROFILE): "+strings.Join(transcribe.ProfileNames(), ", "))
+		languageFlag   = flag.String("language", "", "Language code of the speech, e.g. en_us or de (default: TRANSCRIBE_LANGUAGE)")
+		detectFlag     = flag.Bool("detect-language", false, "Detect the spoken language automatically")
+		modelFlag      = flag.String("speech-model", "", "AssemblyAI speech model: "+strings.Join(transcribe.SpeechModels, ", "))
+		wordBoostFlag  = flag.String("word-boost", "", "Comma-separated words and phrases that are likely to be spoken")
+		boostFlag      = flag.String("boost-param", "", "Weight of the word boost: "+strings.Join(transcribe.BoostParams, ", "))
+		glossaryFlag   = flag.Bool("glossary-boost", false, "Add the stored glossary terms to the word boost")
+		punctuateFlag  = flag.Bool("punctuate", true, "Add punctuation")
+		formatFlag     = flag.Bool("format-text", true, "Format text with casing and numbers")
+		profanityFlag  = flag.Bool("filter-profanity", false, "Mask profanity in the transcript")
+		asyncFlag      = flag.Bool("async", false, "Submit the video for transcription and exit without waiting; collect it later with --poll")
+		pollFlag       = flag.Bool("poll", false, "Save finished transcripts of videos submitted with --async")
 	)
 	flag.Parse()
 
-	if (*transcriptFlag == "" && *videoFlag == "") || *dbPathFlag == "" {
+	if (*transcriptFlag == "" && *videoFlag == "" && *dirFlag == "" && !*pollFlag) || *dbPathFlag == "" {
 		lgr.Printf("Usage:")
 		lgr.Printf("  For text transcripts: savetodb --transcript=file --db=database.db")
-		lgr.Printf("  For video files: savetodb --video=file --db=database.db")
+		lgr.Printf("  For video and audio files: savetodb --video=file --db=database.db [--audio-track=N|--audio-lang=eng] [--start=1:30] [--end=5:00]")
+		lgr.Printf("  For directories: savetodb --dir=videos --db=database.db [--recursive] [--workers=N]")
+		lgr.Printf("  Without waiting: savetodb --video=file --db=database.db --async, later savetodb --db=database.db --poll")
 		flag.PrintDefaults()
 		return 1
 	}
+	if *asyncFlag && *videoFlag == "" {
+		lgr.Printf("Error: --async requires --video")
+		return 1
+	}
+
+	selection, err := parseSelection(*trackFlag, *langFlag, *startFlag, *endFlag)
+	if err != nil {
+		lgr.Printf("Error: %v", err)
+		return 1
+	}
 
 	cfg, err := config.Load()
 	if err != nil {
 		lgr.Printf("Error loading config: %v", err)
 		return 1
 	}
+	if *profileFlag != "" {
+		cfg.AudioProfile = *profileFlag
+	}
+	if *noCacheFlag {
+		cfg.CacheDir = ""
+	}
+	if _, err := transcribe.LookupProfile(cfg.AudioProfile); err != nil {
+		lgr.Printf("Error: %v", err)
+		return 1
+	}
+
+	// transcription options given as flags override the config
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "language":
+			cfg.TranscribeLanguage = *languageFlag
+		case "detect-language":
+			cfg.TranscribeLanguageDetection = *detectFlag
+		case "speech-model":
+			cfg.TranscribeSpeechModel = *modelFlag
+		case "word-boost":
+			cfg.TranscribeWordBoost = config.SplitList(*wordBoostFlag)
+		case "boost-param":
+			cfg.TranscribeBoostParam = *boostFlag
+		case "glossary-boost":
+			cfg.TranscribeGlossaryBoost = *glossaryFlag
+		case "punctuate":
+			cfg.TranscribePunctuate = *punctuateFlag
+		case "format-text":
+			cfg.TranscribeFormatText = *formatFlag
+		case "filter-profanity":
+			cfg.TranscribeFilterProfanity = *profanityFlag
+		}
+	})
+	if err := transcribe.OptionsFromConfig(cfg).Validate(); err != nil {
+		lgr.Printf("Error: %v", err)
+		return 1
+	}
+
+	// a progress bar is only drawn for a single file on a terminal, parallel batch
+	// jobs would overwrite each other's line
+	var bar *progress.Bar
+	if *dirFlag == "" && progress.IsTerminal(os.Stderr) {
+		bar = progress.NewBar(os.Stderr)
+	}
 
 	service := savetodb.NewService(
 		func() (*config.Config, error) { return cfg, nil },
@@ -43,15 +128,63 @@ func run() int {
 			return database.New(path)
 		},
 		func(apiKey string) interfaces.Transcriber {
-			return transcribe.New(apiKey, cfg.MaxAudioFileSizeMB)
+			client := transcribe.NewFromConfig(apiKey, cfg)
+			client.SetSelection(selection)
+			if bar != nil {
+				client.SetProgress(bar.Update)
+			}
+			return client
 		},
 		os.ReadFile,
 	)
-	id, err := service.SaveTranscript(context.Background(), savetodb.SaveTranscriptOptions{
+
+	// cancel on Ctrl-C so that running jobs stop and remove their temporary files
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if *pollFlag {
+		return runPoll(ctx, service, *dbPathFlag)
+	}
+
+	if *asyncFlag {
+		res, err := service.SubmitTranscript(ctx, savetodb.SaveTranscriptOptions{
+			VideoPath:    *videoFlag,
+			DatabasePath: *dbPathFlag,
+		})
+		if bar != nil {
+			bar.Finish()
+		}
+		if err != nil {
+			lgr.Printf("Error: %v", err)
+			return 1
+		}
+		if res.TranscriptionID != 0 {
+			lgr.Printf("Transcript found in cache, saved to database with ID: %d", res.TranscriptionID)
+			return 0
+		}
+		lgr.Printf("Submitted for transcription as pending job %d, run with --poll to collect it", res.PendingID)
+		return 0
+	}
+
+	if *dirFlag != "" {
+		return runBatch(ctx, service, savetodb.BatchOptions{
+			Dir:          *dirFlag,
+			Patterns:     config.SplitList(*patternFlag),
+			Extensions:   config.SplitList(*extFlag),
+			Recursive:    *recursiveFlag,
+			Workers:      *workersFlag,
+			DatabasePath: *dbPathFlag,
+		})
+	}
+
+	id, err := service.SaveTranscript(ctx, savetodb.SaveTranscriptOptions{
 		TranscriptPath: *transcriptFlag,
 		VideoPath:      *videoFlag,
 		DatabasePath:   *dbPathFlag,
 	})
+	if bar != nil {
+		bar.Finish()
+	}
 	if err != nil {
 		lgr.Printf("Error: %v", err)
 		return 1
@@ -61,6 +194,81 @@ func run() int {
 	return 0
 }
 
+// runBatch processes a directory and returns a non-zero exit code if any file failed
+func runBatch(ctx context.Context, service *savetodb.Service, opts savetodb.BatchOptions) int {
+	summary, err := service.SaveDirectory(ctx, opts, func(res savetodb.FileResult) {
+		if res.Err != nil {
+			lgr.Printf("[WARN] FAILED %s (%s): %v", res.Path, res.Duration.Round(time.Second), res.Err)
+			return
+		}
+		lgr.Printf("OK %s -> ID %d (%s)", res.Path, res.ID, res.Duration.Round(time.Second))
+	})
+	if err != nil {
+		lgr.Printf("Error: %v", err)
+		return 1
+	}
+
+	lgr.Printf("Batch complete: %d succeeded, %d failed, %d total",
+		summary.Succeeded, summary.Failed, summary.Succeeded+summary.Failed)
+	if summary.Failed > 0 {
+		return 2
+	}
+	return 0
+}
+
+// runPoll collects finished asynchronous transcriptions and returns a non-zero exit
+// code if any of them failed
+func runPoll(ctx context.Context, service *savetodb.Service, dbPath string) int {
+	var saved, waiting, failed int
+	_, err := service.CollectPending(ctx, dbPath, func(res savetodb.PollResult) {
+		switch {
+		case res.Failed:
+			failed++
+			lgr.Printf("[WARN] FAILED %s (job %d): %v", res.FileName, res.PendingID, res.Err)
+		case res.Collected:
+			lgr.Printf("Already collected %s (job %d) by another process", res.FileName, res.PendingID)
+		case res.Err != nil:
+			waiting++
+			lgr.Printf("[WARN] Could not check %s (job %d), will retry: %v", res.FileName, res.PendingID, res.Err)
+		case res.TranscriptionID != 0:
+			saved++
+			lgr.Printf("OK %s -> ID %d", res.FileName, res.TranscriptionID)
+		default:
+			waiting++
+			lgr.Printf("Still processing %s (job %d)", res.FileName, res.PendingID)
+		}
+	})
+	if err != nil {
+		lgr.Printf("Error: %v", err)
+		return 1
+	}
+
+	lgr.Printf("Poll complete: %d saved, %d pending, %d failed", saved, waiting, failed)
+	if failed > 0 {
+		return 2
+	}
+	return 0
+}
+
+// parseSelection builds the audio track and time range selection from flag values
+func parseSelection(track int, lang, start, end string) (transcribe.Selection, error) {
+	sel := transcribe.Selection{Track: track, Language: lang}
+	if track < 0 {
+		return sel, fmt.Errorf("--audio-track must be positive")
+	}
+	var err error
+	if sel.Start, err = transcribe.ParseTimestamp(start); err != nil {
+		return sel, fmt.Errorf("--start: %w", err)
+	}
+	if sel.End, err = transcribe.ParseTimestamp(end); err != nil {
+		return sel, fmt.Errorf("--end: %w", err)
+	}
+	if sel.End > 0 && sel.Start >= sel.End {
+		return sel, fmt.Errorf("--start must be before --end")
+	}
+	return sel, nil
+}
+
 func main() {
 	os.Exit(run())
 }
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-pkgz/lgr"
+
+	"assemblyai-transcriber/internal/config"
+	"assemblyai-transcriber/internal/database"
+	"assemblyai-transcriber/internal/export"
+)
+
+func main() {
+	lgr.Setup()
+	dbPathFlag := flag.String("db", "", "Path to database file or postgres:// URL (default: DATABASE_URL or DATABASE_PATH)")
+	limitFlag := flag.Int("limit", 20, "Maximum number of results (0: all)")
+	flag.Parse()
+
+	query := strings.Join(flag.Args(), " ")
+	if strings.TrimSpace(query) == "" {
+		lgr.Printf(`Usage: search [--limit=20] rate limits | "rate limit" | limit*`)
+		flag.PrintDefaults()
+		os.Exit(1)
+	}
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		lgr.Fatalf("Error loading configuration: %v", err)
+	}
+	dbPath := cfg.DatabaseDSN()
+	if *dbPathFlag != "" {
+		dbPath = *dbPathFlag
+	}
+
+	// Initialize database
+	db, err := database.New(dbPath)
+	if err != nil {
+		lgr.Fatalf("Error opening database: %v", err)
+	}
+	defer db.Close()
+
+	results, err := db.Search(query, *limitFlag)
+	if err != nil {
+		lgr.Fatalf("Error: %v", err)
+	}
+	if len(results) == 0 {
+		fmt.Println("No matches")
+		return
+	}
+
+	for _, r := range results {
+		location := r.Source
+		if r.Start >= 0 {
+			location = fmt.Sprintf("%s at %s", r.Source, export.FormatDuration(r.Start))
+		}
+		fmt.Printf("#%d %s, %s\n    %s\n", r.TranscriptionID, r.FileName, location, strings.TrimSpace(r.Snippet))
+	}
+}
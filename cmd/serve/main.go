@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/go-pkgz/lgr"
+
+	"assemblyai-transcriber/internal/config"
+	"assemblyai-transcriber/internal/database"
+	"assemblyai-transcriber/internal/interfaces"
+	"assemblyai-transcriber/internal/openrouter"
+	"assemblyai-transcriber/internal/savetodb"
+	"assemblyai-transcriber/internal/server"
+	"assemblyai-transcriber/internal/terms"
+	"assemblyai-transcriber/internal/transcribe"
+	"assemblyai-transcriber/internal/translation"
+)
+
+func run() int {
+	lgr.Setup()
+	var (
+		addrFlag      = flag.String("addr", "127.0.0.1:8080", "Address to listen on")
+		mediaDirFlag  = flag.String("media-dir", "", "Directory with media files that may be submitted by path (disabled if empty)")
+		uploadDirFlag = flag.String("upload-dir", "", "Directory for uploaded files while they are processed (default: system temp)")
+		exportDirFlag = flag.String("export-dir", "./translations", "Output directory for export jobs")
+		maxUploadFlag = flag.Int("max-upload-mb", 2048, "Maximum upload size in MB")
+		termsFlag     = flag.String("terms", string(terms.PolicyAcceptAll), "Term policy for translation jobs: accept or reject")
+	)
+	flag.Parse()
+
+	policy, err := terms.ParsePolicy(*termsFlag)
+	if err != nil || policy == terms.PolicyInteractive {
+		lgr.Printf("Error: --terms must be accept or reject, API jobs run unattended")
+		return 1
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		lgr.Printf("Error loading config: %v", err)
+		return 1
+	}
+	if _, err := transcribe.LookupProfile(cfg.AudioProfile); err != nil {
+		lgr.Printf("Error: %v", err)
+		return 1
+	}
+	if err := transcribe.OptionsFromConfig(cfg).Validate(); err != nil {
+		lgr.Printf("Error: %v", err)
+		return 1
+	}
+
+	db, err := database.New(cfg.DatabaseDSN())
+	if err != nil {
+		lgr.Printf("Error initializing database: %v", err)
+		return 1
+	}
+	defer db.Close()
+
+	saveService := savetodb.NewService(
+		func() (*config.Config, error) { return cfg, nil },
+		func(path string) (interfaces.Database, error) {
+			return database.New(path)
+		},
+		func(apiKey string) interfaces.Transcriber {
+			return transcribe.NewFromConfig(apiKey, cfg)
+		},
+		os.ReadFile,
+	)
+
+	api := server.New(db,
+		func(ctx context.Context, path string) (int64, error) {
+			return saveService.SaveTranscript(ctx, savetodb.SaveTranscriptOptions{
+				VideoPath:    path,
+				DatabasePath: cfg.DatabaseDSN(),
+			})
+		},
+		func(_ context.Context, id int64) error {
+			service := translation.New(db, openrouter.New(cfg.OpenRouterAPIKey))
+			service.SetTermPolicy(policy)
+			service.SetTargetLanguage(cfg.TranslationTargetLanguage)
+			if err := service.ProcessTranscription(id); !errors.Is(err, translation.ErrSameLanguage) {
+				return err
+			}
+			lgr.Printf("Skipped translation of transcription %d, it is already in the target language", id)
+			return nil
+		},
+		server.Options{
+			UploadDir:   *uploadDirFlag,
+			MediaDir:    *mediaDirFlag,
+			ExportDir:   *exportDirFlag,
+			MaxUploadMB: *maxUploadFlag,
+		},
+	)
+	defer api.Close()
+
+	httpServer := &http.Server{
+		Addr:              *addrFlag,
+		Handler:           api.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			lgr.Printf("[WARN] Error shutting down server: %v", err)
+		}
+	}()
+
+	lgr.Printf("Listening on %s", *addrFlag)
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		lgr.Printf("Error: %v", err)
+		return 1
+	}
+
+	lgr.Printf("Server stopped")
+	return 0
+}
+
+func main() {
+	os.Exit(run())
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/go-pkgz/lgr"
+
+	"assemblyai-transcriber/internal/config"
+	"assemblyai-transcriber/internal/database"
+	"assemblyai-transcriber/internal/transcript"
+)
+
+func run() int {
+	lgr.Setup()
+	if len(os.Args) < 2 {
+		usage()
+		return 1
+	}
+
+	fs := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	idFlag := fs.Int64("id", 0, "Transcription ID")
+	dbPathFlag := fs.String("db", "", "Path to database file or postgres:// URL (default: DATABASE_URL or DATABASE_PATH)")
+	textFlag := fs.Bool("text", false, "Edit the whole text instead of segments; drops the segment timings")
+	diffFlag := fs.Bool("diff", false, "Show the diff of every revision")
+	switch os.Args[1] {
+	case "edit", "log":
+		_ = fs.Parse(os.Args[2:])
+	default:
+		usage()
+		return 1
+	}
+	if *idFlag == 0 {
+		usage()
+		return 1
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		lgr.Printf("Error loading config: %v", err)
+		return 1
+	}
+	dbPath := cfg.DatabaseDSN()
+	if *dbPathFlag != "" {
+		dbPath = *dbPathFlag
+	}
+
+	db, err := database.New(dbPath)
+	if err != nil {
+		lgr.Printf("Error opening database: %v", err)
+		return 1
+	}
+	defer db.Close()
+
+	if os.Args[1] == "log" {
+		return history(db, *idFlag, *diffFlag)
+	}
+	return edit(db, *idFlag, *textFlag)
+}
+
+// edit opens a transcription in the text editor and saves the corrections
+func edit(db *database.DB, id int64, wholeText bool) int {
+	rev, err := transcript.New(db).Edit(id, wholeText)
+	if errors.Is(err, transcript.ErrUnchanged) {
+		lgr.Printf("No changes to transcription %d", id)
+		return 0
+	}
+	if err != nil {
+		lgr.Printf("Error: %v", err)
+		return 1
+	}
+
+	fmt.Println(rev.Diff)
+	lgr.Printf("Saved revision %d of transcription %d; its translations are stale, run translate --stale to update them",
+		rev.Revision, id)
+	return 0
+}
+
+// history prints the revisions of a transcription, oldest first
+func history(db *database.DB, id int64, withDiff bool) int {
+	revisions, err := db.ListRevisions(id)
+	if err != nil {
+		lgr.Printf("Error: %v", err)
+		return 1
+	}
+	if len(revisions) == 0 {
+		fmt.Printf("Transcription %d has not been edited\n", id)
+		return 0
+	}
+
+	for _, rev := range revisions {
+		label := "edited"
+		if rev.Revision == 1 {
+			label = "original"
+		}
+		fmt.Printf("revision %d  %s  %s\n", rev.Revision, rev.CreatedAt.Local().Format(time.DateTime), label)
+		if withDiff && rev.Diff != "" {
+			fmt.Printf("%s\n\n", rev.Diff)
+		}
+	}
+	return 0
+}
+
+func usage() {
+	lgr.Printf("Usage:")
+	lgr.Printf("  transcript edit --id=N [--text] [--db=path]")
+	lgr.Printf("  transcript log --id=N [--diff] [--db=path]")
+}
+
+func main() {
+	os.Exit(run())
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"os"
 
@@ -8,6 +9,7 @@ import (
 
 	"assemblyai-transcriber/internal/config"
 	"assemblyai-transcriber/internal/database"
+	"assemblyai-transcriber/internal/interfaces"
 	"assemblyai-transcriber/internal/openrouter"
 	"assemblyai-transcriber/internal/translation"
 )
@@ -16,13 +18,14 @@ func main() {
 	lgr.Setup()
 	// Parse command line arguments
 	idFlag := flag.Int64("id", 0, "Transcription ID to translate")
-	langFlag := flag.String("lang", "ru", "Target language (e.g. 'ru')")
+	langFlag := flag.String("lang", "", "Target language code, e.g. 'ru' (default: TRANSLATION_TARGET_LANGUAGE)")
 	allFlag := flag.Bool("all", false, "Translate all untranslated transcriptions")
+	staleFlag := flag.Bool("stale", false, "Retranslate transcriptions edited since their last translation")
 	flag.Parse()
 
 	// Validate arguments
-	if *idFlag == 0 && !*allFlag {
-		lgr.Printf("Must specify either --id or --all")
+	if *idFlag == 0 && !*allFlag && !*staleFlag {
+		lgr.Printf("Must specify either --id, --all or --stale")
 		flag.Usage()
 		os.Exit(1)
 	}
@@ -34,7 +37,7 @@ func main() {
 	}
 
 	// Initialize database
-	db, err := database.New(cfg.DatabasePath)
+	db, err := database.New(cfg.DatabaseDSN())
 	if err != nil {
 		lgr.Fatalf("Error initializing database: %v", err)
 	}
@@ -45,12 +48,53 @@ func main() {
 
 	// Create translation service
 	translationService := translation.New(db, openrouterClient)
+	if *langFlag == "" {
+		*langFlag = cfg.TranslationTargetLanguage
+	}
+	translationService.SetTargetLanguage(*langFlag)
 
 	// Execute translation based on flags
 	if *idFlag > 0 {
 		translateSingle(*idFlag, *langFlag, translationService)
+	} else if *staleFlag {
+		translateStale(db, translationService)
 	} else if *allFlag {
-		translateAll(*langFlag, translationService)
+		translateAll(db, *langFlag, translationService)
+	}
+}
+
+// translateStale retranslates transcriptions whose latest translation predates an edit
+func translateStale(repo interfaces.Repository, service *translation.Service) {
+	ids, err := repo.ListStaleTranslations()
+	if err != nil {
+		lgr.Fatalf("Error: %v", err)
+	}
+	if len(ids) == 0 {
+		lgr.Printf("No stale translations")
+		return
+	}
+
+	translateBatch(ids, "Retranslating", service)
+	lgr.Printf("Retranslated %d transcriptions", len(ids))
+}
+
+// translateBatch translates the transcriptions one after another and exits with an error
+// when any of them failed
+func translateBatch(ids []int64, action string, service *translation.Service) {
+	var failed int
+	for _, id := range ids {
+		lgr.Printf("%s transcription ID %d...", action, id)
+		err := service.ProcessTranscription(id)
+		switch {
+		case errors.Is(err, translation.ErrSameLanguage):
+			lgr.Printf("Nothing to translate for ID %d: %v", id, err)
+		case err != nil:
+			lgr.Printf("Translation error for ID %d: %v", id, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		lgr.Fatalf("%d of %d translations failed", failed, len(ids))
 	}
 }
 
@@ -59,6 +103,10 @@ func translateSingle(id int64, lang string, service *translation.Service) {
 	lgr.Printf("Translating transcription ID %d to %s...", id, lang)
 
 	err := service.ProcessTranscription(id)
+	if errors.Is(err, translation.ErrSameLanguage) {
+		lgr.Printf("Nothing to translate: %v", err)
+		return
+	}
 	if err != nil {
 		lgr.Fatalf("Translation error: %v", err)
 	}
@@ -67,9 +115,24 @@ func translateSingle(id int64, lang string, service *translation.Service) {
 }
 
 // translateAll translates all untranslated transcriptions
-func translateAll(lang string, _ *translation.Service) {
+func translateAll(repo interfaces.Repository, lang string, service *translation.Service) {
 	lgr.Printf("Finding untranslated transcriptions for %s translation...", lang)
 
-	// TODO: Implement logic to find and translate all untranslated transcriptions
-	lgr.Printf("Batch translation functionality will be implemented in next version")
+	items, err := repo.ListTranscriptions()
+	if err != nil {
+		lgr.Fatalf("Error: %v", err)
+	}
+	var ids []int64
+	for _, item := range items {
+		if !item.HasTranslation {
+			ids = append(ids, item.ID)
+		}
+	}
+	if len(ids) == 0 {
+		lgr.Printf("No untranslated transcriptions")
+		return
+	}
+
+	translateBatch(ids, "Translating", service)
+	lgr.Printf("Translated %d transcriptions", len(ids))
 }
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/go-pkgz/lgr"
+
+	"assemblyai-transcriber/internal/config"
+	"assemblyai-transcriber/internal/database"
+	"assemblyai-transcriber/internal/interfaces"
+	"assemblyai-transcriber/internal/openrouter"
+	"assemblyai-transcriber/internal/savetodb"
+	"assemblyai-transcriber/internal/terms"
+	"assemblyai-transcriber/internal/transcribe"
+	"assemblyai-transcriber/internal/translation"
+	"assemblyai-transcriber/internal/watch"
+)
+
+func run() int {
+	lgr.Setup()
+	var (
+		dirFlag       = flag.String("dir", "", "Directory to watch for new media files")
+		dbPathFlag    = flag.String("db", "", "Path to database file or postgres:// URL (default: DATABASE_URL or DATABASE_PATH)")
+		extFlag       = flag.String("ext", "", "Comma-separated file extensions to pick up (default: common video and audio formats)")
+		intervalFlag  = flag.Duration("interval", 10*time.Second, "Polling interval")
+		stableFlag    = flag.Int("stable", 2, "Number of polls with unchanged size before a file is processed")
+		termsFlag     = flag.String("terms", string(terms.PolicyAcceptAll), "Term policy for translation: accept or reject")
+		translateFlag = flag.Bool("translate", true, "Translate transcriptions after saving them")
+		profileFlag   = flag.String("profile", "", "Audio preprocessing profile (default: AUDIO_PROFILE): "+strings.Join(transcribe.ProfileNames(), ", "))
+		asyncFlag     = flag.Bool("async", false, "Submit files without waiting for transcription and collect finished transcripts on each poll")
+	)
+	flag.Parse()
+
+	if *dirFlag == "" {
+		lgr.Printf("Usage: watch --dir=incoming [--db=database.db] [--terms=accept|reject] [--translate=false] [--async]")
+		flag.PrintDefaults()
+		return 1
+	}
+
+	policy, err := terms.ParsePolicy(*termsFlag)
+	if err != nil || policy == terms.PolicyInteractive {
+		lgr.Printf("Error: --terms must be accept or reject, the watcher runs unattended")
+		return 1
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		lgr.Printf("Error loading config: %v", err)
+		return 1
+	}
+	if *profileFlag != "" {
+		cfg.AudioProfile = *profileFlag
+	}
+	if _, err := transcribe.LookupProfile(cfg.AudioProfile); err != nil {
+		lgr.Printf("Error: %v", err)
+		return 1
+	}
+	if err := transcribe.OptionsFromConfig(cfg).Validate(); err != nil {
+		lgr.Printf("Error: %v", err)
+		return 1
+	}
+	dbPath := cfg.DatabaseDSN()
+	if *dbPathFlag != "" {
+		dbPath = *dbPathFlag
+	}
+
+	service := savetodb.NewService(
+		func() (*config.Config, error) { return cfg, nil },
+		func(path string) (interfaces.Database, error) {
+			return database.New(path)
+		},
+		func(apiKey string) interfaces.Transcriber {
+			return transcribe.NewFromConfig(apiKey, cfg)
+		},
+		os.ReadFile,
+	)
+
+	// saved is called once a transcription is in the database. A failed translation only
+	// logs a warning: the file is done, and transcribing it again would pay AssemblyAI twice.
+	saved := func(id int64, path string) {
+		lgr.Printf("Saved transcription %d for %s", id, path)
+		if !*translateFlag {
+			return
+		}
+		err := translate(dbPath, cfg.OpenRouterAPIKey, cfg.TranslationTargetLanguage, policy, id)
+		switch {
+		case errors.Is(err, translation.ErrSameLanguage):
+			lgr.Printf("Skipped translation of transcription %d: %v", id, err)
+		case err != nil:
+			lgr.Printf("[WARN] Transcription %d saved, translation failed, retry with translate -id %d: %v", id, id, err)
+		default:
+			lgr.Printf("Saved translation for transcription %d", id)
+		}
+	}
+
+	// with --async, a submitted file waits in the pending directory until its job completes
+	process := func(ctx context.Context, path string) (int64, error) {
+		lgr.Printf("Processing %s", path)
+		opts := savetodb.SaveTranscriptOptions{VideoPath: path, DatabasePath: dbPath}
+		if !*asyncFlag {
+			id, err := service.SaveTranscript(ctx, opts)
+			if err != nil {
+				return 0, err
+			}
+			saved(id, path)
+			return 0, nil
+		}
+
+		res, err := service.SubmitTranscript(ctx, opts)
+		if err != nil {
+			return 0, err
+		}
+		if res.TranscriptionID != 0 {
+			saved(res.TranscriptionID, path)
+		}
+		return res.PendingID, nil
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	watcher := watch.New(watch.Options{
+		Dir:          *dirFlag,
+		Extensions:   config.SplitList(*extFlag),
+		Interval:     *intervalFlag,
+		StableChecks: *stableFlag,
+	}, process)
+
+	report := func(res watch.Result) {
+		switch {
+		case res.Err != nil:
+			lgr.Printf("[WARN] Failed %s: %v", res.Path, res.Err)
+		case res.MovedTo != "":
+			lgr.Printf("Done %s -> %s", res.Path, res.MovedTo)
+		}
+	}
+
+	if *asyncFlag {
+		// collects transcriptions submitted by this or an earlier run and moves their files
+		// out of the pending directory
+		go pollPending(ctx, service, dbPath, *intervalFlag, func(res savetodb.PollResult) {
+			switch {
+			case res.Failed:
+				report(watcher.Complete(res.PendingID, res.FileName, res.Err))
+			case res.Collected:
+				// another process saved the transcript, the file is done all the same
+				lgr.Printf("Already collected %s by another process", res.FileName)
+				report(watcher.Complete(res.PendingID, res.FileName, nil))
+			case res.Err != nil:
+				lgr.Printf("[WARN] Could not check %s, will retry: %v", res.FileName, res.Err)
+			case res.TranscriptionID != 0:
+				saved(res.TranscriptionID, res.FileName)
+				report(watcher.Complete(res.PendingID, res.FileName, nil))
+			}
+		})
+	}
+
+	lgr.Printf("Watching %s every %s", *dirFlag, *intervalFlag)
+	err = watcher.Run(ctx, func(res watch.Result) {
+		if res.Err == nil && res.JobID != 0 {
+			lgr.Printf("Submitted %s as pending job %d -> %s", res.Path, res.JobID, res.MovedTo)
+			return
+		}
+		report(res)
+	})
+	if err != nil {
+		lgr.Printf("Error: %v", err)
+		return 1
+	}
+
+	lgr.Printf("Watcher stopped")
+	return 0
+}
+
+// pollPending collects finished asynchronous transcriptions every interval until ctx is done
+func pollPending(ctx context.Context, service *savetodb.Service, dbPath string,
+	interval time.Duration, onResult func(savetodb.PollResult)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := service.CollectPending(ctx, dbPath, onResult); err != nil {
+			lgr.Printf("[WARN] Polling pending transcriptions failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// translate runs the translation workflow for a saved transcription without user interaction
+func translate(dbPath, apiKey, target string, policy terms.Policy, id int64) error {
+	db, err := database.New(dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	service := translation.New(db, openrouter.New(apiKey))
+	service.SetTermPolicy(policy)
+	service.SetTargetLanguage(target)
+	return service.ProcessTranscription(id)
+}
+
+func main() {
+	os.Exit(run())
+}
@@ -4,9 +4,12 @@ go 1.24.2
 
 require (
 	github.com/AssemblyAI/assemblyai-go-sdk v1.10.0
+	github.com/go-pkgz/lgr v0.12.0
 	github.com/jmoiron/sqlx v1.4.0
 	github.com/joho/godotenv v1.5.1
+	github.com/lib/pq v1.10.9
 	github.com/mattn/go-sqlite3 v1.14.27
+	github.com/pressly/goose/v3 v3.24.2
 	github.com/stretchr/testify v1.10.0
 )
 
@@ -14,12 +17,9 @@ require (
 	github.com/cenkalti/backoff v2.2.1+incompatible // indirect
 	github.com/coder/websocket v1.8.13 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
-	github.com/go-pkgz/lgr v0.12.0 // indirect
 	github.com/google/go-querystring v1.1.0 // indirect
-	github.com/jmoiron/sqlx v1.4.0 // indirect
 	github.com/mfridman/interpolate v0.0.2 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	github.com/pressly/goose/v3 v3.24.2 // indirect
 	github.com/sethvargo/go-retry v0.3.0 // indirect
 	go.uber.org/multierr v1.11.0 // indirect
 	golang.org/x/sync v0.12.0 // indirect
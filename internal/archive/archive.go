@@ -0,0 +1,267 @@
+// Package archive exports the library to a portable JSON or NDJSON file and imports such
+// files into another database, so libraries can be moved between backends and merged.
+package archive
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"assemblyai-transcriber/internal/database"
+)
+
+// Archive formats
+const (
+	FormatJSON   = "json"   // one document
+	FormatNDJSON = "ndjson" // a header line followed by one line per transcription or term
+)
+
+// Name identifies library archives; Version is the version of the archive layout
+const (
+	Name    = "assemblyai-transcriber-library"
+	Version = 1
+)
+
+// Store defines the database operations needed for export and import
+type Store interface {
+	ListTranscriptions() ([]database.TranscriptionListItem, error)
+	GetBundle(id int64) (*database.Bundle, error)
+	ImportBundle(b *database.Bundle) (int64, bool, error)
+	GetAllTerms() ([]map[string]string, error)
+	SaveTerm(term, description string) error
+}
+
+// Term is a glossary entry
+type Term struct {
+	Term        string `json:"term"`
+	Description string `json:"description,omitempty"`
+}
+
+// Stats counts the exported records
+type Stats struct {
+	Transcriptions int
+	Terms          int
+}
+
+// Result describes an import. IDs maps the transcription IDs of the archive to the IDs
+// in the database, also for transcriptions that were already present.
+type Result struct {
+	Imported int
+	Skipped  int // already present with the same file name and text
+	Terms    int // new glossary terms; existing terms keep their description
+	IDs      map[int64]int64
+}
+
+// document is the FormatJSON layout
+type document struct {
+	Format         string            `json:"format"`
+	Version        int               `json:"version"`
+	ExportedAt     time.Time         `json:"exported_at"`
+	Transcriptions []database.Bundle `json:"transcriptions"`
+	Terms          []Term            `json:"terms"`
+}
+
+// line is a FormatNDJSON line; the first one is the header
+type line struct {
+	Type          string           `json:"type"` // header, transcription or term
+	Format        string           `json:"format,omitempty"`
+	Version       int              `json:"version,omitempty"`
+	ExportedAt    *time.Time       `json:"exported_at,omitempty"`
+	Transcription *database.Bundle `json:"transcription,omitempty"`
+	Term          *Term            `json:"term,omitempty"`
+}
+
+// Line types of FormatNDJSON
+const (
+	lineHeader        = "header"
+	lineTranscription = "transcription"
+	lineTerm          = "term"
+)
+
+// Export writes all transcriptions with their segments, words, media metadata,
+// translations, tags and collections, and the glossary to w. NDJSON is written one
+// transcription at a time; JSON is collected in memory first.
+func Export(store Store, w io.Writer, format string) (Stats, error) {
+	var stats Stats
+	if format != FormatJSON && format != FormatNDJSON {
+		return stats, fmt.Errorf("unknown archive format %q", format)
+	}
+
+	items, err := store.ListTranscriptions()
+	if err != nil {
+		return stats, fmt.Errorf("error listing transcriptions: %w", err)
+	}
+	terms, err := loadTerms(store)
+	if err != nil {
+		return stats, err
+	}
+
+	now := time.Now().UTC()
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	doc := document{Format: Name, Version: Version, ExportedAt: now, Transcriptions: []database.Bundle{}, Terms: terms}
+	if format == FormatNDJSON {
+		if err := enc.Encode(line{Type: lineHeader, Format: Name, Version: Version, ExportedAt: &now}); err != nil {
+			return stats, fmt.Errorf("error writing archive: %w", err)
+		}
+	}
+
+	for _, item := range items {
+		b, err := store.GetBundle(item.ID)
+		if err != nil {
+			return stats, fmt.Errorf("error reading transcription %d: %w", item.ID, err)
+		}
+		if format == FormatJSON {
+			doc.Transcriptions = append(doc.Transcriptions, *b)
+		} else if err := enc.Encode(line{Type: lineTranscription, Transcription: b}); err != nil {
+			return stats, fmt.Errorf("error writing archive: %w", err)
+		}
+		stats.Transcriptions++
+	}
+	stats.Terms = len(terms)
+
+	if format == FormatJSON {
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(doc); err != nil {
+			return stats, fmt.Errorf("error writing archive: %w", err)
+		}
+	} else {
+		for i := range terms {
+			if err := enc.Encode(line{Type: lineTerm, Term: &terms[i]}); err != nil {
+				return stats, fmt.Errorf("error writing archive: %w", err)
+			}
+		}
+	}
+
+	if err := bw.Flush(); err != nil {
+		return stats, fmt.Errorf("error writing archive: %w", err)
+	}
+	return stats, nil
+}
+
+// Import reads an archive in either format and adds its transcriptions under new IDs and
+// its glossary terms to the store. Every transcription is saved in a transaction of its
+// own; after a failure the import can be repeated, as transcriptions that are already
+// present are skipped.
+func Import(store Store, r io.Reader) (*Result, error) {
+	result := &Result{IDs: map[int64]int64{}}
+	existing, err := loadTerms(store)
+	if err != nil {
+		return result, err
+	}
+	known := make(map[string]bool, len(existing))
+	for _, t := range existing {
+		known[t.Term] = true
+	}
+
+	addBundle := func(b *database.Bundle) error {
+		id, imported, err := store.ImportBundle(b)
+		if err != nil {
+			return fmt.Errorf("error importing transcription %d (%s): %w", b.ID, b.FileName, err)
+		}
+		result.IDs[b.ID] = id
+		if imported {
+			result.Imported++
+		} else {
+			result.Skipped++
+		}
+		return nil
+	}
+	addTerm := func(t *Term) error {
+		if t.Term == "" || known[t.Term] {
+			return nil
+		}
+		if err := store.SaveTerm(t.Term, t.Description); err != nil {
+			return fmt.Errorf("error importing term %q: %w", t.Term, err)
+		}
+		known[t.Term] = true
+		result.Terms++
+		return nil
+	}
+
+	dec := json.NewDecoder(bufio.NewReader(r))
+	var first json.RawMessage
+	if err := dec.Decode(&first); err != nil {
+		return result, fmt.Errorf("error reading archive: %w", err)
+	}
+	var header line
+	if err := json.Unmarshal(first, &header); err != nil {
+		return result, fmt.Errorf("error reading archive: %w", err)
+	}
+
+	if header.Type == "" {
+		var doc document
+		if err := json.Unmarshal(first, &doc); err != nil {
+			return result, fmt.Errorf("error reading archive: %w", err)
+		}
+		if err := checkVersion(doc.Format, doc.Version); err != nil {
+			return result, err
+		}
+		for i := range doc.Transcriptions {
+			if err := addBundle(&doc.Transcriptions[i]); err != nil {
+				return result, err
+			}
+		}
+		for i := range doc.Terms {
+			if err := addTerm(&doc.Terms[i]); err != nil {
+				return result, err
+			}
+		}
+		return result, nil
+	}
+
+	if header.Type != lineHeader {
+		return result, errors.New("error reading archive: the first line is not a header")
+	}
+	if err := checkVersion(header.Format, header.Version); err != nil {
+		return result, err
+	}
+	for n := 2; ; n++ {
+		var l line
+		err := dec.Decode(&l)
+		if errors.Is(err, io.EOF) {
+			return result, nil
+		}
+		if err != nil {
+			return result, fmt.Errorf("error reading archive line %d: %w", n, err)
+		}
+		switch {
+		case l.Type == lineTranscription && l.Transcription != nil:
+			err = addBundle(l.Transcription)
+		case l.Type == lineTerm && l.Term != nil:
+			err = addTerm(l.Term)
+		default:
+			err = fmt.Errorf("error reading archive line %d: unknown record %q", n, l.Type)
+		}
+		if err != nil {
+			return result, err
+		}
+	}
+}
+
+// checkVersion rejects files that are not library archives or were written by a newer build
+func checkVersion(format string, version int) error {
+	if format != Name {
+		return fmt.Errorf("not a library archive (format %q)", format)
+	}
+	if version > Version {
+		return fmt.Errorf("archive version %d is newer than this build supports (%d)", version, Version)
+	}
+	return nil
+}
+
+// loadTerms reads the glossary
+func loadTerms(store Store) ([]Term, error) {
+	all, err := store.GetAllTerms()
+	if err != nil {
+		return nil, fmt.Errorf("error reading glossary: %w", err)
+	}
+	terms := make([]Term, 0, len(all))
+	for _, t := range all {
+		terms = append(terms, Term{Term: t["term"], Description: t["description"]})
+	}
+	return terms, nil
+}
@@ -0,0 +1,117 @@
+package archive
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"assemblyai-transcriber/internal/database"
+)
+
+func newDB(t *testing.T) *database.DB {
+	db, err := database.New(":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+// seed stores a translated transcription with segments, words, media, tags and a collection
+func seed(t *testing.T, db *database.DB, fileName, text string) int64 {
+	id, err := db.InsertTranscription(&database.Transcription{FileName: fileName, Text: text, Language: "en"})
+	require.NoError(t, err)
+	require.NoError(t, db.SaveSegments(id, []database.Segment{{Start: 0, End: 1500, Text: text, Confidence: 0.9}}))
+	require.NoError(t, db.SaveWords(id, []database.Word{{Start: 0, End: 1500, Text: text, Confidence: 0.9}}))
+	created := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, db.SaveMedia(&database.Media{TranscriptionID: id, DurationMS: 1500, Container: "mp4", MediaCreatedAt: &created}))
+	require.NoError(t, db.SaveTranslation(id, "translated "+text))
+	require.NoError(t, db.TagTranscription(id, "meetup"))
+	require.NoError(t, db.AddToCollection("2024 talks", id))
+	return id
+}
+
+func TestExportImport(t *testing.T) {
+	for _, format := range []string{FormatJSON, FormatNDJSON} {
+		t.Run(format, func(t *testing.T) {
+			src := newDB(t)
+			seed(t, src, "a.mp4", "first talk")
+			second := seed(t, src, "b.mp4", "second talk")
+			require.NoError(t, src.SaveTerm("API", "interface"))
+
+			var buf bytes.Buffer
+			stats, err := Export(src, &buf, format)
+			require.NoError(t, err)
+			require.Equal(t, Stats{Transcriptions: 2, Terms: 1}, stats)
+			if format == FormatNDJSON {
+				require.Len(t, strings.Split(strings.TrimSpace(buf.String()), "\n"), 4)
+			}
+
+			// the target already holds a transcription, so the imported ones get new IDs
+			dst := newDB(t)
+			_, err = dst.InsertTranscription(&database.Transcription{FileName: "own.mp4", Text: "own talk"})
+			require.NoError(t, err)
+			require.NoError(t, dst.SaveTerm("API", "kept description"))
+
+			archive := buf.String()
+			result, err := Import(dst, strings.NewReader(archive))
+			require.NoError(t, err)
+			require.Equal(t, 2, result.Imported)
+			require.Equal(t, 0, result.Skipped)
+			require.Equal(t, 0, result.Terms)
+			require.Equal(t, map[int64]int64{1: 2, second: 3}, result.IDs)
+
+			b, err := dst.GetBundle(3)
+			require.NoError(t, err)
+			require.Equal(t, "b.mp4", b.FileName)
+			require.Equal(t, "second talk", b.Text)
+			require.Equal(t, "en", b.Language)
+			require.Len(t, b.Segments, 1)
+			require.Len(t, b.Words, 1)
+			require.NotNil(t, b.Media)
+			require.Equal(t, int64(1500), b.Media.DurationMS)
+			require.Len(t, b.Translations, 1)
+			require.Equal(t, "translated second talk", b.Translations[0].TranslatedText)
+			require.Equal(t, []string{"meetup"}, b.Tags)
+			require.Equal(t, []string{"2024 talks"}, b.Collections)
+
+			orig, err := src.GetBundle(second)
+			require.NoError(t, err)
+			require.True(t, orig.CreatedAt.Equal(b.CreatedAt), "creation time is kept")
+
+			terms, err := dst.GetAllTerms()
+			require.NoError(t, err)
+			require.Equal(t, []map[string]string{{"term": "API", "description": "kept description"}}, terms)
+
+			// importing again merges nothing twice
+			result, err = Import(dst, strings.NewReader(archive))
+			require.NoError(t, err)
+			require.Equal(t, 0, result.Imported)
+			require.Equal(t, 2, result.Skipped)
+			require.Equal(t, map[int64]int64{1: 2, second: 3}, result.IDs)
+			items, err := dst.ListTranscriptions()
+			require.NoError(t, err)
+			require.Len(t, items, 3)
+		})
+	}
+}
+
+func TestImport_Invalid(t *testing.T) {
+	db := newDB(t)
+
+	_, err := Import(db, strings.NewReader(`{"format":"other","version":1}`))
+	require.ErrorContains(t, err, "not a library archive")
+
+	_, err = Import(db, strings.NewReader(`{"type":"header","format":"`+Name+`","version":99}`))
+	require.ErrorContains(t, err, "newer than this build")
+
+	_, err = Import(db, strings.NewReader(`{"type":"term","term":{"term":"API"}}`))
+	require.ErrorContains(t, err, "not a header")
+
+	_, err = Import(db, strings.NewReader(`{"type":"header","format":"`+Name+`","version":1}`+"\n"+`{"type":"bogus"}`))
+	require.ErrorContains(t, err, `line 2: unknown record "bogus"`)
+
+	_, err = Export(db, &bytes.Buffer{}, "xml")
+	require.ErrorContains(t, err, "unknown archive format")
+}
@@ -0,0 +1,205 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Kinds of cached entries
+const (
+	KindAudio      = "audio"
+	KindTranscript = "transcript"
+)
+
+// Cache stores extracted audio and transcripts on disk under content-derived keys.
+// Every entry is a directory; its modification time marks when it was last used.
+type Cache struct {
+	dir      string
+	maxBytes int64
+}
+
+// Entry describes a cached entry
+type Entry struct {
+	Kind     string
+	Key      string
+	Path     string
+	Size     int64
+	LastUsed time.Time
+}
+
+// New creates a cache in dir. When maxBytes is positive, least recently used entries
+// are evicted after each insert to keep the total size under it.
+func New(dir string, maxBytes int64) *Cache {
+	return &Cache{dir: dir, maxBytes: maxBytes}
+}
+
+// Key derives a cache key from the given parts
+func Key(parts ...string) string {
+	hash := sha256.New()
+	for _, p := range parts {
+		hash.Write([]byte(p))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// Get returns the directory of a cached entry and marks it as recently used
+func (c *Cache) Get(kind, key string) (string, bool) {
+	path := c.entryPath(kind, key)
+	info, err := os.Stat(path)
+	if err != nil || !info.IsDir() {
+		return "", false
+	}
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	return path, true
+}
+
+// Put stores an entry. fill writes the entry files into a temporary directory that
+// replaces any existing entry once fill succeeds. It returns the entry directory.
+func (c *Cache) Put(kind, key string, fill func(dir string) error) (string, error) {
+	kindDir := filepath.Join(c.dir, kind)
+	if err := os.MkdirAll(kindDir, 0o750); err != nil {
+		return "", fmt.Errorf("create cache directory: %w", err)
+	}
+
+	tmp, err := os.MkdirTemp(kindDir, ".tmp-*")
+	if err != nil {
+		return "", fmt.Errorf("create cache entry: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	if err := fill(tmp); err != nil {
+		return "", err
+	}
+
+	path := c.entryPath(kind, key)
+	if err := os.RemoveAll(path); err != nil {
+		return "", fmt.Errorf("replace cache entry: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return "", fmt.Errorf("store cache entry: %w", err)
+	}
+
+	if c.maxBytes > 0 {
+		if _, err := c.prune(0, c.maxBytes, path); err != nil {
+			return "", err
+		}
+	}
+	return path, nil
+}
+
+// List returns all entries, most recently used first
+func (c *Cache) List() ([]Entry, error) {
+	kinds, err := os.ReadDir(c.dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read cache directory: %w", err)
+	}
+
+	var entries []Entry
+	for _, kind := range kinds {
+		if !kind.IsDir() {
+			continue
+		}
+		keys, err := os.ReadDir(filepath.Join(c.dir, kind.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("read cache directory: %w", err)
+		}
+		for _, key := range keys {
+			// temporary directories of inserts in progress
+			if !key.IsDir() || strings.HasPrefix(key.Name(), ".") {
+				continue
+			}
+			entry, err := c.entry(kind.Name(), key.Name())
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, entry)
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].LastUsed.After(entries[j].LastUsed) })
+	return entries, nil
+}
+
+// Prune removes entries not used for longer than maxAge and then least recently used
+// entries until the total size is at most maxBytes. Zero values disable either limit.
+// It returns the removed entries.
+func (c *Cache) Prune(maxAge time.Duration, maxBytes int64) ([]Entry, error) {
+	return c.prune(maxAge, maxBytes, "")
+}
+
+// prune implements Prune, never removing the entry at keep
+func (c *Cache) prune(maxAge time.Duration, maxBytes int64, keep string) ([]Entry, error) {
+	entries, err := c.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var total int64
+	for _, e := range entries {
+		total += e.Size
+	}
+
+	var removed []Entry
+	// entries are sorted by last use, so the oldest are at the end
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		expired := maxAge > 0 && time.Since(e.LastUsed) > maxAge
+		oversized := maxBytes > 0 && total > maxBytes
+		if e.Path == keep || (!expired && !oversized) {
+			continue
+		}
+		if err := os.RemoveAll(e.Path); err != nil {
+			return removed, fmt.Errorf("remove cache entry: %w", err)
+		}
+		total -= e.Size
+		removed = append(removed, e)
+	}
+	return removed, nil
+}
+
+// entry reads the size and last use time of an entry
+func (c *Cache) entry(kind, key string) (Entry, error) {
+	path := c.entryPath(kind, key)
+	info, err := os.Stat(path)
+	if err != nil {
+		return Entry{}, fmt.Errorf("stat cache entry: %w", err)
+	}
+
+	var size int64
+	err = filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			fi, err := d.Info()
+			if err != nil {
+				return err
+			}
+			size += fi.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return Entry{}, fmt.Errorf("measure cache entry: %w", err)
+	}
+
+	return Entry{Kind: kind, Key: key, Path: path, Size: size, LastUsed: info.ModTime()}, nil
+}
+
+// entryPath returns the directory of an entry
+func (c *Cache) entryPath(kind, key string) string {
+	return filepath.Join(c.dir, kind, key)
+}
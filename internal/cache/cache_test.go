@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// putFile stores an entry with a single file of the given size
+func putFile(t *testing.T, c *Cache, kind, key string, size int) string {
+	t.Helper()
+	path, err := c.Put(kind, key, func(dir string) error {
+		return os.WriteFile(filepath.Join(dir, "data"), make([]byte, size), 0o600)
+	})
+	require.NoError(t, err)
+	return path
+}
+
+// setLastUsed moves the last use time of an entry into the past
+func setLastUsed(t *testing.T, path string, ago time.Duration) {
+	t.Helper()
+	ts := time.Now().Add(-ago)
+	require.NoError(t, os.Chtimes(path, ts, ts))
+}
+
+func TestCache_PutGet(t *testing.T) {
+	c := New(t.TempDir(), 0)
+
+	_, ok := c.Get(KindAudio, "missing")
+	assert.False(t, ok)
+
+	path := putFile(t, c, KindAudio, "abc", 10)
+	got, ok := c.Get(KindAudio, "abc")
+	require.True(t, ok)
+	assert.Equal(t, path, got)
+	assert.FileExists(t, filepath.Join(got, "data"))
+
+	// a failed fill leaves no entry behind
+	_, err := c.Put(KindTranscript, "abc", func(string) error { return errors.New("boom") })
+	require.EqualError(t, err, "boom")
+	_, ok = c.Get(KindTranscript, "abc")
+	assert.False(t, ok)
+
+	entries, err := c.List()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, Entry{Kind: KindAudio, Key: "abc", Path: path, Size: 10, LastUsed: entries[0].LastUsed}, entries[0])
+}
+
+func TestCache_Prune(t *testing.T) {
+	c := New(t.TempDir(), 0)
+	old := putFile(t, c, KindAudio, "old", 10)
+	mid := putFile(t, c, KindAudio, "mid", 10)
+	recent := putFile(t, c, KindTranscript, "recent", 10)
+	setLastUsed(t, old, 48*time.Hour)
+	setLastUsed(t, mid, 2*time.Hour)
+	setLastUsed(t, recent, time.Minute)
+
+	removed, err := c.Prune(24*time.Hour, 0)
+	require.NoError(t, err)
+	require.Len(t, removed, 1)
+	assert.Equal(t, "old", removed[0].Key)
+
+	removed, err = c.Prune(0, 15)
+	require.NoError(t, err)
+	require.Len(t, removed, 1)
+	assert.Equal(t, "mid", removed[0].Key)
+
+	entries, err := c.List()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "recent", entries[0].Key)
+}
+
+func TestCache_SizeLimitOnPut(t *testing.T) {
+	c := New(t.TempDir(), 25)
+	first := putFile(t, c, KindAudio, "first", 10)
+	setLastUsed(t, first, time.Hour)
+	putFile(t, c, KindAudio, "second", 10)
+
+	// using the first entry makes the second one the least recently used
+	_, ok := c.Get(KindAudio, "first")
+	require.True(t, ok)
+	setLastUsed(t, filepath.Join(c.dir, KindAudio, "second"), 2*time.Hour)
+
+	putFile(t, c, KindAudio, "third", 10)
+	_, ok = c.Get(KindAudio, "second")
+	assert.False(t, ok)
+	_, ok = c.Get(KindAudio, "first")
+	assert.True(t, ok)
+
+	// an entry larger than the limit is kept until the next insert
+	big := putFile(t, c, KindAudio, "big", 100)
+	assert.DirExists(t, big)
+}
+
+func TestKey(t *testing.T) {
+	assert.Equal(t, Key("a", "b"), Key("a", "b"))
+	assert.NotEqual(t, Key("ab", ""), Key("a", "b"))
+	assert.Len(t, Key("x"), 64)
+}
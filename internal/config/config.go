@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -13,8 +14,27 @@ type Config struct {
 	AssemblyAIAPIKey   string
 	OpenRouterAPIKey   string
 	DatabasePath       string
+	DatabaseURL        string // PostgreSQL URL; takes precedence over DatabasePath
 	LogLevel           string
 	MaxAudioFileSizeMB int
+	AudioCacheDir      string
+	AudioProfile       string
+	CacheDir           string
+	CacheMaxSizeMB     int
+
+	// AssemblyAI transcription options
+	TranscribeLanguage          string
+	TranscribeLanguageDetection bool
+	TranscribeSpeechModel       string
+	TranscribeWordBoost         []string
+	TranscribeBoostParam        string
+	TranscribePunctuate         bool
+	TranscribeFormatText        bool
+	TranscribeFilterProfanity   bool
+	TranscribeGlossaryBoost     bool
+
+	// TranslationTargetLanguage is the language code transcriptions are translated to
+	TranslationTargetLanguage string
 }
 
 // Load reads the configuration from environment variables
@@ -27,8 +47,25 @@ func Load() (*Config, error) {
 		AssemblyAIAPIKey:   getEnv("ASSEMBLYAI_API_KEY", ""),
 		OpenRouterAPIKey:   getEnv("OPENROUTER_API_KEY", ""),
 		DatabasePath:       getEnv("DATABASE_PATH", "./transcriptions.db"),
+		DatabaseURL:        getEnv("DATABASE_URL", ""),
 		LogLevel:           getEnv("LOG_LEVEL", "info"),
 		MaxAudioFileSizeMB: 100,
+		AudioCacheDir:      getEnv("AUDIO_CACHE_DIR", ""),
+		AudioProfile:       getEnv("AUDIO_PROFILE", "default"),
+		CacheDir:           getEnv("CACHE_DIR", ""),
+		CacheMaxSizeMB:     2048,
+
+		TranscribeLanguage:          getEnv("TRANSCRIBE_LANGUAGE", ""),
+		TranscribeLanguageDetection: getBool("TRANSCRIBE_LANGUAGE_DETECTION", false),
+		TranscribeSpeechModel:       getEnv("TRANSCRIBE_SPEECH_MODEL", ""),
+		TranscribeWordBoost:         getList("TRANSCRIBE_WORD_BOOST"),
+		TranscribeBoostParam:        getEnv("TRANSCRIBE_BOOST_PARAM", ""),
+		TranscribePunctuate:         getBool("TRANSCRIBE_PUNCTUATE", true),
+		TranscribeFormatText:        getBool("TRANSCRIBE_FORMAT_TEXT", true),
+		TranscribeFilterProfanity:   getBool("TRANSCRIBE_FILTER_PROFANITY", false),
+		TranscribeGlossaryBoost:     getBool("TRANSCRIBE_GLOSSARY_BOOST", false),
+
+		TranslationTargetLanguage: getEnv("TRANSLATION_TARGET_LANGUAGE", "ru"),
 	}
 
 	if val := getEnv("MAX_AUDIO_FILE_SIZE_MB", ""); val != "" {
@@ -37,9 +74,15 @@ func Load() (*Config, error) {
 		}
 	}
 
+	if val := getEnv("CACHE_MAX_SIZE_MB", ""); val != "" {
+		if n, err := strconv.Atoi(val); err == nil && n >= 0 {
+			config.CacheMaxSizeMB = n
+		}
+	}
+
 	// ensure database directory exists
 	dbDir := filepath.Dir(config.DatabasePath)
-	if dbDir != "." {
+	if dbDir != "." && config.DatabaseURL == "" {
 		if err := os.MkdirAll(dbDir, 0o750); err != nil {
 			return nil, err
 		}
@@ -48,6 +91,15 @@ func Load() (*Config, error) {
 	return config, nil
 }
 
+// DatabaseDSN returns the data source of the database: DATABASE_URL when it is set,
+// the SQLite file DATABASE_PATH otherwise
+func (c *Config) DatabaseDSN() string {
+	if c.DatabaseURL != "" {
+		return c.DatabaseURL
+	}
+	return c.DatabasePath
+}
+
 // getEnv gets an environment variable or returns a default value
 func getEnv(key, defaultValue string) string {
 	value, exists := os.LookupEnv(key)
@@ -56,3 +108,29 @@ func getEnv(key, defaultValue string) string {
 	}
 	return value
 }
+
+// getBool gets a boolean environment variable; unset or invalid values yield the default
+func getBool(key string, defaultValue bool) bool {
+	b, err := strconv.ParseBool(getEnv(key, ""))
+	if err != nil {
+		return defaultValue
+	}
+	return b
+}
+
+// getList gets a comma-separated environment variable, dropping empty items
+func getList(key string) []string {
+	return SplitList(getEnv(key, ""))
+}
+
+// SplitList splits a comma-separated value such as a flag or environment variable,
+// trimming spaces and dropping empty items
+func SplitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -26,6 +26,13 @@ func TestConfig_Load(t *testing.T) {
 				DatabasePath:       "./transcriptions.db",
 				LogLevel:           "info",
 				MaxAudioFileSizeMB: 100,
+				AudioProfile:       "default",
+				CacheMaxSizeMB:     2048,
+
+				TranscribePunctuate:  true,
+				TranscribeFormatText: true,
+
+				TranslationTargetLanguage: "ru",
 			},
 		},
 		{
@@ -35,14 +42,42 @@ func TestConfig_Load(t *testing.T) {
 				os.Setenv("ASSEMBLYAI_API_KEY", "test_assemblyai")
 				os.Setenv("OPENROUTER_API_KEY", "test_openrouter")
 				os.Setenv("DATABASE_PATH", "/tmp/test.db")
+				os.Setenv("DATABASE_URL", "postgres://localhost/transcriber")
 				os.Setenv("LOG_LEVEL", "debug")
+				os.Setenv("AUDIO_CACHE_DIR", "/tmp/audio")
+				os.Setenv("AUDIO_PROFILE", "speech")
+				os.Setenv("CACHE_DIR", "/tmp/cache")
+				os.Setenv("CACHE_MAX_SIZE_MB", "0")
+				os.Setenv("TRANSCRIBE_LANGUAGE", "de")
+				os.Setenv("TRANSCRIBE_SPEECH_MODEL", "nano")
+				os.Setenv("TRANSCRIBE_WORD_BOOST", "Kubernetes, gRPC,,")
+				os.Setenv("TRANSCRIBE_BOOST_PARAM", "high")
+				os.Setenv("TRANSCRIBE_PUNCTUATE", "false")
+				os.Setenv("TRANSCRIBE_FORMAT_TEXT", "invalid")
+				os.Setenv("TRANSCRIBE_FILTER_PROFANITY", "1")
+				os.Setenv("TRANSCRIBE_GLOSSARY_BOOST", "true")
+				os.Setenv("TRANSLATION_TARGET_LANGUAGE", "de")
 			},
 			want: &Config{
 				AssemblyAIAPIKey:   "test_assemblyai",
 				OpenRouterAPIKey:   "test_openrouter",
 				DatabasePath:       "/tmp/test.db",
+				DatabaseURL:        "postgres://localhost/transcriber",
 				LogLevel:           "debug",
 				MaxAudioFileSizeMB: 100,
+				AudioCacheDir:      "/tmp/audio",
+				AudioProfile:       "speech",
+				CacheDir:           "/tmp/cache",
+
+				TranscribeLanguage:        "de",
+				TranscribeSpeechModel:     "nano",
+				TranscribeWordBoost:       []string{"Kubernetes", "gRPC"},
+				TranscribeBoostParam:      "high",
+				TranscribeFormatText:      true,
+				TranscribeFilterProfanity: true,
+				TranscribeGlossaryBoost:   true,
+
+				TranslationTargetLanguage: "de",
 			},
 		},
 	}
@@ -74,6 +109,14 @@ func TestConfig_Load(t *testing.T) {
 	}
 }
 
+func TestConfig_DatabaseDSN(t *testing.T) {
+	cfg := &Config{DatabasePath: "./transcriptions.db"}
+	require.Equal(t, "./transcriptions.db", cfg.DatabaseDSN())
+
+	cfg.DatabaseURL = "postgres://localhost/transcriber"
+	require.Equal(t, "postgres://localhost/transcriber", cfg.DatabaseDSN())
+}
+
 func TestConfig_LoadWithEnvFile(t *testing.T) {
 	// Clear env before test
 	os.Clearenv()
@@ -136,3 +179,21 @@ func Test_getEnv(t *testing.T) {
 		})
 	}
 }
+
+func TestSplitList(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  []string
+	}{
+		{name: "empty", value: "", want: nil},
+		{name: "single", value: "mp4", want: []string{"mp4"}},
+		{name: "spaces and empty items", value: " mp4, ,mkv,", want: []string{"mp4", "mkv"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, SplitList(tt.value))
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+)
+
+// ErrBackupUnsupported is returned by Backup for PostgreSQL databases
+var ErrBackupUnsupported = errors.New("backup is only supported for SQLite databases, use pg_dump for PostgreSQL")
+
+// Bundle is a transcription with everything that belongs to it, the unit of library
+// export and import. Revisions and pending submissions are not part of it.
+type Bundle struct {
+	Transcription
+	Segments     []Segment     `json:"segments,omitempty"`
+	Words        []Word        `json:"words,omitempty"`
+	Media        *Media        `json:"media,omitempty"`
+	Translations []Translation `json:"translations,omitempty"`
+	Tags         []string      `json:"tags,omitempty"`
+	Collections  []string      `json:"collections,omitempty"`
+}
+
+// Backup writes a consistent copy of a SQLite database to path with VACUUM INTO while
+// the database stays in use. The file must not exist yet.
+func (db *DB) Backup(ctx context.Context, path string) error {
+	if db.driver != DriverSQLite {
+		return ErrBackupUnsupported
+	}
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("error backing up: %s already exists", path)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("error backing up: %w", err)
+	}
+
+	if _, err := db.conn.ExecContext(ctx, "VACUUM INTO ?", path); err != nil {
+		return fmt.Errorf("error backing up to %s: %w", path, err)
+	}
+	return nil
+}
+
+// GetBundle retrieves a transcription with its segments, words, media metadata,
+// translations, tags and collections
+func (db *DB) GetBundle(id int64) (*Bundle, error) {
+	record, err := db.GetTranscriptionRecord(id)
+	if err != nil {
+		return nil, err
+	}
+	b := &Bundle{Transcription: *record}
+
+	if b.Segments, err = db.GetSegments(id); err != nil {
+		return nil, err
+	}
+	if b.Words, err = db.GetWords(id); err != nil {
+		return nil, err
+	}
+	switch media, err := db.GetMedia(id); {
+	case err == nil:
+		b.Media = media
+	case !errors.Is(err, sql.ErrNoRows):
+		return nil, err
+	}
+	if err := db.conn.Select(&b.Translations,
+		`SELECT id, transcription_id, translated_text, COALESCE(language, '') AS language,
+		COALESCE(model, '') AS model, stale, created_at FROM translations WHERE transcription_id = ? ORDER BY id`,
+		id,
+	); err != nil {
+		return nil, fmt.Errorf("error retrieving translations: %w", err)
+	}
+	if b.Tags, err = db.GetTags(id); err != nil {
+		return nil, err
+	}
+	if b.Collections, err = db.GetCollections(id); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// ImportBundle saves a bundle as a new transcription in one transaction and returns its
+// ID; the IDs stored in the bundle are ignored. A transcription with the same file name
+// and text is not imported again: its ID is returned with imported false.
+func (db *DB) ImportBundle(b *Bundle) (id int64, imported bool, err error) {
+	tx, err := db.conn.Beginx()
+	if err != nil {
+		return 0, false, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	err = tx.Get(&id, "SELECT id FROM transcriptions WHERE file_name = ? AND transcript_text = ? ORDER BY id LIMIT 1",
+		b.FileName, b.Text)
+	if err == nil {
+		return id, false, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return 0, false, fmt.Errorf("error checking for a duplicate of %s: %w", b.FileName, err)
+	}
+
+	if id, err = db.insertBundle(tx, b); err != nil {
+		return 0, false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, false, fmt.Errorf("error committing import of %s: %w", b.FileName, err)
+	}
+	return id, true, nil
+}
+
+// SaveBundle saves a bundle as a new transcription in one transaction and returns its ID,
+// so a failure leaves no transcription without its segments, words or media metadata
+func (db *DB) SaveBundle(b *Bundle) (int64, error) {
+	tx, err := db.conn.Beginx()
+	if err != nil {
+		return 0, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	id, err := db.insertBundle(tx, b)
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("error committing %s: %w", b.FileName, err)
+	}
+	return id, nil
+}
+
+// CompletePending saves the transcript of a pending transcription as a new transcription
+// and deletes the pending transcription in one transaction, so a transcript is collected
+// once. It fails with sql.ErrNoRows when the pending transcription no longer exists.
+func (db *DB) CompletePending(pendingID int64, b *Bundle) (int64, error) {
+	tx, err := db.conn.Beginx()
+	if err != nil {
+		return 0, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	result, err := tx.Exec("DELETE FROM pending_transcriptions WHERE id = ?", pendingID)
+	if err != nil {
+		return 0, fmt.Errorf("error deleting pending transcription: %w", err)
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return 0, fmt.Errorf("error checking deleted pending transcription: %w", err)
+	} else if n == 0 {
+		return 0, fmt.Errorf("error completing pending transcription %d: %w", pendingID, sql.ErrNoRows)
+	}
+
+	id, err := db.insertBundle(tx, b)
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("error committing %s: %w", b.FileName, err)
+	}
+	return id, nil
+}
+
+// insertBundle saves a bundle as a new transcription in a transaction
+func (db *DB) insertBundle(tx *tx, b *Bundle) (int64, error) {
+	var id int64
+	if err := tx.Get(&id,
+		`INSERT INTO transcriptions (file_name, transcript_text, audio_profile, transcription_options, language, created_at)
+		VALUES (?, ?, NULLIF(?, ''), NULLIF(?, ''), NULLIF(?, ''), ?) RETURNING id`,
+		b.FileName, b.Text, b.AudioProfile, b.Options, b.Language, db.timeArg(orNow(b.CreatedAt)),
+	); err != nil {
+		return 0, fmt.Errorf("error saving transcription: %w", err)
+	}
+	if err := insertSegments(tx, id, b.Segments); err != nil {
+		return 0, err
+	}
+	if err := insertWords(tx, id, b.Words); err != nil {
+		return 0, err
+	}
+	if b.Media != nil {
+		media := *b.Media
+		media.TranscriptionID = id
+		if _, err := tx.NamedExec(saveMediaQuery, &media); err != nil {
+			return 0, fmt.Errorf("error saving media: %w", err)
+		}
+	}
+	for _, t := range b.Translations {
+		if _, err := tx.Exec(
+			`INSERT INTO translations (transcription_id, translated_text, language, model, stale, created_at)
+			VALUES (?, ?, NULLIF(?, ''), NULLIF(?, ''), ?, ?)`,
+			id, t.TranslatedText, t.Language, t.Model, t.Stale, db.timeArg(orNow(t.CreatedAt)),
+		); err != nil {
+			return 0, fmt.Errorf("error saving translation: %w", err)
+		}
+	}
+	if err := insertTags(tx, id, b.Tags); err != nil {
+		return 0, err
+	}
+	for _, name := range b.Collections {
+		if err := insertCollectionItems(tx, name, []int64{id}); err != nil {
+			return 0, err
+		}
+	}
+	return id, nil
+}
+
+// orNow returns t, or the current time when t is zero
+func orNow(t time.Time) time.Time {
+	if t.IsZero() {
+		return time.Now()
+	}
+	return t
+}
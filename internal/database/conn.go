@@ -0,0 +1,81 @@
+package database
+
+import (
+	"database/sql"
+	"net/url"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"           // PostgreSQL driver
+	_ "github.com/mattn/go-sqlite3" // SQLite driver
+)
+
+// Supported storage drivers
+const (
+	DriverSQLite   = "sqlite3"
+	DriverPostgres = "postgres"
+)
+
+// Driver returns the driver for a data source: postgres:// and postgresql:// URLs are
+// PostgreSQL databases, anything else is the path of a SQLite file
+func Driver(dsn string) string {
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		return DriverPostgres
+	}
+	return DriverSQLite
+}
+
+// redact hides the password of a PostgreSQL URL for error messages
+func redact(dsn string) string {
+	if Driver(dsn) != DriverPostgres {
+		return dsn
+	}
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return "postgres database"
+	}
+	return u.Redacted()
+}
+
+// conn rebinds the ? placeholders of the queries to the bind style of the driver, so the
+// same SQL runs on SQLite and PostgreSQL. Named queries are rebound by sqlx itself.
+type conn struct {
+	*sqlx.DB
+}
+
+func (c conn) Exec(query string, args ...any) (sql.Result, error) {
+	return c.DB.Exec(c.Rebind(query), args...)
+}
+
+func (c conn) Get(dest any, query string, args ...any) error {
+	return c.DB.Get(dest, c.Rebind(query), args...)
+}
+
+func (c conn) Select(dest any, query string, args ...any) error {
+	return c.DB.Select(dest, c.Rebind(query), args...)
+}
+
+func (c conn) Beginx() (*tx, error) {
+	t, err := c.DB.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	return &tx{t}, nil
+}
+
+// tx is a transaction rebinding its queries like conn
+type tx struct {
+	*sqlx.Tx
+}
+
+func (t *tx) Exec(query string, args ...any) (sql.Result, error) {
+	return t.Tx.Exec(t.Rebind(query), args...)
+}
+
+func (t *tx) Get(dest any, query string, args ...any) error {
+	return t.Tx.Get(dest, t.Rebind(query), args...)
+}
+
+func (t *tx) Preparex(query string) (*sqlx.Stmt, error) {
+	return t.Tx.Preparex(t.Rebind(query))
+}
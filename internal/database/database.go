@@ -1,32 +1,203 @@
 package database
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
-	_ "github.com/mattn/go-sqlite3" // SQLite driver
 )
 
-// DB represents the database connection
+// DB represents the database connection, to a SQLite file or a PostgreSQL server
 type DB struct {
-	conn *sqlx.DB
+	conn   conn
+	driver string
 }
 
-// New creates a new database connection
-func New(dbPath string) (*DB, error) {
-	conn, err := sqlx.Open("sqlite3", dbPath)
+// Transcription represents a stored transcription
+type Transcription struct {
+	ID           int64     `db:"id" json:"id"`
+	FileName     string    `db:"file_name" json:"file_name"`
+	Text         string    `db:"transcript_text" json:"text"`
+	AudioProfile string    `db:"audio_profile" json:"audio_profile,omitempty"`
+	Options      string    `db:"transcription_options" json:"options,omitempty"` // AssemblyAI options as JSON
+	Language     string    `db:"language" json:"language,omitempty"`             // spoken language code, e.g. "en_us"
+	CreatedAt    time.Time `db:"created_at" json:"created_at"`
+}
+
+// Segment represents a timed part of a transcription; Start and End are in milliseconds.
+// Confidence is the average word confidence from 0 to 1, zero when unknown.
+type Segment struct {
+	Index      int     `db:"segment_index" json:"index"`
+	Start      int64   `db:"start_ms" json:"start_ms"`
+	End        int64   `db:"end_ms" json:"end_ms"`
+	Text       string  `db:"text" json:"text"`
+	Confidence float64 `db:"confidence" json:"confidence,omitempty"`
+}
+
+// Word represents a recognized word of a transcription with its confidence from 0 to 1
+type Word struct {
+	Index      int     `db:"word_index" json:"index"`
+	Start      int64   `db:"start_ms" json:"start_ms"`
+	End        int64   `db:"end_ms" json:"end_ms"`
+	Text       string  `db:"text" json:"text"`
+	Confidence float64 `db:"confidence" json:"confidence"`
+}
+
+// Media holds metadata of the source file of a transcription
+type Media struct {
+	TranscriptionID int64      `db:"transcription_id" json:"transcription_id"`
+	DurationMS      int64      `db:"duration_ms" json:"duration_ms"`
+	Container       string     `db:"container" json:"container"`
+	VideoCodec      string     `db:"video_codec" json:"video_codec,omitempty"`
+	AudioCodec      string     `db:"audio_codec" json:"audio_codec,omitempty"`
+	BitRate         int64      `db:"bit_rate" json:"bit_rate"`
+	AudioChannels   int        `db:"audio_channels" json:"audio_channels"`
+	FileSize        int64      `db:"file_size" json:"file_size"`
+	SHA256          string     `db:"sha256" json:"sha256"`
+	MediaCreatedAt  *time.Time `db:"media_created_at" json:"media_created_at,omitempty"`
+}
+
+// TranscriptionListItem is a transcription with its media summary, without the text
+type TranscriptionListItem struct {
+	ID             int64     `db:"id" json:"id"`
+	FileName       string    `db:"file_name" json:"file_name"`
+	AudioProfile   string    `db:"audio_profile" json:"audio_profile,omitempty"`
+	Language       string    `db:"language" json:"language,omitempty"`
+	DurationMS     int64     `db:"duration_ms" json:"duration_ms"`
+	Container      string    `db:"container" json:"container"`
+	FileSize       int64     `db:"file_size" json:"file_size"`
+	HasTranslation bool      `db:"has_translation" json:"has_translation"`
+	Stale          bool      `db:"stale" json:"stale,omitempty"` // the latest translation predates an edit
+	Tags           string    `db:"tags" json:"tags,omitempty"`   // comma-separated, sorted
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+}
+
+// TranscriptionFilter selects transcriptions; zero fields match everything
+type TranscriptionFilter struct {
+	ID         int64
+	Since      time.Time // created at or after
+	Until      time.Time // created before
+	Tag        string
+	Collection string
+	Translated *bool
+	Language   string // ISO 639-1 code, also matches regional variants such as "en_us"
+}
+
+// Pending transcription statuses
+const (
+	PendingStatusPending = "pending"
+	PendingStatusFailed  = "failed"
+)
+
+// PendingTranscription is a transcription submitted to AssemblyAI whose result has not
+// been collected yet. Submission holds the serialized job description.
+type PendingTranscription struct {
+	ID            int64     `db:"id" json:"id"`
+	FileName      string    `db:"file_name" json:"file_name"`
+	TranscriptIDs string    `db:"transcript_ids" json:"transcript_ids"`
+	Submission    string    `db:"submission" json:"submission"`
+	Status        string    `db:"status" json:"status"`
+	Error         string    `db:"error" json:"error,omitempty"`
+	CreatedAt     time.Time `db:"created_at" json:"created_at"`
+}
+
+// Translation represents a stored translation
+type Translation struct {
+	ID              int64     `db:"id" json:"id"`
+	TranscriptionID int64     `db:"transcription_id" json:"transcription_id"`
+	TranslatedText  string    `db:"translated_text" json:"text"`
+	Language        string    `db:"language" json:"language,omitempty"` // target language code, empty for old translations
+	Model           string    `db:"model" json:"model,omitempty"`       // model that translated the text
+	Stale           bool      `db:"stale" json:"stale,omitempty"`       // the transcription was edited after translating
+	CreatedAt       time.Time `db:"created_at" json:"created_at"`
+}
+
+// Revision is a version of a transcription text. Revision 1 is the original text and
+// Diff describes the change from the previous revision.
+type Revision struct {
+	ID              int64     `db:"id" json:"id"`
+	TranscriptionID int64     `db:"transcription_id" json:"transcription_id"`
+	Revision        int       `db:"revision" json:"revision"`
+	Text            string    `db:"transcript_text" json:"text"`
+	Diff            string    `db:"diff" json:"diff,omitempty"`
+	CreatedAt       time.Time `db:"created_at" json:"created_at"`
+}
+
+// TranscriptEdit describes a correction of a transcription text
+type TranscriptEdit struct {
+	Text string
+	Diff string
+	// Segments replaces the text of the segments with the same Index and removes their
+	// words; the segment timings are kept
+	Segments []Segment
+	// DropSegments removes all segments and words, for edits of the whole text that no
+	// longer line up with the timings
+	DropSegments bool
+}
+
+// New opens a database connection and applies pending migrations. The data source is
+// a SQLite file path or a postgres:// URL.
+func New(dsn string) (*DB, error) {
+	db, err := Open(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := db.Migrate(context.Background()); err != nil {
+		_ = db.conn.Close()
+		return nil, fmt.Errorf("error migrating %s: %w", redact(dsn), err)
+	}
+	return db, nil
+}
+
+// Open opens a database connection without applying migrations. Foreign keys are
+// enforced, so deleting a transcription deletes its translations, segments and media.
+func Open(dsn string) (*DB, error) {
+	driver := Driver(dsn)
+	source := dsn
+	if driver == DriverSQLite {
+		sep := "?"
+		if strings.Contains(dsn, "?") {
+			sep = "&"
+		}
+		source += sep + "_foreign_keys=on"
+	}
+	sqlxDB, err := sqlx.Open(driver, source)
 	if err != nil {
 		return nil, fmt.Errorf("error opening database: %w", err)
 	}
 
-	if err := conn.Ping(); err != nil {
+	if err := sqlxDB.Ping(); err != nil {
+		_ = sqlxDB.Close()
 		return nil, fmt.Errorf("error connecting to database: %w", err)
 	}
 
-	return &DB{conn: conn}, nil
+	// every connection to ":memory:" gets a database of its own
+	if strings.HasPrefix(dsn, ":memory:") {
+		sqlxDB.SetMaxOpenConns(1)
+	}
+
+	return &DB{conn: conn{sqlxDB}, driver: driver}, nil
 }
 
-// Setup is deprecated: schema initialization is now handled by goose migrations.
+// Driver returns the driver of the connection, DriverSQLite or DriverPostgres
+func (db *DB) Driver() string {
+	return db.driver
+}
+
+// timeArg converts a time to a query argument comparable with stored timestamps: SQLite
+// keeps them as UTC text, PostgreSQL as timestamptz
+func (db *DB) timeArg(t time.Time) any {
+	if db.driver == DriverPostgres {
+		return t
+	}
+	return t.UTC().Format(time.DateTime)
+}
+
+// Setup is kept for the Database interface: New applies the migrations.
 func (db *DB) Setup() error {
 	return nil
 }
@@ -38,26 +209,288 @@ func (db *DB) Close() error {
 
 // SaveTranscription saves a transcription to the database
 func (db *DB) SaveTranscription(fileName, text string) (int64, error) {
-	result, err := db.conn.Exec(
-		"INSERT INTO transcriptions (file_name, transcript_text) VALUES (?, ?)",
-		fileName, text,
+	return db.InsertTranscription(&Transcription{FileName: fileName, Text: text})
+}
+
+// InsertTranscription saves a transcription with its metadata to the database
+func (db *DB) InsertTranscription(t *Transcription) (int64, error) {
+	var id int64
+	err := db.conn.Get(&id,
+		`INSERT INTO transcriptions (file_name, transcript_text, audio_profile, transcription_options, language)
+		VALUES (?, ?, NULLIF(?, ''), NULLIF(?, ''), NULLIF(?, '')) RETURNING id`,
+		t.FileName, t.Text, t.AudioProfile, t.Options, t.Language,
 	)
 	if err != nil {
 		return 0, fmt.Errorf("error saving transcription: %w", err)
 	}
 
-	id, err := result.LastInsertId()
+	return id, nil
+}
+
+// SaveSegments saves the timed segments of a transcription
+func (db *DB) SaveSegments(transcriptionID int64, segments []Segment) error {
+	tx, err := db.conn.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if err := insertSegments(tx, transcriptionID, segments); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing segments: %w", err)
+	}
+	return nil
+}
+
+// insertSegments saves segments in a transaction, numbered by their position
+func insertSegments(tx *tx, transcriptionID int64, segments []Segment) error {
+	for i, seg := range segments {
+		_, err := tx.Exec(
+			`INSERT INTO segments (transcription_id, segment_index, start_ms, end_ms, text, confidence)
+			VALUES (?, ?, ?, ?, ?, NULLIF(?, 0.0))`,
+			transcriptionID, i, seg.Start, seg.End, seg.Text, seg.Confidence,
+		)
+		if err != nil {
+			return fmt.Errorf("error saving segment %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// GetSegments retrieves the segments of a transcription ordered by position
+func (db *DB) GetSegments(transcriptionID int64) ([]Segment, error) {
+	var segments []Segment
+	err := db.conn.Select(&segments,
+		`SELECT segment_index, start_ms, end_ms, text, COALESCE(confidence, 0) AS confidence
+		FROM segments WHERE transcription_id = ? ORDER BY segment_index`,
+		transcriptionID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving segments: %w", err)
+	}
+
+	return segments, nil
+}
+
+// SaveWords saves the recognized words of a transcription
+func (db *DB) SaveWords(transcriptionID int64, words []Word) error {
+	tx, err := db.conn.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if err := insertWords(tx, transcriptionID, words); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing words: %w", err)
+	}
+	return nil
+}
+
+// insertWords saves words in a transaction, numbered by their position
+func insertWords(tx *tx, transcriptionID int64, words []Word) error {
+	stmt, err := tx.Preparex(
+		"INSERT INTO words (transcription_id, word_index, start_ms, end_ms, text, confidence) VALUES (?, ?, ?, ?, ?, ?)",
+	)
+	if err != nil {
+		return fmt.Errorf("error preparing words insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for i, w := range words {
+		if _, err := stmt.Exec(transcriptionID, i, w.Start, w.End, w.Text, w.Confidence); err != nil {
+			return fmt.Errorf("error saving word %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// GetWords retrieves the words of a transcription ordered by position
+func (db *DB) GetWords(transcriptionID int64) ([]Word, error) {
+	var words []Word
+	err := db.conn.Select(&words,
+		"SELECT word_index, start_ms, end_ms, text, confidence FROM words WHERE transcription_id = ? ORDER BY word_index",
+		transcriptionID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving words: %w", err)
+	}
+
+	return words, nil
+}
+
+// saveMediaQuery inserts or replaces the media metadata of a transcription
+const saveMediaQuery = `INSERT INTO media (transcription_id, duration_ms, container, video_codec, audio_codec,
+		bit_rate, audio_channels, file_size, sha256, media_created_at)
+	VALUES (:transcription_id, :duration_ms, :container, :video_codec, :audio_codec,
+		:bit_rate, :audio_channels, :file_size, :sha256, :media_created_at)
+	ON CONFLICT (transcription_id) DO UPDATE SET duration_ms = excluded.duration_ms,
+		container = excluded.container, video_codec = excluded.video_codec, audio_codec = excluded.audio_codec,
+		bit_rate = excluded.bit_rate, audio_channels = excluded.audio_channels, file_size = excluded.file_size,
+		sha256 = excluded.sha256, media_created_at = excluded.media_created_at`
+
+// SaveMedia saves the source file metadata of a transcription, replacing earlier metadata
+func (db *DB) SaveMedia(m *Media) error {
+	_, err := db.conn.NamedExec(saveMediaQuery, m)
+	if err != nil {
+		return fmt.Errorf("error saving media: %w", err)
+	}
+
+	return nil
+}
+
+// GetMedia retrieves the source file metadata of a transcription
+func (db *DB) GetMedia(transcriptionID int64) (*Media, error) {
+	var m Media
+	err := db.conn.Get(&m,
+		`SELECT transcription_id, duration_ms, container, video_codec, audio_codec,
+			bit_rate, audio_channels, file_size, sha256, media_created_at
+		FROM media WHERE transcription_id = ?`,
+		transcriptionID,
+	)
 	if err != nil {
-		return 0, fmt.Errorf("error getting inserted ID: %w", err)
+		return nil, fmt.Errorf("error retrieving media: %w", err)
+	}
+
+	return &m, nil
+}
+
+// ListTranscriptions retrieves all transcriptions with their media summary ordered by ID
+func (db *DB) ListTranscriptions() ([]TranscriptionListItem, error) {
+	return db.FindTranscriptions(TranscriptionFilter{})
+}
+
+// FindTranscriptions retrieves the transcriptions matching a filter with their media
+// summary and tags, ordered by ID
+func (db *DB) FindTranscriptions(f TranscriptionFilter) ([]TranscriptionListItem, error) {
+	var (
+		where []string
+		args  []any
+	)
+	if f.ID != 0 {
+		where = append(where, "t.id = ?")
+		args = append(args, f.ID)
+	}
+	if !f.Since.IsZero() {
+		where = append(where, "t.created_at >= ?")
+		args = append(args, db.timeArg(f.Since))
+	}
+	if !f.Until.IsZero() {
+		where = append(where, "t.created_at < ?")
+		args = append(args, db.timeArg(f.Until))
+	}
+	if f.Tag != "" {
+		where = append(where, `EXISTS (SELECT 1 FROM transcription_tags tt JOIN tags tg ON tg.id = tt.tag_id
+			WHERE tt.transcription_id = t.id AND lower(tg.name) = lower(?))`)
+		args = append(args, f.Tag)
+	}
+	if f.Collection != "" {
+		where = append(where, `EXISTS (SELECT 1 FROM collection_items ci JOIN collections c ON c.id = ci.collection_id
+			WHERE ci.transcription_id = t.id AND lower(c.name) = lower(?))`)
+		args = append(args, f.Collection)
+	}
+	if f.Translated != nil {
+		cond := "EXISTS (SELECT 1 FROM translations tr WHERE tr.transcription_id = t.id)"
+		if !*f.Translated {
+			cond = "NOT " + cond
+		}
+		where = append(where, cond)
+	}
+	if f.Language != "" {
+		// stored codes may carry a region, e.g. "en_us"
+		where = append(where, `(lower(t.language) = ? OR lower(t.language) LIKE ? ESCAPE '\')`)
+		args = append(args, f.Language, f.Language+`\_%`)
+	}
+	query := `SELECT t.id, t.file_name, COALESCE(t.audio_profile, '') AS audio_profile,
+			COALESCE(t.language, '') AS language, COALESCE(m.duration_ms, 0) AS duration_ms, COALESCE(m.container, '') AS container,
+			COALESCE(m.file_size, 0) AS file_size,
+			EXISTS (SELECT 1 FROM translations tr WHERE tr.transcription_id = t.id) AS has_translation,
+			COALESCE((SELECT tr.stale FROM translations tr WHERE tr.transcription_id = t.id ORDER BY tr.id DESC LIMIT 1), FALSE) AS stale,
+			COALESCE((SELECT string_agg(tg.name, ',' ORDER BY lower(tg.name)) FROM transcription_tags tt
+				JOIN tags tg ON tg.id = tt.tag_id WHERE tt.transcription_id = t.id), '') AS tags,
+			t.created_at
+		FROM transcriptions t
+		LEFT JOIN media m ON m.transcription_id = t.id`
+	if len(where) > 0 {
+		query += "\n\t\tWHERE " + strings.Join(where, " AND ")
+	}
+	query += "\n\t\tORDER BY t.id"
+
+	var items []TranscriptionListItem
+	if err := db.conn.Select(&items, query, args...); err != nil {
+		return nil, fmt.Errorf("error retrieving transcriptions: %w", err)
+	}
+
+	return items, nil
+}
+
+// SavePending records a submitted transcription
+func (db *DB) SavePending(p *PendingTranscription) (int64, error) {
+	var id int64
+	err := db.conn.Get(&id,
+		"INSERT INTO pending_transcriptions (file_name, transcript_ids, submission) VALUES (?, ?, ?) RETURNING id",
+		p.FileName, p.TranscriptIDs, p.Submission,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("error saving pending transcription: %w", err)
 	}
 
 	return id, nil
 }
 
+// ListPending retrieves submitted transcriptions with the given status ordered by ID
+func (db *DB) ListPending(status string) ([]PendingTranscription, error) {
+	var pending []PendingTranscription
+	err := db.conn.Select(&pending,
+		`SELECT id, file_name, transcript_ids, submission, status, error, created_at
+		FROM pending_transcriptions WHERE status = ? ORDER BY id`,
+		status,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving pending transcriptions: %w", err)
+	}
+
+	return pending, nil
+}
+
+// FailPending marks a submitted transcription as failed
+func (db *DB) FailPending(id int64, message string) error {
+	_, err := db.conn.Exec(
+		"UPDATE pending_transcriptions SET status = ?, error = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?",
+		PendingStatusFailed, message, id,
+	)
+	if err != nil {
+		return fmt.Errorf("error updating pending transcription: %w", err)
+	}
+
+	return nil
+}
+
+// DeletePending removes a submitted transcription once its result is saved
+func (db *DB) DeletePending(id int64) error {
+	_, err := db.conn.Exec("DELETE FROM pending_transcriptions WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("error deleting pending transcription: %w", err)
+	}
+
+	return nil
+}
+
 // SaveTerm saves an untranslatable term to the database
 func (db *DB) SaveTerm(term, description string) error {
 	_, err := db.conn.Exec(
-		"INSERT OR REPLACE INTO untranslatable_terms (term, description) VALUES (?, ?)",
+		`INSERT INTO untranslatable_terms (term, description) VALUES (?, ?)
+		ON CONFLICT (term) DO UPDATE SET description = excluded.description`,
 		term, description,
 	)
 	if err != nil {
@@ -67,6 +500,23 @@ func (db *DB) SaveTerm(term, description string) error {
 	return nil
 }
 
+// DeleteTerm removes an untranslatable term from the database
+func (db *DB) DeleteTerm(term string) error {
+	result, err := db.conn.Exec("DELETE FROM untranslatable_terms WHERE term = ?", term)
+	if err != nil {
+		return fmt.Errorf("error deleting term: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking deleted term: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("error deleting term: %w", sql.ErrNoRows)
+	}
+
+	return nil
+}
+
 // SaveTranslation saves a translation to the database
 func (db *DB) SaveTranslation(transcriptionID int64, translatedText string) error {
 	_, err := db.conn.Exec(
@@ -80,6 +530,21 @@ func (db *DB) SaveTranslation(transcriptionID int64, translatedText string) erro
 	return nil
 }
 
+// InsertTranslation saves a translation with its target language and model to the database
+func (db *DB) InsertTranslation(t *Translation) (int64, error) {
+	var id int64
+	err := db.conn.Get(&id,
+		`INSERT INTO translations (transcription_id, translated_text, language, model)
+		VALUES (?, ?, NULLIF(?, ''), NULLIF(?, '')) RETURNING id`,
+		t.TranscriptionID, t.TranslatedText, t.Language, t.Model,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("error saving translation: %w", err)
+	}
+
+	return id, nil
+}
+
 // GetTranscription retrieves a transcription by ID
 func (db *DB) GetTranscription(id int64) (string, error) {
 	var text string
@@ -94,13 +559,29 @@ func (db *DB) GetTranscription(id int64) (string, error) {
 	return text, nil
 }
 
+// GetTranscriptionRecord retrieves a transcription with its metadata by ID
+func (db *DB) GetTranscriptionRecord(id int64) (*Transcription, error) {
+	var t Transcription
+	err := db.conn.Get(&t,
+		`SELECT id, file_name, transcript_text, COALESCE(audio_profile, '') AS audio_profile,
+		COALESCE(transcription_options, '') AS transcription_options, COALESCE(language, '') AS language, created_at
+		FROM transcriptions WHERE id = ?`,
+		id,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving transcription: %w", err)
+	}
+
+	return &t, nil
+}
+
 // GetAllTerms retrieves all untranslatable terms
 func (db *DB) GetAllTerms() ([]map[string]string, error) {
 	var terms []struct {
 		Term        string `db:"term"`
 		Description string `db:"description"`
 	}
-	err := db.conn.Select(&terms, "SELECT term, description FROM untranslatable_terms")
+	err := db.conn.Select(&terms, "SELECT term, description FROM untranslatable_terms ORDER BY term")
 	if err != nil {
 		return nil, fmt.Errorf("error retrieving terms: %w", err)
 	}
@@ -116,11 +597,11 @@ func (db *DB) GetAllTerms() ([]map[string]string, error) {
 	return result, nil
 }
 
-// GetTranslation retrieves a translation by transcription ID
+// GetTranslation retrieves the latest translation by transcription ID
 func (db *DB) GetTranslation(transcriptionID int64) (string, error) {
 	var text string
 	err := db.conn.Get(&text,
-		"SELECT translated_text FROM translations WHERE transcription_id = ?",
+		"SELECT translated_text FROM translations WHERE transcription_id = ? ORDER BY id DESC LIMIT 1",
 		transcriptionID,
 	)
 	if err != nil {
@@ -129,3 +610,127 @@ func (db *DB) GetTranslation(transcriptionID int64) (string, error) {
 
 	return text, nil
 }
+
+// ListTranslations retrieves all translations ordered by ID
+func (db *DB) ListTranslations() ([]Translation, error) {
+	var translations []Translation
+	err := db.conn.Select(&translations,
+		`SELECT id, transcription_id, translated_text, COALESCE(language, '') AS language,
+		COALESCE(model, '') AS model, stale, created_at FROM translations ORDER BY id`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving translations: %w", err)
+	}
+
+	return translations, nil
+}
+
+// ListStaleTranslations returns the IDs of transcriptions whose latest translation is stale
+func (db *DB) ListStaleTranslations() ([]int64, error) {
+	var ids []int64
+	err := db.conn.Select(&ids,
+		`SELECT tr.transcription_id FROM translations tr
+		WHERE tr.stale = TRUE AND tr.id = (SELECT MAX(id) FROM translations WHERE transcription_id = tr.transcription_id)
+		ORDER BY tr.transcription_id`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving stale translations: %w", err)
+	}
+
+	return ids, nil
+}
+
+// ReviseTranscription applies an edit to a transcription, records it as a new revision and
+// marks the translations of the transcription stale, all in one transaction. The first
+// edit also records the original text as revision 1.
+func (db *DB) ReviseTranscription(id int64, edit TranscriptEdit) (*Revision, error) {
+	tx, err := db.conn.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var last int
+	if err := tx.Get(&last,
+		"SELECT COALESCE(MAX(revision), 0) FROM transcription_revisions WHERE transcription_id = ?", id,
+	); err != nil {
+		return nil, fmt.Errorf("error retrieving revisions: %w", err)
+	}
+	if last == 0 {
+		if _, err := tx.Exec(
+			`INSERT INTO transcription_revisions (transcription_id, revision, transcript_text)
+			SELECT id, 1, transcript_text FROM transcriptions WHERE id = ?`, id,
+		); err != nil {
+			return nil, fmt.Errorf("error saving original revision: %w", err)
+		}
+		last = 1
+	}
+
+	result, err := tx.Exec("UPDATE transcriptions SET transcript_text = ? WHERE id = ?", edit.Text, id)
+	if err != nil {
+		return nil, fmt.Errorf("error updating transcription: %w", err)
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return nil, fmt.Errorf("error checking updated transcription: %w", err)
+	} else if n == 0 {
+		return nil, fmt.Errorf("error updating transcription: %w", sql.ErrNoRows)
+	}
+
+	if edit.DropSegments {
+		if _, err := tx.Exec("DELETE FROM segments WHERE transcription_id = ?", id); err != nil {
+			return nil, fmt.Errorf("error deleting segments: %w", err)
+		}
+		if _, err := tx.Exec("DELETE FROM words WHERE transcription_id = ?", id); err != nil {
+			return nil, fmt.Errorf("error deleting words: %w", err)
+		}
+	}
+	for _, seg := range edit.Segments {
+		if _, err := tx.Exec(
+			"UPDATE segments SET text = ? WHERE transcription_id = ? AND segment_index = ?",
+			seg.Text, id, seg.Index,
+		); err != nil {
+			return nil, fmt.Errorf("error updating segment %d: %w", seg.Index, err)
+		}
+		// the recognized words and their confidences no longer match the corrected text
+		if _, err := tx.Exec(
+			"DELETE FROM words WHERE transcription_id = ? AND start_ms >= ? AND end_ms <= ?",
+			id, seg.Start, seg.End,
+		); err != nil {
+			return nil, fmt.Errorf("error deleting words of segment %d: %w", seg.Index, err)
+		}
+	}
+
+	if _, err := tx.Exec("UPDATE translations SET stale = TRUE WHERE transcription_id = ?", id); err != nil {
+		return nil, fmt.Errorf("error marking translations stale: %w", err)
+	}
+
+	rev := &Revision{TranscriptionID: id, Revision: last + 1, Text: edit.Text, Diff: edit.Diff}
+	if err := tx.Get(&rev.ID,
+		"INSERT INTO transcription_revisions (transcription_id, revision, transcript_text, diff) VALUES (?, ?, ?, ?) RETURNING id",
+		id, rev.Revision, rev.Text, rev.Diff,
+	); err != nil {
+		return nil, fmt.Errorf("error saving revision: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing revision: %w", err)
+	}
+	return rev, nil
+}
+
+// ListRevisions retrieves the revisions of a transcription, oldest first
+func (db *DB) ListRevisions(transcriptionID int64) ([]Revision, error) {
+	var revisions []Revision
+	err := db.conn.Select(&revisions,
+		`SELECT id, transcription_id, revision, transcript_text, diff, created_at
+		FROM transcription_revisions WHERE transcription_id = ? ORDER BY revision`,
+		transcriptionID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving revisions: %w", err)
+	}
+
+	return revisions, nil
+}
@@ -0,0 +1,245 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Collection is a named group of transcriptions
+type Collection struct {
+	Name  string `db:"name" json:"name"`
+	Count int    `db:"count" json:"count"`
+}
+
+// Tag is a label of transcriptions with the number of tagged transcriptions
+type Tag struct {
+	Name  string `db:"name" json:"name"`
+	Count int    `db:"count" json:"count"`
+}
+
+// DeleteTranscription deletes a transcription with its translations, segments, words,
+// media metadata, revisions, tags and collection memberships
+func (db *DB) DeleteTranscription(id int64) error {
+	result, err := db.conn.Exec("DELETE FROM transcriptions WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("error deleting transcription: %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking deleted transcription: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("error deleting transcription: %w", sql.ErrNoRows)
+	}
+
+	return nil
+}
+
+// TagTranscription adds tags to a transcription, creating tags that do not exist yet.
+// Tag names are case-insensitive.
+func (db *DB) TagTranscription(id int64, tags ...string) error {
+	tx, err := db.conn.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if err := insertTags(tx, id, tags); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing tags: %w", err)
+	}
+	return nil
+}
+
+// insertTags tags a transcription in a transaction
+func insertTags(tx *tx, id int64, tags []string) error {
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		if _, err := tx.Exec("INSERT INTO tags (name) VALUES (?) ON CONFLICT DO NOTHING", tag); err != nil {
+			return fmt.Errorf("error saving tag %q: %w", tag, err)
+		}
+		if _, err := tx.Exec(
+			`INSERT INTO transcription_tags (transcription_id, tag_id)
+			SELECT CAST(? AS BIGINT), id FROM tags WHERE lower(name) = lower(?) ON CONFLICT DO NOTHING`,
+			id, tag,
+		); err != nil {
+			return fmt.Errorf("error tagging transcription %d with %q: %w", id, tag, err)
+		}
+	}
+	return nil
+}
+
+// UntagTranscription removes tags from a transcription; tags left unused are deleted
+func (db *DB) UntagTranscription(id int64, tags ...string) error {
+	tx, err := db.conn.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	for _, tag := range tags {
+		if _, err := tx.Exec(
+			"DELETE FROM transcription_tags WHERE transcription_id = ? AND tag_id IN (SELECT id FROM tags WHERE lower(name) = lower(?))",
+			id, strings.TrimSpace(tag),
+		); err != nil {
+			return fmt.Errorf("error removing tag %q: %w", tag, err)
+		}
+	}
+	if _, err := tx.Exec("DELETE FROM tags WHERE id NOT IN (SELECT tag_id FROM transcription_tags)"); err != nil {
+		return fmt.Errorf("error deleting unused tags: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing tags: %w", err)
+	}
+	return nil
+}
+
+// GetTags retrieves the tags of a transcription ordered by name
+func (db *DB) GetTags(transcriptionID int64) ([]string, error) {
+	var tags []string
+	err := db.conn.Select(&tags,
+		`SELECT tg.name FROM transcription_tags tt JOIN tags tg ON tg.id = tt.tag_id
+		WHERE tt.transcription_id = ? ORDER BY lower(tg.name)`,
+		transcriptionID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+// ListTags retrieves all tags with the number of tagged transcriptions, ordered by name
+func (db *DB) ListTags() ([]Tag, error) {
+	var tags []Tag
+	err := db.conn.Select(&tags,
+		`SELECT tg.name, COUNT(tt.transcription_id) AS count FROM tags tg
+		LEFT JOIN transcription_tags tt ON tt.tag_id = tg.id
+		GROUP BY tg.id, tg.name ORDER BY lower(tg.name)`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+// AddToCollection adds transcriptions to a collection, creating the collection when it does
+// not exist yet
+func (db *DB) AddToCollection(name string, ids ...int64) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return errors.New("error adding to collection: empty name")
+	}
+
+	tx, err := db.conn.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if err := insertCollectionItems(tx, name, ids); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing collection: %w", err)
+	}
+	return nil
+}
+
+// insertCollectionItems adds transcriptions to a collection in a transaction
+func insertCollectionItems(tx *tx, name string, ids []int64) error {
+	if _, err := tx.Exec("INSERT INTO collections (name) VALUES (?) ON CONFLICT DO NOTHING", name); err != nil {
+		return fmt.Errorf("error saving collection %q: %w", name, err)
+	}
+	for _, id := range ids {
+		if _, err := tx.Exec(
+			`INSERT INTO collection_items (collection_id, transcription_id)
+			SELECT id, CAST(? AS BIGINT) FROM collections WHERE lower(name) = lower(?) ON CONFLICT DO NOTHING`,
+			id, name,
+		); err != nil {
+			return fmt.Errorf("error adding transcription %d to %q: %w", id, name, err)
+		}
+	}
+	return nil
+}
+
+// RemoveFromCollection removes transcriptions from a collection
+func (db *DB) RemoveFromCollection(name string, ids ...int64) error {
+	for _, id := range ids {
+		_, err := db.conn.Exec(
+			"DELETE FROM collection_items WHERE transcription_id = ? AND collection_id IN (SELECT id FROM collections WHERE lower(name) = lower(?))",
+			id, name,
+		)
+		if err != nil {
+			return fmt.Errorf("error removing transcription %d from %q: %w", id, name, err)
+		}
+	}
+
+	return nil
+}
+
+// DeleteCollection deletes a collection; its transcriptions are kept
+func (db *DB) DeleteCollection(name string) error {
+	result, err := db.conn.Exec("DELETE FROM collections WHERE lower(name) = lower(?)", name)
+	if err != nil {
+		return fmt.Errorf("error deleting collection: %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking deleted collection: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("error deleting collection: %w", sql.ErrNoRows)
+	}
+
+	return nil
+}
+
+// GetCollections retrieves the names of the collections containing a transcription
+func (db *DB) GetCollections(transcriptionID int64) ([]string, error) {
+	var names []string
+	err := db.conn.Select(&names,
+		`SELECT c.name FROM collection_items ci JOIN collections c ON c.id = ci.collection_id
+		WHERE ci.transcription_id = ? ORDER BY lower(c.name)`,
+		transcriptionID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving collections: %w", err)
+	}
+
+	return names, nil
+}
+
+// ListCollections retrieves all collections with the number of their transcriptions
+func (db *DB) ListCollections() ([]Collection, error) {
+	var collections []Collection
+	err := db.conn.Select(&collections,
+		`SELECT c.name, COUNT(ci.transcription_id) AS count FROM collections c
+		LEFT JOIN collection_items ci ON ci.collection_id = c.id
+		GROUP BY c.id, c.name ORDER BY lower(c.name)`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving collections: %w", err)
+	}
+
+	return collections, nil
+}
@@ -0,0 +1,117 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pressly/goose/v3"
+
+	"assemblyai-transcriber/migrations"
+)
+
+// ErrSchemaTooNew is returned when the database was migrated by a newer build that
+// knows migrations this one does not
+var ErrSchemaTooNew = errors.New("database schema is newer than this build supports")
+
+// Migration describes an embedded migration and whether it is applied to the database
+type Migration struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// provider creates a goose provider for the embedded migrations of the database driver
+func (db *DB) provider() (*goose.Provider, error) {
+	dialect, files := goose.DialectSQLite3, fs.FS(migrations.FS)
+	if db.driver == DriverPostgres {
+		dialect, files = goose.DialectPostgres, migrations.Postgres()
+	}
+	provider, err := goose.NewProvider(dialect, db.conn.DB.DB, files)
+	if err != nil {
+		return nil, fmt.Errorf("error loading migrations: %w", err)
+	}
+	return provider, nil
+}
+
+// Migrate applies pending migrations and returns the names of the applied files. It fails
+// with ErrSchemaTooNew when the database has a higher version than the latest migration.
+func (db *DB) Migrate(ctx context.Context) ([]string, error) {
+	return db.MigrateTo(ctx, 0)
+}
+
+// MigrateTo applies pending migrations up to version, or all of them when version is 0
+func (db *DB) MigrateTo(ctx context.Context, version int64) ([]string, error) {
+	provider, err := db.provider()
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := provider.GetDBVersion(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error checking schema version: %w", err)
+	}
+	sources := provider.ListSources()
+	latest := sources[len(sources)-1].Version
+	if current > latest {
+		return nil, fmt.Errorf("schema version %d, latest known migration %d: %w", current, latest, ErrSchemaTooNew)
+	}
+	if version == 0 {
+		version = latest
+	}
+
+	results, err := provider.UpTo(ctx, version)
+	if err != nil && strings.Contains(err.Error(), "no such module: fts5") {
+		return nil, fmt.Errorf("error applying migrations, the search index needs a build with -tags sqlite_fts5: %w", err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error applying migrations: %w", err)
+	}
+	applied := make([]string, 0, len(results))
+	for _, r := range results {
+		applied = append(applied, filepath.Base(r.Source.Path))
+	}
+	return applied, nil
+}
+
+// MigrateDown rolls back the latest applied migration and returns its file name
+func (db *DB) MigrateDown(ctx context.Context) (string, error) {
+	provider, err := db.provider()
+	if err != nil {
+		return "", err
+	}
+
+	result, err := provider.Down(ctx)
+	if err != nil {
+		return "", fmt.Errorf("error rolling back migration: %w", err)
+	}
+	return filepath.Base(result.Source.Path), nil
+}
+
+// MigrationStatus lists the embedded migrations in order with their state
+func (db *DB) MigrationStatus(ctx context.Context) ([]Migration, error) {
+	provider, err := db.provider()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses, err := provider.Status(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving migration status: %w", err)
+	}
+	result := make([]Migration, 0, len(statuses))
+	for _, s := range statuses {
+		result = append(result, Migration{
+			Version:   s.Source.Version,
+			Name:      filepath.Base(s.Source.Path),
+			Applied:   s.State == goose.StateApplied,
+			AppliedAt: s.AppliedAt,
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,152 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Markers around the matched words in SearchResult.Snippet
+const (
+	SnippetStart = "["
+	SnippetEnd   = "]"
+)
+
+// Search result sources
+const (
+	SourceTranscription = "transcription"
+	SourceTranslation   = "translation"
+)
+
+// snippetWords is the number of words of a search result snippet
+const snippetWords = 16
+
+// SearchResult is a part of a transcription or translation matching a search query
+type SearchResult struct {
+	TranscriptionID int64  `db:"transcription_id" json:"transcription_id"`
+	FileName        string `db:"file_name" json:"file_name"`
+	Source          string `db:"source" json:"source"`
+	Snippet         string `db:"snippet" json:"snippet"`
+	Start           int64  `db:"start_ms" json:"start_ms"` // start of the matching segment in milliseconds, -1 when unknown
+}
+
+// Search finds transcriptions and their latest translations matching a full-text query,
+// newest transcription first. Words must all occur and "quotes" match a phrase; SQLite
+// also matches a prefix with limit*, PostgreSQL excludes words with -word. Transcriptions
+// with segments are reported per matching segment with its start time. At most limit
+// results are returned, all of them when limit is 0.
+func (db *DB) Search(query string, limit int) ([]SearchResult, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, errors.New("error searching: empty query")
+	}
+
+	match := ftsQuery(query)
+	q, args := searchSQLite, []any{match, match, match, match}
+	if db.driver == DriverPostgres {
+		q, args = searchPostgres, []any{query}
+	}
+	q += "\n\t\tORDER BY transcription_id DESC, source, start_ms"
+	if limit > 0 {
+		q += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	var results []SearchResult
+	if err := db.conn.Select(&results, q, args...); err != nil {
+		return nil, fmt.Errorf("error searching for %q: %w", query, err)
+	}
+
+	return results, nil
+}
+
+// ftsQuery turns a search query into an FTS5 expression matching all of its words, so
+// punctuation in words like rate-limits, what's or C++ is not read as FTS5 syntax. Every
+// word is quoted as a string, which FTS5 splits into tokens like the indexed text;
+// "quoted phrases" are kept and a trailing * still matches a prefix, "like this"* too.
+func ftsQuery(query string) string {
+	var terms []string
+	for rest := strings.TrimSpace(query); rest != ""; rest = strings.TrimSpace(rest) {
+		if phrase, ok := strings.CutPrefix(rest, `"`); ok {
+			end := strings.Index(phrase, `"`)
+			if end < 0 {
+				end = len(phrase)
+			}
+			rest = phrase[min(end+1, len(phrase)):]
+			prefix := ""
+			if strings.HasPrefix(rest, "*") {
+				prefix, rest = "*", strings.TrimLeft(rest, "*")
+			}
+			terms = append(terms, `"`+phrase[:end]+`"`+prefix)
+			continue
+		}
+
+		word := rest
+		if end := strings.IndexFunc(rest, unicode.IsSpace); end >= 0 {
+			word = rest[:end]
+		}
+		rest = rest[len(word):]
+		prefix := ""
+		if trimmed := strings.TrimRight(word, "*"); trimmed != "" && trimmed != word {
+			word, prefix = trimmed, "*"
+		}
+		terms = append(terms, `"`+strings.ReplaceAll(word, `"`, `""`)+`"`+prefix)
+	}
+	return strings.Join(terms, " ")
+}
+
+// searchSQLite queries the FTS5 tables; each part takes the query once
+var searchSQLite = func() string {
+	snippet := func(table string) string {
+		return fmt.Sprintf("snippet(%s, -1, '%s', '%s', '...', %d)", table, SnippetStart, SnippetEnd, snippetWords)
+	}
+	return `SELECT t.id AS transcription_id, t.file_name, '` + SourceTranscription + `' AS source, s.start_ms,
+			` + snippet("segments_fts") + ` AS snippet
+		FROM segments_fts
+		JOIN segments s ON s.id = segments_fts.rowid
+		JOIN transcriptions t ON t.id = s.transcription_id
+		WHERE segments_fts MATCH ?
+		UNION ALL
+		SELECT t.id, t.file_name, '` + SourceTranscription + `', -1, ` + snippet("transcriptions_fts") + `
+		FROM transcriptions_fts
+		JOIN transcriptions t ON t.id = transcriptions_fts.rowid
+		WHERE transcriptions_fts MATCH ? AND t.id NOT IN (
+			SELECT s.transcription_id FROM segments_fts JOIN segments s ON s.id = segments_fts.rowid
+			WHERE segments_fts MATCH ?)
+		UNION ALL
+		SELECT t.id, t.file_name, '` + SourceTranslation + `', -1, ` + snippet("translations_fts") + `
+		FROM translations_fts
+		JOIN translations tr ON tr.id = translations_fts.rowid
+		JOIN transcriptions t ON t.id = tr.transcription_id
+		WHERE translations_fts MATCH ?
+			AND tr.id = (SELECT MAX(id) FROM translations WHERE transcription_id = tr.transcription_id)`
+}()
+
+// searchPostgres queries the generated tsvector columns with the same parts as
+// searchSQLite; the query is passed once
+var searchPostgres = func() string {
+	headline := func(column string) string {
+		return fmt.Sprintf("ts_headline('simple', %s, q.query, 'StartSel=%s, StopSel=%s, MaxWords=%d, MinWords=%d')",
+			column, SnippetStart, SnippetEnd, snippetWords, snippetWords/2)
+	}
+	return `WITH q AS (SELECT websearch_to_tsquery('simple', ?) AS query)
+		SELECT t.id AS transcription_id, t.file_name, '` + SourceTranscription + `' AS source, s.start_ms,
+			` + headline("s.text") + ` AS snippet
+		FROM segments s
+		CROSS JOIN q
+		JOIN transcriptions t ON t.id = s.transcription_id
+		WHERE s.search_vector @@ q.query
+		UNION ALL
+		SELECT t.id, t.file_name, '` + SourceTranscription + `', -1, ` + headline("t.transcript_text") + `
+		FROM transcriptions t
+		CROSS JOIN q
+		WHERE t.search_vector @@ q.query AND t.id NOT IN (
+			SELECT s.transcription_id FROM segments s WHERE s.search_vector @@ q.query)
+		UNION ALL
+		SELECT t.id, t.file_name, '` + SourceTranslation + `', -1, ` + headline("tr.translated_text") + `
+		FROM translations tr
+		CROSS JOIN q
+		JOIN transcriptions t ON t.id = tr.transcription_id
+		WHERE tr.search_vector @@ q.query
+			AND tr.id = (SELECT MAX(id) FROM translations WHERE transcription_id = tr.transcription_id)`
+}()
@@ -0,0 +1,56 @@
+package database_test
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"assemblyai-transcriber/internal/database"
+	"assemblyai-transcriber/internal/database/storagetest"
+	"assemblyai-transcriber/internal/interfaces"
+)
+
+func TestStorageSQLite(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) interfaces.Repository {
+		db, err := database.New(filepath.Join(t.TempDir(), "test.db"))
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = db.Close() })
+		return db
+	})
+}
+
+// TestStoragePostgres runs against the server in TEST_POSTGRES_DSN, e.g. the container
+// started by make test-postgres; every subtest gets a schema of its own
+func TestStoragePostgres(t *testing.T) {
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN is not set")
+	}
+
+	admin, err := sql.Open(database.DriverPostgres, dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = admin.Close() })
+
+	storagetest.Run(t, func(t *testing.T) interfaces.Repository {
+		schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+		_, err := admin.Exec("CREATE SCHEMA " + schema)
+		require.NoError(t, err)
+		t.Cleanup(func() { _, _ = admin.Exec("DROP SCHEMA " + schema + " CASCADE") })
+
+		sep := "?"
+		if strings.Contains(dsn, "?") {
+			sep = "&"
+		}
+		db, err := database.New(dsn + sep + "search_path=" + schema)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = db.Close() })
+		require.Equal(t, database.DriverPostgres, db.Driver())
+		return db
+	})
+}
@@ -0,0 +1,197 @@
+// Package storagetest is a conformance suite for storage backends. Every backend must pass
+// it, so the commands behave the same on SQLite and PostgreSQL.
+package storagetest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"assemblyai-transcriber/internal/database"
+	"assemblyai-transcriber/internal/interfaces"
+)
+
+// Run runs the suite; open returns an empty, migrated storage for each subtest
+func Run(t *testing.T, open func(t *testing.T) interfaces.Repository) {
+	t.Run("setup", func(t *testing.T) {
+		require.NoError(t, open(t).Setup())
+	})
+
+	t.Run("transcriptions", func(t *testing.T) {
+		s := open(t)
+		first, err := s.InsertTranscription(&database.Transcription{FileName: "talk.mp4", Text: "hello"})
+		require.NoError(t, err)
+		second, err := s.InsertTranscription(&database.Transcription{
+			FileName: "meetup.mkv", Text: "world", AudioProfile: "speech", Options: `{"language_code":"de"}`, Language: "de",
+		})
+		require.NoError(t, err)
+		assert.Greater(t, second, first)
+
+		text, err := s.GetTranscription(first)
+		require.NoError(t, err)
+		assert.Equal(t, "hello", text)
+
+		record, err := s.GetTranscriptionRecord(second)
+		require.NoError(t, err)
+		assert.Equal(t, second, record.ID)
+		assert.Equal(t, "meetup.mkv", record.FileName)
+		assert.Equal(t, "world", record.Text)
+		assert.Equal(t, "speech", record.AudioProfile)
+		assert.Equal(t, `{"language_code":"de"}`, record.Options)
+		assert.Equal(t, "de", record.Language)
+		assert.False(t, record.CreatedAt.IsZero())
+
+		record, err = s.GetTranscriptionRecord(first)
+		require.NoError(t, err)
+		assert.Empty(t, record.AudioProfile, "empty metadata is stored as NULL and read back empty")
+
+		items, err := s.ListTranscriptions()
+		require.NoError(t, err)
+		require.Len(t, items, 2)
+		assert.Equal(t, first, items[0].ID)
+		assert.Equal(t, "meetup.mkv", items[1].FileName)
+		assert.Equal(t, "de", items[1].Language)
+		assert.False(t, items[1].HasTranslation)
+
+		_, err = s.GetTranscription(second + 100)
+		require.Error(t, err)
+		_, err = s.GetTranscriptionRecord(second + 100)
+		require.Error(t, err)
+	})
+
+	t.Run("segments, words and media", func(t *testing.T) {
+		s := open(t)
+		id, err := s.InsertTranscription(&database.Transcription{FileName: "talk.mp4", Text: "hello world"})
+		require.NoError(t, err)
+
+		require.NoError(t, s.SaveSegments(id, []database.Segment{
+			{Start: 0, End: 1200, Text: "hello", Confidence: 0.75},
+			{Start: 1200, End: 2500, Text: "world"},
+		}))
+		require.NoError(t, s.SaveWords(id, []database.Word{
+			{Start: 0, End: 1200, Text: "hello", Confidence: 0.5},
+			{Start: 1200, End: 2500, Text: "world", Confidence: 1},
+		}))
+
+		segments, err := s.GetSegments(id)
+		require.NoError(t, err)
+		require.Len(t, segments, 2)
+		assert.Equal(t, database.Segment{Index: 1, Start: 1200, End: 2500, Text: "world"}, segments[1])
+		assert.InDelta(t, 0.75, segments[0].Confidence, 0.001)
+		words, err := s.GetWords(id)
+		require.NoError(t, err)
+		require.Len(t, words, 2)
+		assert.Equal(t, "world", words[1].Text)
+		assert.InDelta(t, 0.5, words[0].Confidence, 0.001)
+
+		_, err = s.GetMedia(id)
+		require.Error(t, err)
+		media := &database.Media{TranscriptionID: id, DurationMS: 2500, Container: "mov,mp4", FileSize: 1024, SHA256: "abc"}
+		require.NoError(t, s.SaveMedia(media))
+		media.DurationMS = 3000
+		require.NoError(t, s.SaveMedia(media), "saving media again replaces it")
+		got, err := s.GetMedia(id)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3000), got.DurationMS)
+		assert.Equal(t, "mov,mp4", got.Container)
+
+		items, err := s.ListTranscriptions()
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		assert.Equal(t, int64(3000), items[0].DurationMS)
+		assert.Equal(t, int64(1024), items[0].FileSize)
+
+		// foreign keys are enforced
+		require.Error(t, s.SaveSegments(id+100, []database.Segment{{Text: "orphan"}}))
+		require.Error(t, s.SaveMedia(&database.Media{TranscriptionID: id + 100}))
+	})
+
+	t.Run("pending transcriptions", func(t *testing.T) {
+		s := open(t)
+		first, err := s.SavePending(&database.PendingTranscription{FileName: "a.mp4", TranscriptIDs: "t1", Submission: "{}"})
+		require.NoError(t, err)
+		second, err := s.SavePending(&database.PendingTranscription{FileName: "b.mp4", TranscriptIDs: "t2,t3", Submission: "{}"})
+		require.NoError(t, err)
+		assert.Greater(t, second, first)
+
+		pending, err := s.ListPending(database.PendingStatusPending)
+		require.NoError(t, err)
+		require.Len(t, pending, 2)
+		assert.Equal(t, first, pending[0].ID)
+		assert.Equal(t, "t2,t3", pending[1].TranscriptIDs)
+		assert.Equal(t, database.PendingStatusPending, pending[1].Status)
+
+		require.NoError(t, s.FailPending(first, "upload failed"))
+		failed, err := s.ListPending(database.PendingStatusFailed)
+		require.NoError(t, err)
+		require.Len(t, failed, 1)
+		assert.Equal(t, "upload failed", failed[0].Error)
+
+		require.NoError(t, s.DeletePending(second))
+		pending, err = s.ListPending(database.PendingStatusPending)
+		require.NoError(t, err)
+		assert.Empty(t, pending)
+	})
+
+	t.Run("terms", func(t *testing.T) {
+		s := open(t)
+		terms, err := s.GetAllTerms()
+		require.NoError(t, err)
+		assert.Empty(t, terms)
+
+		require.NoError(t, s.SaveTerm("API", "interface"))
+		require.NoError(t, s.SaveTerm("gRPC", "RPC framework"))
+		require.NoError(t, s.SaveTerm("API", "Application Programming Interface"))
+
+		terms, err = s.GetAllTerms()
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []map[string]string{
+			{"term": "API", "description": "Application Programming Interface"},
+			{"term": "gRPC", "description": "RPC framework"},
+		}, terms)
+
+		require.NoError(t, s.DeleteTerm("gRPC"))
+		require.Error(t, s.DeleteTerm("gRPC"))
+		terms, err = s.GetAllTerms()
+		require.NoError(t, err)
+		assert.Len(t, terms, 1)
+	})
+
+	t.Run("translations", func(t *testing.T) {
+		s := open(t)
+		id, err := s.InsertTranscription(&database.Transcription{FileName: "talk.mp4", Text: "hello"})
+		require.NoError(t, err)
+
+		_, err = s.GetTranslation(id)
+		require.Error(t, err)
+
+		require.NoError(t, s.SaveTranslation(id, "hallo"))
+		trID, err := s.InsertTranslation(&database.Translation{
+			TranscriptionID: id, TranslatedText: "guten Tag", Language: "de", Model: "test-model",
+		})
+		require.NoError(t, err)
+		text, err := s.GetTranslation(id)
+		require.NoError(t, err)
+		assert.Equal(t, "guten Tag", text, "the latest translation is returned")
+
+		translations, err := s.ListTranslations()
+		require.NoError(t, err)
+		require.Len(t, translations, 2)
+		assert.Equal(t, id, translations[1].TranscriptionID)
+		assert.Equal(t, trID, translations[1].ID)
+		assert.Equal(t, "de", translations[1].Language)
+		assert.Equal(t, "test-model", translations[1].Model)
+		assert.Empty(t, translations[0].Language, "the language of translations saved without one is empty")
+		assert.False(t, translations[1].Stale)
+		stale, err := s.ListStaleTranslations()
+		require.NoError(t, err)
+		assert.Empty(t, stale)
+		items, err := s.ListTranscriptions()
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		assert.True(t, items[0].HasTranslation)
+
+		require.Error(t, s.SaveTranslation(id+100, "orphan"))
+	})
+}
@@ -0,0 +1,45 @@
+package editor
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Command returns the text editor command based on OS and the EDITOR and VISUAL variables
+func Command() string {
+	switch runtime.GOOS {
+	case "windows":
+		return "notepad"
+	case "darwin":
+		return "open -W -a TextEdit"
+	default:
+		if editor := os.Getenv("EDITOR"); editor != "" {
+			return editor
+		}
+		if editor := os.Getenv("VISUAL"); editor != "" {
+			return editor
+		}
+		return "nano"
+	}
+}
+
+// Edit opens a file in the text editor and waits until it is closed. Editor commands
+// with arguments, such as "code --wait", are supported.
+func Edit(path string) error {
+	args := strings.Fields(Command())
+	args = append(args, filepath.Clean(path))
+	cmd := exec.Command(args[0], args[1:]...) // #nosec G204
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	fmt.Printf("Opening %s in %s. Save and exit the editor when finished.\n", filepath.Base(path), args[0])
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("error running editor: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"assemblyai-transcriber/internal/database"
+	"assemblyai-transcriber/internal/language"
+)
+
+// Document is a translation with the records it is rendered from
+type Document struct {
+	Transcription *database.Transcription
+	Translation   *database.Translation
+	Media         *database.Media // nil when the source file metadata is unknown
+	Tags          []string
+	Collections   []string
+}
+
+// Title returns the name of the source file without directory and extension
+func (d *Document) Title() string {
+	if d.Transcription.FileName == "" {
+		return fmt.Sprintf("Translation %d", d.Translation.ID)
+	}
+	base := filepath.Base(d.Transcription.FileName)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// SourceLanguage returns the stored or detected language of the transcription
+func (d *Document) SourceLanguage() string {
+	if d.Transcription.Language != "" {
+		return d.Transcription.Language
+	}
+	return language.Detect(d.Transcription.Text)
+}
+
+// TargetLanguage returns the stored or detected language of the translation; translations
+// made before the language was stored have none
+func (d *Document) TargetLanguage() string {
+	if d.Translation.Language != "" {
+		return d.Translation.Language
+	}
+	return language.Detect(d.Translation.TranslatedText)
+}
+
+// SpeechModel returns the AssemblyAI speech model stored with the transcription options
+func (d *Document) SpeechModel() string {
+	var opts struct {
+		SpeechModel string `json:"speech_model"`
+	}
+	if d.Transcription.Options == "" || json.Unmarshal([]byte(d.Transcription.Options), &opts) != nil {
+		return ""
+	}
+	return opts.SpeechModel
+}
+
+// Fields returns the metadata shown in the document header by the formats for readers;
+// Markdown writes the same data as YAML front matter
+func (d *Document) Fields() []Field {
+	var fields []Field
+	item := func(label, value string) {
+		if value != "" {
+			fields = append(fields, Field{Label: label, Value: value})
+		}
+	}
+	item("Source file", d.Transcription.FileName)
+	if !d.Translation.CreatedAt.IsZero() {
+		item("Translated", d.Translation.CreatedAt.UTC().Format(time.DateTime))
+	}
+	if d.Media != nil && d.Media.DurationMS > 0 {
+		item("Duration", FormatDuration(d.Media.DurationMS))
+	}
+	if lang := d.SourceLanguage(); lang != "" {
+		item("Source language", language.Name(lang))
+	}
+	if lang := d.TargetLanguage(); lang != "" {
+		item("Target language", language.Name(lang))
+	}
+	item("Speech model", d.SpeechModel())
+	item("Translation model", d.Translation.Model)
+	item("Tags", strings.Join(d.Tags, ", "))
+	item("Collections", strings.Join(d.Collections, ", "))
+	return fields
+}
+
+// Headings returns the column headings of the bilingual layout: the language names, or
+// Original and Translation when a language is unknown
+func (d *Document) Headings() (source, target string) {
+	source, target = "Original", "Translation"
+	if lang := d.SourceLanguage(); lang != "" {
+		source = language.Name(lang)
+	}
+	if lang := d.TargetLanguage(); lang != "" {
+		target = language.Name(lang)
+	}
+	return source, target
+}
+
+// Aligned splits the transcript and the translation into chunks to show side by side;
+// both slices have the same length
+func (d *Document) Aligned() (src, dst []string) {
+	src, dst = align(d.Transcription.Text, d.Translation.TranslatedText)
+	for len(src) < len(dst) {
+		src = append(src, "")
+	}
+	for len(dst) < len(src) {
+		dst = append(dst, "")
+	}
+	return src, dst
+}
+
+// align splits the source and the translation into chunks to show side by side. The
+// translation keeps the paragraphs of the source, so paragraphs are paired when both
+// sides have the same number of them. Otherwise the texts are split into sentences, and
+// the side with more sentences is grouped at the sentence boundaries of the other side,
+// measured in characters.
+func align(source, translated string) (src, dst []string) {
+	src, dst = paragraphs(source), paragraphs(translated)
+	if len(src) == len(dst) && len(src) > 1 {
+		return src, dst
+	}
+
+	src, dst = sentences(source), sentences(translated)
+	switch {
+	case len(src) == 0 || len(dst) == 0:
+		return paragraphs(source), paragraphs(translated)
+	case len(src) > len(dst):
+		src = regroup(src, dst)
+	case len(dst) > len(src):
+		dst = regroup(dst, src)
+	}
+	return src, dst
+}
+
+// regroup joins the chunks of long into len(short) groups whose boundaries are at the
+// same relative positions as the boundaries of short. Every group gets at least one
+// chunk.
+func regroup(long, short []string) []string {
+	longTotal, shortTotal := runeCount(long), runeCount(short)
+	groups := make([]string, 0, len(short))
+	start, longPos, shortPos := 0, 0, 0
+	for i, s := range short {
+		shortPos += utf8.RuneCountInString(s)
+		end := len(long)
+		if i < len(short)-1 {
+			// take chunks while their middle is before the boundary, leaving one for each
+			// following group
+			end = start + 1
+			longPos += utf8.RuneCountInString(long[start])
+			for end < len(long)-(len(short)-i-1) {
+				n := utf8.RuneCountInString(long[end])
+				if float64(longPos+n/2)/float64(longTotal) > float64(shortPos)/float64(shortTotal) {
+					break
+				}
+				longPos += n
+				end++
+			}
+		}
+		groups = append(groups, strings.Join(long[start:end], " "))
+		start = end
+	}
+	return groups
+}
+
+// runeCount returns the total number of characters of the chunks
+func runeCount(chunks []string) int {
+	n := 0
+	for _, c := range chunks {
+		n += utf8.RuneCountInString(c)
+	}
+	return max(n, 1)
+}
+
+// paragraphs splits text at blank lines
+func paragraphs(text string) []string {
+	var result []string
+	for _, p := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n\n") {
+		if p = strings.TrimSpace(p); p != "" {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// sentences splits text after sentence-ending punctuation followed by a space, and after
+// CJK full stops
+func sentences(text string) []string {
+	var result []string
+	runes := []rune(text)
+	start := 0
+	flush := func(end int) {
+		if s := strings.TrimSpace(string(runes[start:end])); s != "" {
+			result = append(result, s)
+		}
+		start = end
+	}
+	for i, r := range runes {
+		switch {
+		case strings.ContainsRune("。！？", r):
+			flush(i + 1)
+		case strings.ContainsRune(".!?…", r) && i+1 < len(runes) && unicode.IsSpace(runes[i+1]):
+			flush(i + 1)
+		}
+	}
+	flush(len(runes))
+	return result
+}
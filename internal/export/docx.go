@@ -0,0 +1,168 @@
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// docxFormat writes Word documents with the minimal parts of the Office Open XML package
+type docxFormat struct{}
+
+func (docxFormat) Ext() string { return ".docx" }
+func (docxFormat) Book() bool  { return false }
+
+const docxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>
+<Override PartName="/word/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml"/>
+<Override PartName="/docProps/core.xml" ContentType="application/vnd.openxmlformats-package.core-properties+xml"/>
+</Types>
+`
+
+const docxRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/package/2006/relationships/metadata/core-properties" Target="docProps/core.xml"/>
+</Relationships>
+`
+
+const docxDocumentRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>
+</Relationships>
+`
+
+const docxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+<w:docDefaults><w:rPrDefault><w:rPr><w:rFonts w:ascii="Georgia" w:hAnsi="Georgia" w:cs="Georgia"/><w:sz w:val="22"/></w:rPr></w:rPrDefault>
+<w:pPrDefault><w:pPr><w:spacing w:after="160" w:line="276" w:lineRule="auto"/></w:pPr></w:pPrDefault></w:docDefaults>
+<w:style w:type="paragraph" w:default="1" w:styleId="Normal"><w:name w:val="Normal"/></w:style>
+<w:style w:type="paragraph" w:styleId="Title"><w:name w:val="Title"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:pPr><w:spacing w:after="240"/></w:pPr><w:rPr><w:b/><w:sz w:val="40"/></w:rPr></w:style>
+<w:style w:type="paragraph" w:styleId="Heading1"><w:name w:val="heading 1"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:pPr><w:keepNext/><w:spacing w:before="360" w:after="120"/><w:outlineLvl w:val="0"/></w:pPr><w:rPr><w:b/><w:sz w:val="28"/></w:rPr></w:style>
+<w:style w:type="table" w:styleId="TableGrid"><w:name w:val="Table Grid"/><w:tblPr><w:tblBorders><w:top w:val="single" w:sz="4" w:space="0" w:color="CCCCCC"/><w:left w:val="single" w:sz="4" w:space="0" w:color="CCCCCC"/><w:bottom w:val="single" w:sz="4" w:space="0" w:color="CCCCCC"/><w:right w:val="single" w:sz="4" w:space="0" w:color="CCCCCC"/><w:insideH w:val="single" w:sz="4" w:space="0" w:color="CCCCCC"/><w:insideV w:val="single" w:sz="4" w:space="0" w:color="CCCCCC"/></w:tblBorders><w:tblCellMar><w:left w:w="100" w:type="dxa"/><w:right w:w="100" w:type="dxa"/></w:tblCellMar></w:tblPr></w:style>
+</w:styles>
+`
+
+// Render writes a Word document with the documents one after another
+func (docxFormat) Render(w io.Writer, title string, docs []*Document, opts Options) error {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n")
+	b.WriteString(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` + "\n<w:body>\n")
+	for _, doc := range docs {
+		writeDocxBody(&b, doc, opts)
+	}
+	b.WriteString("<w:sectPr/>\n</w:body>\n</w:document>\n")
+
+	core := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" xmlns:dc="http://purl.org/dc/elements/1.1/">
+<dc:title>` + xmlText(title) + `</dc:title>
+</cp:coreProperties>
+`
+	return writeZip(w, []zipEntry{
+		{name: "[Content_Types].xml", data: docxContentTypes},
+		{name: "_rels/.rels", data: docxRels},
+		{name: "docProps/core.xml", data: core},
+		{name: "word/_rels/document.xml.rels", data: docxDocumentRels},
+		{name: "word/styles.xml", data: docxStyles},
+		{name: "word/document.xml", data: b.String()},
+	})
+}
+
+// writeDocxBody writes the title, metadata, text and source media of a document
+func writeDocxBody(b *strings.Builder, doc *Document, opts Options) {
+	docxParagraph(b, "Title", doc.Title())
+	writeDocxFields(b, doc.Fields())
+
+	if opts.Bilingual {
+		source, target := doc.Headings()
+		rows := [][2]string{{source, target}}
+		src, dst := doc.Aligned()
+		for i := range src {
+			rows = append(rows, [2]string{src[i], dst[i]})
+		}
+		docxTable(b, rows, true)
+	} else {
+		for _, p := range paragraphs(doc.Translation.TranslatedText) {
+			docxParagraph(b, "", p)
+		}
+		if opts.Original {
+			docxParagraph(b, "Heading1", "Original transcript")
+			for _, p := range paragraphs(doc.Transcription.Text) {
+				docxParagraph(b, "", p)
+			}
+		}
+	}
+
+	if doc.Media != nil {
+		docxParagraph(b, "Heading1", "Source media")
+		writeDocxFields(b, mediaFields(doc.Media))
+	}
+}
+
+// writeDocxFields writes metadata as a two-column table
+func writeDocxFields(b *strings.Builder, fields []Field) {
+	if len(fields) == 0 {
+		return
+	}
+	rows := make([][2]string, len(fields))
+	for i, f := range fields {
+		rows[i] = [2]string{f.Label, f.Value}
+	}
+	docxTable(b, rows, false)
+	docxParagraph(b, "", "")
+}
+
+// docxTable writes a full-width two-column table, with a bold first row if header is set
+func docxTable(b *strings.Builder, rows [][2]string, header bool) {
+	b.WriteString(`<w:tbl><w:tblPr><w:tblStyle w:val="TableGrid"/><w:tblW w:w="5000" w:type="pct"/></w:tblPr>`)
+	b.WriteString(`<w:tblGrid><w:gridCol w:w="4680"/><w:gridCol w:w="4680"/></w:tblGrid>` + "\n")
+	for i, row := range rows {
+		b.WriteString("<w:tr>")
+		for _, text := range row {
+			b.WriteString(`<w:tc><w:tcPr><w:tcW w:w="2500" w:type="pct"/></w:tcPr><w:p>`)
+			writeDocxRuns(b, text, header && i == 0)
+			b.WriteString("</w:p></w:tc>")
+		}
+		b.WriteString("</w:tr>\n")
+	}
+	b.WriteString("</w:tbl>\n")
+}
+
+// docxParagraph writes a paragraph with an optional style, keeping line breaks of text
+func docxParagraph(b *strings.Builder, style, text string) {
+	b.WriteString("<w:p>")
+	if style != "" {
+		fmt.Fprintf(b, `<w:pPr><w:pStyle w:val="%s"/></w:pPr>`, style)
+	}
+	writeDocxRuns(b, text, false)
+	b.WriteString("</w:p>\n")
+}
+
+// writeDocxRuns writes the lines of text as one run separated by line breaks
+func writeDocxRuns(b *strings.Builder, text string, bold bool) {
+	if text == "" {
+		return
+	}
+	b.WriteString("<w:r>")
+	if bold {
+		b.WriteString("<w:rPr><w:b/></w:rPr>")
+	}
+	for i, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		if i > 0 {
+			b.WriteString("<w:br/>")
+		}
+		fmt.Fprintf(b, `<w:t xml:space="preserve">%s</w:t>`, xmlText(strings.TrimSpace(line)))
+	}
+	b.WriteString("</w:r>")
+}
+
+// xmlText escapes text for XML character data
+func xmlText(text string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(text)) // writing to a strings.Builder does not fail
+	return b.String()
+}
@@ -0,0 +1,102 @@
+package export
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"time"
+)
+
+// epubFormat writes an EPUB 3 book with one chapter per transcription
+type epubFormat struct{}
+
+func (epubFormat) Ext() string { return ".epub" }
+func (epubFormat) Book() bool  { return true }
+
+const epubContainer = `<?xml version="1.0" encoding="UTF-8"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+<rootfiles>
+<rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+</rootfiles>
+</container>
+`
+
+// Render writes the book. Its identifier is derived from the title and the translation
+// IDs and its modification time is that of the newest translation, so exporting the same
+// translations again gives the same file.
+func (epubFormat) Render(w io.Writer, title string, docs []*Document, opts Options) error {
+	lang := "und"
+	if len(docs) > 0 {
+		lang = htmlLanguage(docs[0])
+	}
+
+	id := sha256.New()
+	fmt.Fprint(id, title)
+	var modified time.Time
+	for _, doc := range docs {
+		fmt.Fprintf(id, "\n%d", doc.Translation.ID)
+		if doc.Translation.CreatedAt.After(modified) {
+			modified = doc.Translation.CreatedAt
+		}
+	}
+	if modified.IsZero() {
+		modified = time.Now()
+	}
+
+	var manifest, spine, toc strings.Builder
+	entries := []zipEntry{
+		{name: "mimetype", data: "application/epub+zip", stored: true},
+		{name: "META-INF/container.xml", data: epubContainer},
+		{name: "OEBPS/style.css", data: stylesheet},
+	}
+	for i, doc := range docs {
+		name := fmt.Sprintf("chapter-%d", i+1)
+		fmt.Fprintf(&manifest, "<item id=\"%s\" href=\"%s.xhtml\" media-type=\"application/xhtml+xml\"/>\n", name, name)
+		fmt.Fprintf(&spine, "<itemref idref=\"%s\"/>\n", name)
+		fmt.Fprintf(&toc, "<li><a href=\"%s.xhtml\">%s</a></li>\n", name, html.EscapeString(doc.Title()))
+
+		var body strings.Builder
+		writeHTMLBody(&body, doc, opts)
+		entries = append(entries, zipEntry{name: "OEBPS/" + name + ".xhtml", data: xhtmlPage(doc.Title(), htmlLanguage(doc), body.String())})
+	}
+
+	nav := fmt.Sprintf("<h1>%s</h1>\n<nav epub:type=\"toc\" id=\"toc\">\n<ol>\n%s</ol>\n</nav>\n", html.EscapeString(title), toc.String())
+	entries = append(entries,
+		zipEntry{name: "OEBPS/nav.xhtml", data: xhtmlPage(title, lang, nav)},
+		zipEntry{name: "OEBPS/content.opf", data: fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="book-id" xml:lang="%s">
+<metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+<dc:identifier id="book-id">urn:sha256:%x</dc:identifier>
+<dc:title>%s</dc:title>
+<dc:language>%s</dc:language>
+<meta property="dcterms:modified">%s</meta>
+</metadata>
+<manifest>
+<item id="nav" href="nav.xhtml" media-type="application/xhtml+xml" properties="nav"/>
+<item id="style" href="style.css" media-type="text/css"/>
+%s</manifest>
+<spine>
+%s</spine>
+</package>
+`, lang, id.Sum(nil)[:16], xmlText(title), lang, modified.UTC().Format(time.RFC3339), manifest.String(), spine.String())},
+	)
+	return writeZip(w, entries)
+}
+
+// xhtmlPage wraps a body in an XHTML document linking the stylesheet
+func xhtmlPage(title, lang, body string) string {
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" lang="%s" xml:lang="%s">
+<head>
+<meta charset="utf-8"/>
+<title>%s</title>
+<link rel="stylesheet" type="text/css" href="style.css"/>
+</head>
+<body>
+%s</body>
+</html>
+`, lang, lang, html.EscapeString(title), body)
+}
@@ -0,0 +1,345 @@
+package export
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"assemblyai-transcriber/internal/database"
+	"assemblyai-transcriber/internal/translation"
+)
+
+// Store defines database operations needed for export
+type Store interface {
+	ListTranslations() ([]database.Translation, error)
+	GetTranscriptionRecord(id int64) (*database.Transcription, error)
+	GetMedia(transcriptionID int64) (*database.Media, error)
+	GetTags(transcriptionID int64) ([]string, error)
+	GetCollections(transcriptionID int64) ([]string, error)
+}
+
+// Options control the export. The zero value writes translation_<id> files with the
+// translation only, for every translation.
+type Options struct {
+	// Suffix names the files after the source file, e.g. "{lang}" writes talk_ru.md for
+	// talk.mp4; {lang}, {id} (the transcription) and {translation} are replaced
+	Suffix     string
+	Original   bool   // append the original transcript
+	Bilingual  bool   // show the source and the translation side by side in a table
+	Tag        string // export only transcriptions with this tag
+	Collection string // export only transcriptions in this collection
+	Prune      bool   // remove the obsolete files of earlier exports
+}
+
+// Result lists the paths of the files of an export by what happened to them
+type Result struct {
+	Created   []string
+	Updated   []string
+	Unchanged []string
+	Removed   []string
+	// Obsolete files were written by earlier exports for translations that were deleted or
+	// became stale, or that are now written to files of other names; they are kept unless
+	// Prune is set
+	Obsolete []string
+}
+
+// Written returns the created and updated files
+func (r *Result) Written() []string {
+	return append(slices.Clone(r.Created), r.Updated...)
+}
+
+// Markdown writes every translation to outDir as a markdown file with YAML front matter,
+// see Export
+func Markdown(store Store, outDir string, opts Options) (*Result, error) {
+	return Export(store, outDir, formats["md"], opts)
+}
+
+// output is a file to export and the documents rendered into it
+type output struct {
+	name  string
+	title string
+	docs  []*Document
+}
+
+// Export writes the translations selected by opts to outDir in the given format.
+// Formats writing one file per translation name it after the translation or the source
+// file; book formats write the latest translation of every selected transcription into
+// one file named after the tag or collection. Stale translations of edited transcriptions
+// are skipped.
+//
+// The files written to outDir are recorded in StateFile, so later exports only write new
+// and changed files and find the obsolete files of deleted or stale translations and of
+// translations whose file name changed. Exports with different suffixes therefore need
+// output directories of their own. Concurrent exports to the same directory run one after
+// another. A failure to write one file does not stop the others; all errors are returned
+// together with the result.
+func Export(store Store, outDir string, format Format, opts Options) (*Result, error) {
+	result := &Result{}
+	if err := os.MkdirAll(outDir, 0o750); err != nil {
+		return result, fmt.Errorf("error creating output directory: %w", err)
+	}
+	defer lockDir(outDir)()
+	st, err := loadState(outDir)
+	if err != nil {
+		return result, err
+	}
+	docs, live, err := load(store, opts, format.Book())
+	if live == nil {
+		return result, err
+	}
+	errs := []error{err}
+
+	var outputs []output
+	if format.Book() && len(docs) > 0 {
+		title := bookTitle(opts)
+		outputs = append(outputs, output{name: safeName(title) + format.Ext(), title: title, docs: docs})
+	}
+	if !format.Book() {
+		used := map[string]bool{}
+		for _, doc := range docs {
+			name := fileName(doc, opts.Suffix, format.Ext())
+			if used[name] {
+				// several translations of one transcription or source files with the same name
+				name = fileName(doc, opts.Suffix+"_{translation}", format.Ext())
+			}
+			used[name] = true
+			outputs = append(outputs, output{name: name, title: doc.Title(), docs: []*Document{doc}})
+		}
+	}
+
+	rendered := map[int64]string{} // translation ID -> name of the file it is in this time
+	for _, out := range outputs {
+		ids := make([]int64, len(out.docs))
+		for i, doc := range out.docs {
+			ids[i] = doc.Translation.ID
+		}
+		path := filepath.Join(outDir, out.name)
+		var buf bytes.Buffer
+		if err := format.Render(&buf, out.title, out.docs, opts); err != nil {
+			errs = append(errs, fmt.Errorf("error rendering %s: %w", path, err))
+			continue
+		}
+		sum := sha256.Sum256(buf.Bytes())
+		hash := hex.EncodeToString(sum[:])
+
+		_, statErr := os.Stat(path)
+		exists := statErr == nil
+		if prev, ok := st.Files[out.name]; ok && prev.SHA256 == hash && exists {
+			result.Unchanged = append(result.Unchanged, path)
+			if !slices.Equal(prev.Translations, ids) {
+				prev.Translations = ids
+				st.Files[out.name] = prev
+			}
+		} else {
+			if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+				errs = append(errs, fmt.Errorf("error saving %s: %w", path, err))
+				continue
+			}
+			st.Files[out.name] = fileState{Translations: ids, SHA256: hash, ExportedAt: time.Now().UTC()}
+			if exists {
+				result.Updated = append(result.Updated, path)
+			} else {
+				result.Created = append(result.Created, path)
+			}
+		}
+		for _, id := range ids {
+			rendered[id] = out.name
+		}
+	}
+
+	// files rendered from translations that were all deleted or became stale, and files of
+	// this format whose translations were all written to files of other names this time:
+	// after a change of the suffix or of the source file name. Files of translations
+	// outside the selected tag or collection are kept.
+	for _, name := range slices.Sorted(maps.Keys(st.Files)) {
+		ids := st.Files[name].Translations
+		dead := !slices.ContainsFunc(ids, func(id int64) bool { return live[id] })
+		moved := filepath.Ext(name) == format.Ext() && len(ids) > 0 &&
+			!slices.ContainsFunc(ids, func(id int64) bool { return rendered[id] == "" || rendered[id] == name })
+		if !dead && !moved {
+			continue
+		}
+		path := filepath.Join(outDir, name)
+		if !opts.Prune {
+			result.Obsolete = append(result.Obsolete, path)
+			continue
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			errs = append(errs, fmt.Errorf("error removing %s: %w", path, err))
+			continue
+		}
+		delete(st.Files, name)
+		result.Removed = append(result.Removed, path)
+	}
+
+	if err := st.save(outDir); err != nil {
+		errs = append(errs, err)
+	}
+	return result, errors.Join(errs...)
+}
+
+// load reads the non-stale translations selected by the tag and collection of opts with
+// the records they are rendered from, and returns them with the IDs of all non-stale
+// translations. With latest only the newest translation of each transcription is kept.
+// Documents that cannot be loaded are left out and their errors returned together with
+// the others; live is nil when the translations cannot be listed.
+func load(store Store, opts Options, latest bool) (docs []*Document, live map[int64]bool, err error) {
+	translations, err := store.ListTranslations()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error querying translations: %w", err)
+	}
+
+	live = map[int64]bool{}
+	var (
+		index = map[int64]int{} // transcription ID -> position in docs, for latest
+		errs  []error
+	)
+	for _, t := range translations {
+		if t.Stale {
+			continue
+		}
+		live[t.ID] = true
+		doc := &Document{Translation: &t}
+		if doc.Transcription, err = store.GetTranscriptionRecord(t.TranscriptionID); err != nil {
+			errs = append(errs, fmt.Errorf("error querying transcription %d: %w", t.TranscriptionID, err))
+			continue
+		}
+		if doc.Tags, err = store.GetTags(t.TranscriptionID); err != nil {
+			errs = append(errs, fmt.Errorf("error querying tags of transcription %d: %w", t.TranscriptionID, err))
+			continue
+		}
+		if doc.Collections, err = store.GetCollections(t.TranscriptionID); err != nil {
+			errs = append(errs, fmt.Errorf("error querying collections of transcription %d: %w", t.TranscriptionID, err))
+			continue
+		}
+		if (opts.Tag != "" && !containsFold(doc.Tags, opts.Tag)) ||
+			(opts.Collection != "" && !containsFold(doc.Collections, opts.Collection)) {
+			continue
+		}
+		doc.Media, err = store.GetMedia(t.TranscriptionID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			errs = append(errs, fmt.Errorf("error querying media of transcription %d: %w", t.TranscriptionID, err))
+		}
+
+		if i, ok := index[t.TranscriptionID]; ok && latest {
+			docs[i] = doc // translations are listed oldest first
+			continue
+		}
+		index[t.TranscriptionID] = len(docs)
+		docs = append(docs, doc)
+	}
+	return docs, live, errors.Join(errs...)
+}
+
+// containsFold reports whether names contain name, ignoring case like tag lookups
+func containsFold(names []string, name string) bool {
+	return slices.ContainsFunc(names, func(n string) bool { return strings.EqualFold(n, name) })
+}
+
+// fileName returns the name of the file of a translation: translation_<id>, or the source
+// file name with the expanded suffix
+func fileName(doc *Document, suffix, ext string) string {
+	record, t := doc.Transcription, doc.Translation
+	if suffix == "" || record.FileName == "" {
+		return fmt.Sprintf("translation_%d%s", t.ID, ext)
+	}
+	suffix = strings.NewReplacer(
+		"{lang}", doc.TargetLanguage(),
+		"{id}", strconv.FormatInt(record.ID, 10),
+		"{translation}", strconv.FormatInt(t.ID, 10),
+	).Replace(suffix)
+	return translation.GenerateFileName(record.FileName, suffix, ext)
+}
+
+// bookTitle returns the title of a book: the selected collection or tag, or the library
+func bookTitle(opts Options) string {
+	switch {
+	case opts.Collection != "":
+		return opts.Collection
+	case opts.Tag != "":
+		return opts.Tag
+	default:
+		return "Translations"
+	}
+}
+
+// safeName replaces the characters of a title that are not safe in file names
+func safeName(title string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' || r == '.' {
+			return r
+		}
+		return '_'
+	}, title)
+}
+
+// Field is a labelled metadata value shown in the header of a document
+type Field struct {
+	Label string
+	Value string
+}
+
+// mediaFields returns the source file metadata that is known
+func mediaFields(m *database.Media) []Field {
+	var fields []Field
+	item := func(label, value string) {
+		if value != "" {
+			fields = append(fields, Field{Label: label, Value: value})
+		}
+	}
+	if m.DurationMS > 0 {
+		item("Duration", FormatDuration(m.DurationMS))
+	}
+	item("Container", m.Container)
+	item("Codecs", strings.Trim(m.VideoCodec+" / "+m.AudioCodec, " /"))
+	if m.BitRate > 0 {
+		item("Bitrate", fmt.Sprintf("%d kb/s", m.BitRate/1000))
+	}
+	if m.AudioChannels > 0 {
+		item("Audio channels", fmt.Sprint(m.AudioChannels))
+	}
+	if m.FileSize > 0 {
+		item("File size", FormatSize(m.FileSize))
+	}
+	item("SHA-256", m.SHA256)
+	if m.MediaCreatedAt != nil {
+		item("Created", m.MediaCreatedAt.UTC().Format(time.DateTime))
+	}
+	return fields
+}
+
+// FormatDuration formats milliseconds as h:mm:ss, or m:ss for durations under an hour
+func FormatDuration(ms int64) string {
+	d := (time.Duration(ms) * time.Millisecond).Round(time.Second)
+	h, m, s := int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%d:%02d", m, s)
+}
+
+// FormatSize formats a byte count with a binary unit, e.g. "1.5 MB"
+func FormatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	value, exp := float64(bytes)/unit, 0
+	for value >= unit && exp < 4 {
+		value /= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", value, "KMGTP"[exp])
+}
@@ -0,0 +1,405 @@
+package export
+
+import (
+	"database/sql"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"assemblyai-transcriber/internal/database"
+)
+
+type stubStore struct {
+	translations []database.Translation
+	records      map[int64]*database.Transcription
+	media        map[int64]*database.Media
+	tags         map[int64][]string
+	collections  map[int64][]string
+	err          error
+}
+
+func (s *stubStore) ListTranslations() ([]database.Translation, error) {
+	return s.translations, s.err
+}
+
+func (s *stubStore) GetTranscriptionRecord(id int64) (*database.Transcription, error) {
+	if r, ok := s.records[id]; ok {
+		return r, nil
+	}
+	return &database.Transcription{ID: id}, nil
+}
+
+func (s *stubStore) GetTags(transcriptionID int64) ([]string, error) {
+	return s.tags[transcriptionID], nil
+}
+
+func (s *stubStore) GetCollections(transcriptionID int64) ([]string, error) {
+	return s.collections[transcriptionID], nil
+}
+
+func (s *stubStore) GetMedia(transcriptionID int64) (*database.Media, error) {
+	m, ok := s.media[transcriptionID]
+	if !ok {
+		return nil, fmt.Errorf("error retrieving media: %w", sql.ErrNoRows)
+	}
+	return m, nil
+}
+
+func TestMarkdown(t *testing.T) {
+	outDir := filepath.Join(t.TempDir(), "out")
+	store := &stubStore{translations: []database.Translation{
+		{ID: 1, TranscriptionID: 10, TranslatedText: "# First"},
+		{ID: 2, TranscriptionID: 11, TranslatedText: "# Second"},
+		{ID: 3, TranscriptionID: 12, TranslatedText: "# Outdated", Stale: true},
+	}}
+
+	result, err := Markdown(store, outDir, Options{})
+	require.NoError(t, err)
+	written := result.Created
+	require.Equal(t, []string{
+		filepath.Join(outDir, "translation_1.md"),
+		filepath.Join(outDir, "translation_2.md"),
+	}, written)
+
+	data, err := os.ReadFile(written[1])
+	require.NoError(t, err)
+	require.Equal(t, "---\ntranscription_id: 11\ntranslation_id: 2\n---\n\n# Second", string(data))
+}
+
+func TestMarkdown_Media(t *testing.T) {
+	outDir := t.TempDir()
+	created := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	store := &stubStore{
+		translations: []database.Translation{{ID: 1, TranscriptionID: 10, TranslatedText: "# Talk"}},
+		media: map[int64]*database.Media{10: {
+			TranscriptionID: 10,
+			DurationMS:      3723000,
+			Container:       "matroska,webm",
+			VideoCodec:      "h264",
+			AudioCodec:      "opus",
+			BitRate:         1250000,
+			AudioChannels:   2,
+			FileSize:        1572864,
+			SHA256:          "abc123",
+			MediaCreatedAt:  &created,
+		}},
+	}
+
+	result, err := Markdown(store, outDir, Options{})
+	require.NoError(t, err)
+	written := result.Created
+	data, err := os.ReadFile(written[0])
+	require.NoError(t, err)
+	require.Equal(t, `---
+transcription_id: 10
+translation_id: 1
+duration: "1:02:03"
+---
+
+# Talk
+
+---
+
+**Source media**
+
+- Duration: 1:02:03
+- Container: matroska,webm
+- Codecs: h264 / opus
+- Bitrate: 1250 kb/s
+- Audio channels: 2
+- File size: 1.5 MB
+- SHA-256: abc123
+- Created: 2024-05-01 10:00:00
+`, string(data))
+}
+
+func TestMarkdown_FrontMatter(t *testing.T) {
+	outDir := t.TempDir()
+	created := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	store := &stubStore{
+		translations: []database.Translation{
+			{ID: 1, TranscriptionID: 10, TranslatedText: "Hallo Welt.", Language: "de", Model: "llama", CreatedAt: created},
+			{ID: 2, TranscriptionID: 10, TranslatedText: "Hallo, Welt.", Language: "de"},
+		},
+		records: map[int64]*database.Transcription{10: {
+			ID: 10, FileName: "/videos/my talk.mp4", Text: "Hello world.", Language: "en",
+			Options: `{"speech_model":"best","punctuate":true}`,
+		}},
+		tags: map[int64][]string{10: {"go", `say "hi"`}},
+	}
+
+	result, err := Markdown(store, outDir, Options{Suffix: "{lang}", Original: true})
+	require.NoError(t, err)
+	written := result.Created
+	require.Equal(t, []string{
+		filepath.Join(outDir, "my talk_de.md"),
+		filepath.Join(outDir, "my talk_de_2.md"),
+	}, written, "a second translation of the same file gets its ID appended")
+
+	data, err := os.ReadFile(written[0])
+	require.NoError(t, err)
+	require.Equal(t, `---
+title: "my talk"
+source_file: "/videos/my talk.mp4"
+transcription_id: 10
+translation_id: 1
+date: "2024-05-01T10:00:00Z"
+source_language: "en"
+target_language: "de"
+speech_model: "best"
+model: "llama"
+tags: ["go", "say \"hi\""]
+---
+
+Hallo Welt.
+
+---
+
+## Original transcript
+
+Hello world.`, string(data))
+}
+
+func TestMarkdown_Bilingual(t *testing.T) {
+	outDir := t.TempDir()
+	store := &stubStore{
+		translations: []database.Translation{{ID: 1, TranscriptionID: 10, TranslatedText: "Erster Absatz.\n\nZweiter | Absatz.", Language: "de"}},
+		records: map[int64]*database.Transcription{10: {
+			ID: 10, FileName: "talk.mp4", Text: "First paragraph.\n\nSecond | paragraph.", Language: "en",
+		}},
+	}
+
+	result, err := Markdown(store, outDir, Options{Bilingual: true})
+	require.NoError(t, err)
+	written := result.Created
+	data, err := os.ReadFile(written[0])
+	require.NoError(t, err)
+	require.Equal(t, `---
+title: "talk"
+source_file: "talk.mp4"
+transcription_id: 10
+translation_id: 1
+source_language: "en"
+target_language: "de"
+---
+
+| English | German |
+| --- | --- |
+| First paragraph. | Erster Absatz. |
+| Second \| paragraph. | Zweiter \| Absatz. |`, string(data))
+}
+
+func TestAlign(t *testing.T) {
+	tests := []struct {
+		name             string
+		source, target   string
+		wantSrc, wantDst []string
+	}{
+		{
+			name:    "paragraphs",
+			source:  "One. Two.\n\nThree.",
+			target:  "Eins. Zwei.\n\nDrei.",
+			wantSrc: []string{"One. Two.", "Three."},
+			wantDst: []string{"Eins. Zwei.", "Drei."},
+		},
+		{
+			name:    "sentences of one paragraph",
+			source:  "One. Two? Three!",
+			target:  "Eins. Zwei? Drei!",
+			wantSrc: []string{"One.", "Two?", "Three!"},
+			wantDst: []string{"Eins.", "Zwei?", "Drei!"},
+		},
+		{
+			name:    "translation merged two sentences",
+			source:  "A short one. Another short one. And a much longer sentence at the end.",
+			target:  "Ein kurzer und noch ein kurzer Satz. Und ein viel längerer Satz am Ende.",
+			wantSrc: []string{"A short one. Another short one.", "And a much longer sentence at the end."},
+			wantDst: []string{"Ein kurzer und noch ein kurzer Satz.", "Und ein viel längerer Satz am Ende."},
+		},
+		{
+			name:    "translation split a sentence",
+			source:  "Hello. This is a long sentence, and it goes on.",
+			target:  "Hallo. Das ist ein langer Satz. Und er geht weiter.",
+			wantSrc: []string{"Hello.", "This is a long sentence, and it goes on."},
+			wantDst: []string{"Hallo.", "Das ist ein langer Satz. Und er geht weiter."},
+		},
+		{
+			name:    "cjk full stops",
+			source:  "Hello. World.",
+			target:  "你好。世界。",
+			wantSrc: []string{"Hello.", "World."},
+			wantDst: []string{"你好。", "世界。"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src, dst := align(tt.source, tt.target)
+			require.Equal(t, tt.wantSrc, src)
+			require.Equal(t, tt.wantDst, dst)
+		})
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	require.Equal(t, "512 B", FormatSize(512))
+	require.Equal(t, "1.0 KB", FormatSize(1024))
+	require.Equal(t, "2.3 GB", FormatSize(2469606195))
+	require.Equal(t, "0:59", FormatDuration(59400))
+}
+
+func TestMarkdown_StoreError(t *testing.T) {
+	_, err := Markdown(&stubStore{err: fmt.Errorf("db closed")}, t.TempDir(), Options{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "db closed")
+}
+
+func TestMarkdown_Incremental(t *testing.T) {
+	outDir := t.TempDir()
+	path := func(name string) string { return filepath.Join(outDir, name) }
+	store := &stubStore{
+		translations: []database.Translation{
+			{ID: 1, TranscriptionID: 10, TranslatedText: "# First"},
+			{ID: 2, TranscriptionID: 11, TranslatedText: "# Second"},
+		},
+		tags: map[int64][]string{10: {"go"}},
+	}
+
+	result, err := Markdown(store, outDir, Options{})
+	require.NoError(t, err)
+	require.Equal(t, []string{path("translation_1.md"), path("translation_2.md")}, result.Created)
+	require.FileExists(t, path(StateFile))
+
+	// nothing changed
+	result, err = Markdown(store, outDir, Options{})
+	require.NoError(t, err)
+	require.Empty(t, result.Written())
+	require.Equal(t, []string{path("translation_1.md"), path("translation_2.md")}, result.Unchanged)
+
+	// new metadata changes the file, a deleted file is written again
+	store.tags[11] = []string{"meetup"}
+	require.NoError(t, os.Remove(path("translation_1.md")))
+	result, err = Markdown(store, outDir, Options{})
+	require.NoError(t, err)
+	require.Equal(t, []string{path("translation_1.md")}, result.Created)
+	require.Equal(t, []string{path("translation_2.md")}, result.Updated)
+
+	// a new translation replaces the first one, which is only removed with Prune
+	store.translations[0] = database.Translation{ID: 3, TranscriptionID: 10, TranslatedText: "# First, again"}
+	result, err = Markdown(store, outDir, Options{Tag: "go"})
+	require.NoError(t, err)
+	require.Equal(t, []string{path("translation_3.md")}, result.Created)
+	require.Equal(t, []string{path("translation_1.md")}, result.Obsolete,
+		"files of translations outside the tag are not obsolete")
+	require.FileExists(t, path("translation_1.md"))
+
+	result, err = Markdown(store, outDir, Options{Tag: "go", Prune: true})
+	require.NoError(t, err)
+	require.Equal(t, []string{path("translation_1.md")}, result.Removed)
+	require.Empty(t, result.Obsolete)
+	require.NoFileExists(t, path("translation_1.md"))
+	require.FileExists(t, path("translation_2.md"))
+
+	st, err := loadState(outDir)
+	require.NoError(t, err)
+	require.Equal(t, []string{"translation_2.md", "translation_3.md"}, slices.Sorted(maps.Keys(st.Files)))
+	require.Equal(t, []int64{3}, st.Files["translation_3.md"].Translations)
+}
+
+func TestMarkdown_Renamed(t *testing.T) {
+	outDir := t.TempDir()
+	path := func(name string) string { return filepath.Join(outDir, name) }
+	store := &stubStore{
+		translations: []database.Translation{
+			{ID: 1, TranscriptionID: 10, TranslatedText: "Hallo.", Language: "de"},
+			{ID: 2, TranscriptionID: 11, TranslatedText: "Tschüss.", Language: "de"},
+		},
+		records: map[int64]*database.Transcription{
+			10: {ID: 10, FileName: "talk.mp4", Text: "Hello."},
+			11: {ID: 11, FileName: "outro.mp4", Text: "Bye."},
+		},
+	}
+	_, err := Markdown(store, outDir, Options{})
+	require.NoError(t, err)
+	_, err = Export(store, outDir, formats["txt"], Options{})
+	require.NoError(t, err)
+
+	// the translations are now named after the source files
+	result, err := Markdown(store, outDir, Options{Suffix: "{lang}", Prune: true})
+	require.NoError(t, err)
+	require.Equal(t, []string{path("talk_de.md"), path("outro_de.md")}, result.Created)
+	require.Equal(t, []string{path("translation_1.md"), path("translation_2.md")}, result.Removed)
+	require.FileExists(t, path("translation_1.txt"), "files of other formats are kept")
+
+	// the second translation of a file gets the plain name once the first one is deleted
+	store.translations = append(store.translations, database.Translation{ID: 3, TranscriptionID: 10, TranslatedText: "Hallo!", Language: "de"})
+	result, err = Markdown(store, outDir, Options{Suffix: "{lang}", Prune: true})
+	require.NoError(t, err)
+	require.Equal(t, []string{path("talk_de_3.md")}, result.Created)
+	store.translations = store.translations[1:]
+	result, err = Markdown(store, outDir, Options{Suffix: "{lang}", Prune: true})
+	require.NoError(t, err)
+	require.Equal(t, []string{path("talk_de.md")}, result.Updated)
+	require.Equal(t, []string{path("talk_de_3.md"), path("translation_1.txt")}, result.Removed,
+		"files of deleted translations are removed in every format")
+
+	// a tag filter that leaves out a translation does not make its file obsolete
+	result, err = Markdown(store, outDir, Options{Suffix: "{lang}", Tag: "missing", Prune: true})
+	require.NoError(t, err)
+	require.Empty(t, result.Removed)
+	require.FileExists(t, path("outro_de.md"))
+}
+
+func TestExport_Concurrent(t *testing.T) {
+	outDir := t.TempDir()
+	store := &stubStore{translations: []database.Translation{{ID: 1, TranscriptionID: 10, TranslatedText: "# First"}}}
+	var wg sync.WaitGroup
+	errs := make([]error, 8)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = Export(store, outDir, formats[FormatNames()[i%len(formats)]], Options{})
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+
+	st, err := loadState(outDir)
+	require.NoError(t, err)
+	require.Len(t, st.Files, len(formats), "every export recorded its file")
+	entries, err := os.ReadDir(outDir)
+	require.NoError(t, err)
+	require.Len(t, entries, len(formats)+1, "no temporary state files are left")
+}
+
+func TestLoadState_Newer(t *testing.T) {
+	outDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(outDir, StateFile), []byte(`{"version": 99}`), 0o600))
+	_, err := Markdown(&stubStore{}, outDir, Options{})
+	require.ErrorContains(t, err, "export state version 99")
+}
+
+func TestWriteList(t *testing.T) {
+	created := time.Date(2024, 5, 1, 10, 0, 0, 0, time.Local)
+	var b strings.Builder
+	err := WriteList(&b, []database.TranscriptionListItem{
+		{ID: 1, FileName: "talk.mp4", DurationMS: 62000, Container: "mp4", FileSize: 2048, Language: "en",
+			HasTranslation: true, Stale: true, Tags: "go,meetup", CreatedAt: created},
+		{ID: 2, FileName: "notes.txt", CreatedAt: created},
+	})
+	require.NoError(t, err)
+	require.Equal(t, `ID  FILE       DURATION  CONTAINER  SIZE    PROFILE  LANGUAGE  TRANSLATED  TAGS       CREATED
+1   talk.mp4   1:02      mp4        2.0 KB  -        en        stale       go,meetup  2024-05-01 10:00:00
+2   notes.txt  -         -          -       -        -         no          -          2024-05-01 10:00:00
+`, b.String())
+}
@@ -0,0 +1,45 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// Format renders translations as one file type. Book formats get every selected
+// translation at once and write a single file titled after the tag or collection; the
+// others are called with one translation per file.
+type Format interface {
+	Ext() string // file name extension including the dot
+	Book() bool
+	Render(w io.Writer, title string, docs []*Document, opts Options) error
+}
+
+// formats are the export formats by name
+var formats = map[string]Format{
+	"md":   markdownFormat{},
+	"html": htmlFormat{},
+	"docx": docxFormat{},
+	"epub": epubFormat{},
+	"txt":  textFormat{},
+}
+
+// Lookup returns the export format with the given name
+func Lookup(name string) (Format, error) {
+	f, ok := formats[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown export format %q, expected one of %s", name, strings.Join(FormatNames(), ", "))
+	}
+	return f, nil
+}
+
+// FormatNames returns the names of the export formats in alphabetical order
+func FormatNames() []string {
+	names := make([]string, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,187 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"encoding/xml"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"assemblyai-transcriber/internal/database"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// goldenStore holds two translated talks in a collection; the keynote is tagged and has
+// media metadata
+func goldenStore() *stubStore {
+	created := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	return &stubStore{
+		translations: []database.Translation{
+			{ID: 1, TranscriptionID: 10, TranslatedText: "Willkommen zum Vortrag.\n\nHeute geht es um <Go> & Tests.",
+				Language: "de", Model: "llama", CreatedAt: created},
+			{ID: 2, TranscriptionID: 11, TranslatedText: "Kurze Notiz.\nZweite Zeile.", Language: "de", CreatedAt: created.Add(time.Hour)},
+			{ID: 3, TranscriptionID: 12, TranslatedText: "Veraltet.", Language: "de", Stale: true},
+		},
+		records: map[int64]*database.Transcription{
+			10: {ID: 10, FileName: "videos/keynote.mp4", Text: "Welcome to the talk.\n\nToday it is about <Go> & tests.",
+				Language: "en", Options: `{"speech_model":"best"}`},
+			11: {ID: 11, FileName: "notes.txt", Text: "Short note.\nSecond line.", Language: "en"},
+			12: {ID: 12, FileName: "old.mp4", Text: "Outdated.", Language: "en"},
+		},
+		media: map[int64]*database.Media{10: {TranscriptionID: 10, DurationMS: 754000, Container: "mp4", FileSize: 2048}},
+		tags:  map[int64][]string{10: {"conference", "go"}},
+		collections: map[int64][]string{
+			10: {"2024 talks"},
+			11: {"2024 talks"},
+			12: {"2024 talks"},
+		},
+	}
+}
+
+func TestFormats_Golden(t *testing.T) {
+	tests := []struct {
+		format string
+		opts   Options
+		golden string // also the name of the written file
+	}{
+		{format: "md", opts: Options{Tag: "go", Original: true}, golden: "translation_1.md"},
+		{format: "md", opts: Options{Tag: "go", Suffix: "{lang}", Bilingual: true}, golden: "keynote_de.md"},
+		{format: "html", opts: Options{Tag: "go", Original: true}, golden: "translation_1.html"},
+		{format: "html", opts: Options{Tag: "go", Suffix: "{lang}", Bilingual: true}, golden: "keynote_de.html"},
+		{format: "txt", opts: Options{Tag: "go", Original: true}, golden: "translation_1.txt"},
+		{format: "txt", opts: Options{Tag: "go", Suffix: "{lang}", Bilingual: true}, golden: "keynote_de.txt"},
+		{format: "docx", opts: Options{Tag: "go", Suffix: "{lang}", Bilingual: true}, golden: "keynote_de.docx"},
+		{format: "epub", opts: Options{Collection: "2024 talks"}, golden: "2024_talks.epub"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			format, err := Lookup(tt.format)
+			require.NoError(t, err)
+			outDir := t.TempDir()
+
+			result, err := Export(goldenStore(), outDir, format, tt.opts)
+			require.NoError(t, err)
+			written := result.Created
+			require.Equal(t, []string{filepath.Join(outDir, tt.golden)}, written)
+			data := readExport(t, written[0])
+
+			path := filepath.Join("testdata", tt.golden+".golden")
+			if *update {
+				require.NoError(t, os.WriteFile(path, data, 0o600))
+			}
+			want, err := os.ReadFile(path)
+			require.NoError(t, err)
+			require.Equal(t, string(want), string(data))
+
+			// rendering again gives the same file, so it is not written again
+			result, err = Export(goldenStore(), outDir, format, tt.opts)
+			require.NoError(t, err)
+			require.Equal(t, written, result.Unchanged)
+			require.Empty(t, result.Written())
+		})
+	}
+}
+
+// readExport reads an exported file; zip containers are listed entry by entry with their
+// compression method and content, after checking that their XML parts are well-formed
+func readExport(t *testing.T, path string) []byte {
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	if ext := filepath.Ext(path); ext != ".docx" && ext != ".epub" {
+		return data
+	}
+
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	var b bytes.Buffer
+	for _, f := range r.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+		if ext := filepath.Ext(f.Name); ext == ".xml" || ext == ".rels" || ext == ".xhtml" || ext == ".opf" {
+			dec := xml.NewDecoder(bytes.NewReader(content))
+			for {
+				_, err := dec.Token()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				require.NoError(t, err, "%s is not well-formed", f.Name)
+			}
+		}
+		method := "deflate"
+		if f.Method == zip.Store {
+			method = "store"
+			requireRawEntry(t, data, f, content)
+		}
+		fmt.Fprintf(&b, "=== %s (%s)\n%s\n", f.Name, method, content)
+	}
+	return b.Bytes()
+}
+
+// requireRawEntry checks that the content of a stored entry follows its local file header
+// directly, without extra fields or a data descriptor, as the EPUB mimetype must
+func requireRawEntry(t *testing.T, data []byte, f *zip.File, content []byte) {
+	offset, err := f.DataOffset()
+	require.NoError(t, err)
+	header := int(offset) - 30 - len(f.Name)
+	require.GreaterOrEqual(t, header, 0)
+	require.Equal(t, "PK\x03\x04", string(data[header:header+4]), "%s has extra fields", f.Name)
+	require.Zero(t, binary.LittleEndian.Uint16(data[header+6:])&0x8, "%s has a data descriptor", f.Name)
+	require.Zero(t, binary.LittleEndian.Uint16(data[header+28:]), "%s has extra fields", f.Name)
+	require.Equal(t, f.Name, string(data[header+30:offset]))
+	require.Equal(t, content, data[offset:int(offset)+len(content)])
+}
+
+func TestExport_Book(t *testing.T) {
+	store := goldenStore()
+	// a newer translation of the keynote replaces the older one in the book
+	store.translations = append(store.translations, database.Translation{
+		ID: 4, TranscriptionID: 10, TranslatedText: "Neue Fassung.", Language: "de",
+	})
+	format, err := Lookup("epub")
+	require.NoError(t, err)
+	outDir := t.TempDir()
+
+	result, err := Export(store, outDir, format, Options{})
+	require.NoError(t, err)
+	written := result.Created
+	require.Equal(t, []string{filepath.Join(outDir, "Translations.epub")}, written)
+	data, err := os.ReadFile(written[0])
+	require.NoError(t, err)
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	require.Equal(t, "mimetype", r.File[0].Name, "the mimetype comes first")
+	require.Equal(t, "PK\x03\x04", string(data[:4]))
+	require.Equal(t, "mimetypeapplication/epub+zip", string(data[30:58]),
+		"the mimetype is readable at a fixed offset for magic number detection")
+	dump := string(readExport(t, written[0]))
+	require.Contains(t, dump, "Neue Fassung.")
+	require.NotContains(t, dump, "Willkommen")
+	require.Contains(t, dump, "chapter-2.xhtml")
+	require.NotContains(t, dump, "chapter-3.xhtml", "stale translations are left out")
+
+	result, err = Export(store, outDir, format, Options{Tag: "missing"})
+	require.NoError(t, err)
+	require.Empty(t, result.Written())
+}
+
+func TestLookup(t *testing.T) {
+	require.Equal(t, []string{"docx", "epub", "html", "md", "txt"}, FormatNames())
+	f, err := Lookup("HTML")
+	require.NoError(t, err)
+	require.Equal(t, ".html", f.Ext())
+	_, err = Lookup("pdf")
+	require.ErrorContains(t, err, `unknown export format "pdf"`)
+}
@@ -0,0 +1,110 @@
+package export
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"assemblyai-transcriber/internal/language"
+)
+
+// stylesheet is embedded in HTML files and shipped with EPUB books
+const stylesheet = `body { font-family: Georgia, "Times New Roman", serif; line-height: 1.6; color: #222; max-width: 46em; margin: 2em auto; padding: 0 1em; }
+h1 { font-size: 1.8em; margin-bottom: 0.5em; }
+h2 { font-size: 1.3em; margin-top: 2em; }
+table { border-collapse: collapse; width: 100%; margin: 1em 0; }
+th, td { border: 1px solid #ccc; padding: 0.4em 0.6em; text-align: left; vertical-align: top; }
+table.meta th { width: 12em; background: #f5f5f5; font-weight: normal; color: #555; }
+table.bilingual td { width: 50%; }
+`
+
+// htmlFormat writes self-contained HTML pages with the stylesheet embedded
+type htmlFormat struct{}
+
+func (htmlFormat) Ext() string { return ".html" }
+func (htmlFormat) Book() bool  { return false }
+
+// Render writes an HTML page with the documents one after another
+func (htmlFormat) Render(w io.Writer, title string, docs []*Document, opts Options) error {
+	var b strings.Builder
+	lang := "und"
+	if len(docs) > 0 {
+		lang = htmlLanguage(docs[0])
+	}
+	fmt.Fprintf(&b, "<!DOCTYPE html>\n<html lang=\"%s\">\n<head>\n<meta charset=\"utf-8\">\n", lang)
+	fmt.Fprintf(&b, "<meta name=\"viewport\" content=\"width=device-width, initial-scale=1\">\n<title>%s</title>\n", html.EscapeString(title))
+	fmt.Fprintf(&b, "<style>\n%s</style>\n</head>\n<body>\n", stylesheet)
+	for _, doc := range docs {
+		writeHTMLBody(&b, doc, opts)
+	}
+	b.WriteString("</body>\n</html>\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeHTMLBody writes the title, metadata, text and source media of a document as
+// markup that is also valid XHTML, so EPUB chapters use it too
+func writeHTMLBody(b *strings.Builder, doc *Document, opts Options) {
+	fmt.Fprintf(b, "<h1>%s</h1>\n", html.EscapeString(doc.Title()))
+	writeHTMLFields(b, doc.Fields())
+
+	if opts.Bilingual {
+		source, target := doc.Headings()
+		fmt.Fprintf(b, "<table class=\"bilingual\">\n<thead><tr><th>%s</th><th>%s</th></tr></thead>\n<tbody>\n",
+			html.EscapeString(source), html.EscapeString(target))
+		src, dst := doc.Aligned()
+		for i := range src {
+			fmt.Fprintf(b, "<tr><td>%s</td><td>%s</td></tr>\n", htmlText(src[i]), htmlText(dst[i]))
+		}
+		b.WriteString("</tbody>\n</table>\n")
+	} else {
+		writeHTMLParagraphs(b, doc.Translation.TranslatedText)
+		if opts.Original {
+			b.WriteString("<h2>Original transcript</h2>\n")
+			writeHTMLParagraphs(b, doc.Transcription.Text)
+		}
+	}
+
+	if doc.Media != nil {
+		b.WriteString("<h2>Source media</h2>\n")
+		writeHTMLFields(b, mediaFields(doc.Media))
+	}
+}
+
+// writeHTMLFields writes metadata as a two-column table
+func writeHTMLFields(b *strings.Builder, fields []Field) {
+	if len(fields) == 0 {
+		return
+	}
+	b.WriteString("<table class=\"meta\">\n")
+	for _, f := range fields {
+		fmt.Fprintf(b, "<tr><th>%s</th><td>%s</td></tr>\n", html.EscapeString(f.Label), html.EscapeString(f.Value))
+	}
+	b.WriteString("</table>\n")
+}
+
+// writeHTMLParagraphs writes the paragraphs of a text, keeping its line breaks
+func writeHTMLParagraphs(b *strings.Builder, text string) {
+	for _, p := range paragraphs(text) {
+		fmt.Fprintf(b, "<p>%s</p>\n", htmlText(p))
+	}
+}
+
+// htmlText escapes text and turns its line breaks into <br/>
+func htmlText(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	for i, line := range lines {
+		lines[i] = html.EscapeString(strings.TrimSpace(line))
+	}
+	return strings.Join(lines, "<br/>")
+}
+
+// htmlLanguage returns the language tag of the translation for the lang attribute, or
+// "und" when it is unknown
+func htmlLanguage(doc *Document) string {
+	if lang := language.Normalize(doc.TargetLanguage()); lang != "" {
+		return lang
+	}
+	return "und"
+}
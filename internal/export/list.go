@@ -0,0 +1,51 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"assemblyai-transcriber/internal/database"
+)
+
+// WriteList writes transcriptions as an aligned table with their media summary, language,
+// translation status and tags
+func WriteList(w io.Writer, items []database.TranscriptionListItem) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tFILE\tDURATION\tCONTAINER\tSIZE\tPROFILE\tLANGUAGE\tTRANSLATED\tTAGS\tCREATED")
+	for _, item := range items {
+		duration, size := "-", "-"
+		if item.DurationMS > 0 {
+			duration = FormatDuration(item.DurationMS)
+		}
+		if item.FileSize > 0 {
+			size = FormatSize(item.FileSize)
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			item.ID, item.FileName, duration, orDash(item.Container), size, orDash(item.AudioProfile), orDash(item.Language),
+			TranslationStatus(item), orDash(item.Tags), item.CreatedAt.Local().Format(time.DateTime))
+	}
+	return tw.Flush()
+}
+
+// TranslationStatus describes whether a transcription is translated: "yes", "no" or
+// "stale" when it was edited after the latest translation
+func TranslationStatus(item database.TranscriptionListItem) string {
+	switch {
+	case item.Stale:
+		return "stale"
+	case item.HasTranslation:
+		return "yes"
+	default:
+		return "no"
+	}
+}
+
+// orDash returns a placeholder for empty table cells
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,111 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"assemblyai-transcriber/internal/database"
+)
+
+// markdownFormat writes the translation text as it is, with YAML front matter for static
+// site generators and note-taking tools
+type markdownFormat struct{}
+
+func (markdownFormat) Ext() string { return ".md" }
+func (markdownFormat) Book() bool  { return false }
+
+// Render writes the front matter, the translation or the bilingual table, and the source
+// media metadata of each document
+func (markdownFormat) Render(w io.Writer, _ string, docs []*Document, opts Options) error {
+	var b strings.Builder
+	for i, doc := range docs {
+		if i > 0 {
+			b.WriteString("\n\n")
+		}
+		b.WriteString(frontMatter(doc))
+		if opts.Bilingual {
+			b.WriteString(markdownTable(doc))
+		} else {
+			b.WriteString(doc.Translation.TranslatedText)
+			if opts.Original {
+				b.WriteString("\n\n---\n\n## Original transcript\n\n" + doc.Transcription.Text)
+			}
+		}
+		if doc.Media != nil {
+			b.WriteString(mediaSection(doc.Media))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// frontMatter renders the YAML front matter of a document. Strings are double-quoted, so
+// file names and tags need no further escaping.
+func frontMatter(doc *Document) string {
+	var b strings.Builder
+	b.WriteString("---\n")
+	field := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, "%s: %s\n", name, strconv.Quote(value))
+		}
+	}
+	record, t := doc.Transcription, doc.Translation
+	base := filepath.Base(record.FileName)
+	if record.FileName != "" {
+		field("title", strings.TrimSuffix(base, filepath.Ext(base)))
+	}
+	field("source_file", record.FileName)
+	fmt.Fprintf(&b, "transcription_id: %d\n", record.ID)
+	fmt.Fprintf(&b, "translation_id: %d\n", t.ID)
+	if !t.CreatedAt.IsZero() {
+		field("date", t.CreatedAt.UTC().Format(time.RFC3339))
+	}
+	if doc.Media != nil && doc.Media.DurationMS > 0 {
+		field("duration", FormatDuration(doc.Media.DurationMS))
+	}
+	field("source_language", doc.SourceLanguage())
+	field("target_language", doc.TargetLanguage())
+	field("speech_model", doc.SpeechModel())
+	field("model", t.Model)
+	if len(doc.Tags) > 0 {
+		quoted := make([]string, len(doc.Tags))
+		for i, tag := range doc.Tags {
+			quoted[i] = strconv.Quote(tag)
+		}
+		fmt.Fprintf(&b, "tags: [%s]\n", strings.Join(quoted, ", "))
+	}
+	b.WriteString("---\n\n")
+	return b.String()
+}
+
+// markdownTable renders the source and the translation as a two-column table of aligned
+// chunks. Like the translation text, the table ends without a newline.
+func markdownTable(doc *Document) string {
+	var b strings.Builder
+	source, target := doc.Headings()
+	fmt.Fprintf(&b, "| %s | %s |\n| --- | --- |", source, target)
+	src, dst := doc.Aligned()
+	for i := range src {
+		fmt.Fprintf(&b, "\n| %s | %s |", cell(src[i]), cell(dst[i]))
+	}
+	return b.String()
+}
+
+// cell returns a chunk as a table cell on a single line
+func cell(chunk string) string {
+	return strings.ReplaceAll(singleLine(chunk), "|", `\|`)
+}
+
+// mediaSection renders the source file metadata as a markdown list after the translation
+func mediaSection(m *database.Media) string {
+	var b strings.Builder
+	b.WriteString("\n\n---\n\n**Source media**\n\n")
+	for _, f := range mediaFields(m) {
+		fmt.Fprintf(&b, "- %s: %s\n", f.Label, f.Value)
+	}
+	return b.String()
+}
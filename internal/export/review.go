@@ -0,0 +1,203 @@
+package export
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"assemblyai-transcriber/internal/database"
+)
+
+// DefaultConfidenceThreshold is the confidence below which words are marked for review
+const DefaultConfidenceThreshold = 0.6
+
+// Review report formats
+const (
+	ReviewMarkdown = "md"
+	ReviewHTML     = "html"
+)
+
+// ReviewStore defines database operations needed for the confidence review
+type ReviewStore interface {
+	GetTranscriptionRecord(id int64) (*database.Transcription, error)
+	GetSegments(transcriptionID int64) ([]database.Segment, error)
+	GetWords(transcriptionID int64) ([]database.Word, error)
+}
+
+// reviewSegment is a segment with its words
+type reviewSegment struct {
+	database.Segment
+	words []database.Word
+}
+
+// Review renders a transcription for proofreading in the given format, with runs of words
+// recognized with a confidence below threshold highlighted and a list of the segments
+// that contain them. Transcriptions saved without word confidences are rendered unmarked.
+func Review(store ReviewStore, id int64, threshold float64, format string) (string, error) {
+	if format != ReviewMarkdown && format != ReviewHTML {
+		return "", fmt.Errorf("unknown review format %q", format)
+	}
+
+	record, err := store.GetTranscriptionRecord(id)
+	if err != nil {
+		return "", fmt.Errorf("error querying transcription %d: %w", id, err)
+	}
+	segments, err := store.GetSegments(id)
+	if err != nil {
+		return "", fmt.Errorf("error querying segments of transcription %d: %w", id, err)
+	}
+	words, err := store.GetWords(id)
+	if err != nil {
+		return "", fmt.Errorf("error querying words of transcription %d: %w", id, err)
+	}
+
+	grouped := groupWords(segments, words)
+	if format == ReviewHTML {
+		return reviewHTML(record, grouped, words, threshold), nil
+	}
+	return reviewMarkdown(record, grouped, words, threshold), nil
+}
+
+// groupWords assigns words to the segments they were built from. Without segments the
+// words, or the plain text when there are none, form a single segment.
+func groupWords(segments []database.Segment, words []database.Word) []reviewSegment {
+	if len(segments) == 0 && len(words) > 0 {
+		last := words[len(words)-1]
+		return []reviewSegment{{Segment: database.Segment{Start: words[0].Start, End: last.End}, words: words}}
+	}
+
+	grouped := make([]reviewSegment, 0, len(segments))
+	next := 0
+	for _, seg := range segments {
+		rs := reviewSegment{Segment: seg}
+		for next < len(words) && words[next].End <= seg.End {
+			rs.words = append(rs.words, words[next])
+			next++
+		}
+		grouped = append(grouped, rs)
+	}
+	return grouped
+}
+
+// lowWords counts the words below the threshold
+func lowWords(words []database.Word, threshold float64) int {
+	var n int
+	for _, w := range words {
+		if w.Confidence < threshold {
+			n++
+		}
+	}
+	return n
+}
+
+// summary describes the overall confidence of the words
+func summary(words []database.Word, threshold float64) string {
+	if len(words) == 0 {
+		return "No word confidences are stored for this transcription."
+	}
+	var total float64
+	for _, w := range words {
+		total += w.Confidence
+	}
+	return fmt.Sprintf("Average confidence %s, %d of %d words below %s.",
+		percent(total/float64(len(words))), lowWords(words, threshold), len(words), percent(threshold))
+}
+
+// highlight joins the words of a segment, wrapping runs of low-confidence words in
+// before and after. Words are escaped with escape.
+func highlight(rs reviewSegment, threshold float64, escape func(string) string, before, after string) string {
+	if len(rs.words) == 0 {
+		return escape(rs.Text)
+	}
+	var b strings.Builder
+	inside := false
+	for i, w := range rs.words {
+		low := w.Confidence < threshold
+		if i > 0 {
+			if inside && !low {
+				b.WriteString(after)
+				inside = false
+			}
+			b.WriteByte(' ')
+		}
+		if low && !inside {
+			b.WriteString(before)
+			inside = true
+		}
+		b.WriteString(escape(w.Text))
+	}
+	if inside {
+		b.WriteString(after)
+	}
+	return b.String()
+}
+
+// reviewMarkdown renders the review as markdown; highlighted words use the <mark> tag
+func reviewMarkdown(record *database.Transcription, segments []reviewSegment, words []database.Word, threshold float64) string {
+	escape := func(s string) string { return strings.ReplaceAll(s, "<", "&lt;") }
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Review: %s\n\n%s\n", record.FileName, summary(words, threshold))
+
+	var flagged []reviewSegment
+	for _, rs := range segments {
+		if lowWords(rs.words, threshold) > 0 {
+			flagged = append(flagged, rs)
+		}
+	}
+	if len(flagged) > 0 {
+		b.WriteString("\n## Segments to check\n\n| Time | Confidence | Text |\n|---|---|---|\n")
+		for _, rs := range flagged {
+			text := strings.ReplaceAll(highlight(rs, threshold, escape, "<mark>", "</mark>"), "|", `\|`)
+			fmt.Fprintf(&b, "| %s | %s | %s |\n", FormatDuration(rs.Start), percent(rs.Confidence), text)
+		}
+	}
+
+	b.WriteString("\n## Transcript\n\n")
+	if len(segments) == 0 {
+		b.WriteString(escape(record.Text) + "\n")
+	}
+	for _, rs := range segments {
+		fmt.Fprintf(&b, "**[%s]** %s\n\n", FormatDuration(rs.Start), highlight(rs, threshold, escape, "<mark>", "</mark>"))
+	}
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+// reviewHTML renders the review as a standalone HTML page, segment confidences on hover
+func reviewHTML(record *database.Transcription, segments []reviewSegment, words []database.Word, threshold float64) string {
+	var b strings.Builder
+	title := html.EscapeString("Review: " + record.FileName)
+	fmt.Fprintf(&b, `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>%s</title>
+<style>
+body { font-family: sans-serif; max-width: 50em; margin: 2em auto; line-height: 1.5; }
+mark { background: #ffd966; }
+.time { color: #888; font-size: 0.9em; margin-right: 0.5em; }
+</style>
+</head>
+<body>
+<h1>%s</h1>
+<p>%s</p>
+`, title, title, html.EscapeString(summary(words, threshold)))
+
+	if len(segments) == 0 {
+		fmt.Fprintf(&b, "<p>%s</p>\n", html.EscapeString(record.Text))
+	}
+	for _, rs := range segments {
+		fmt.Fprintf(&b, "<p title=\"confidence %s\"><span class=\"time\">%s</span>%s</p>\n",
+			percent(rs.Confidence), FormatDuration(rs.Start),
+			highlight(rs, threshold, html.EscapeString, "<mark>", "</mark>"))
+	}
+	b.WriteString("</body>\n</html>\n")
+	return b.String()
+}
+
+// percent formats a confidence from 0 to 1 as a percentage, or "-" when it is unknown
+func percent(confidence float64) string {
+	if confidence <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f%%", confidence*100)
+}
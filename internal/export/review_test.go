@@ -0,0 +1,101 @@
+package export
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"assemblyai-transcriber/internal/database"
+)
+
+type reviewStore struct {
+	record   *database.Transcription
+	segments []database.Segment
+	words    []database.Word
+}
+
+func (s *reviewStore) GetTranscriptionRecord(int64) (*database.Transcription, error) {
+	if s.record == nil {
+		return nil, errors.New("not found")
+	}
+	return s.record, nil
+}
+
+func (s *reviewStore) GetSegments(int64) ([]database.Segment, error) { return s.segments, nil }
+
+func (s *reviewStore) GetWords(int64) ([]database.Word, error) { return s.words, nil }
+
+func newReviewStore() *reviewStore {
+	return &reviewStore{
+		record: &database.Transcription{ID: 1, FileName: "talk.mp4", Text: "Hello world. Use a <b> tag."},
+		segments: []database.Segment{
+			{Index: 0, Start: 0, End: 900, Text: "Hello world.", Confidence: 0.95},
+			{Index: 1, Start: 61000, End: 63000, Text: "Use a <b> tag.", Confidence: 0.6},
+		},
+		words: []database.Word{
+			{Start: 0, End: 400, Text: "Hello", Confidence: 0.95},
+			{Start: 450, End: 900, Text: "world.", Confidence: 0.95},
+			{Start: 61000, End: 61400, Text: "Use", Confidence: 0.9},
+			{Start: 61500, End: 61800, Text: "a", Confidence: 0.4},
+			{Start: 61900, End: 62400, Text: "<b>", Confidence: 0.3},
+			{Start: 62500, End: 63000, Text: "tag.", Confidence: 0.8},
+		},
+	}
+}
+
+func TestReview_Markdown(t *testing.T) {
+	report, err := Review(newReviewStore(), 1, DefaultConfidenceThreshold, ReviewMarkdown)
+	require.NoError(t, err)
+	assert.Equal(t, `# Review: talk.mp4
+
+Average confidence 72%, 2 of 6 words below 60%.
+
+## Segments to check
+
+| Time | Confidence | Text |
+|---|---|---|
+| 1:01 | 60% | Use <mark>a &lt;b></mark> tag. |
+
+## Transcript
+
+**[0:00]** Hello world.
+
+**[1:01]** Use <mark>a &lt;b></mark> tag.
+`, report)
+}
+
+func TestReview_HTML(t *testing.T) {
+	report, err := Review(newReviewStore(), 1, 0.5, ReviewHTML)
+	require.NoError(t, err)
+	assert.Contains(t, report, "<title>Review: talk.mp4</title>")
+	assert.Contains(t, report, "Average confidence 72%, 2 of 6 words below 50%.")
+	assert.Contains(t, report,
+		`<p title="confidence 60%"><span class="time">1:01</span>Use <mark>a &lt;b&gt;</mark> tag.</p>`)
+}
+
+func TestReview_WithoutWords(t *testing.T) {
+	store := newReviewStore()
+	store.words = nil
+	store.segments[1].Confidence = 0
+
+	report, err := Review(store, 1, DefaultConfidenceThreshold, ReviewMarkdown)
+	require.NoError(t, err)
+	assert.Contains(t, report, "No word confidences are stored for this transcription.")
+	assert.NotContains(t, report, "Segments to check")
+	assert.Contains(t, report, "**[1:01]** Use a &lt;b> tag.")
+
+	store.segments = nil
+	report, err = Review(store, 1, DefaultConfidenceThreshold, ReviewMarkdown)
+	require.NoError(t, err)
+	assert.Contains(t, report, "## Transcript\n\nHello world. Use a &lt;b> tag.\n")
+}
+
+func TestReview_Errors(t *testing.T) {
+	_, err := Review(newReviewStore(), 1, DefaultConfidenceThreshold, "pdf")
+	require.EqualError(t, err, `unknown review format "pdf"`)
+
+	_, err = Review(&reviewStore{}, 7, DefaultConfidenceThreshold, ReviewMarkdown)
+	require.EqualError(t, err, "error querying transcription 7: not found")
+}
@@ -0,0 +1,97 @@
+package export
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// StateFile is the name of the file in the output directory that records what the
+// previous exports wrote there
+const StateFile = ".export-state.json"
+
+// stateVersion is the version of the state file layout
+const stateVersion = 1
+
+// state records the files written to an output directory, by file name
+type state struct {
+	Version int                  `json:"version"`
+	Files   map[string]fileState `json:"files"`
+}
+
+// fileState describes an exported file. A new translation gets a new ID, so the IDs are
+// the versions of the translations rendered into the file; the hash of the content also
+// covers changed metadata and options.
+type fileState struct {
+	Translations []int64   `json:"translations"`
+	SHA256       string    `json:"sha256"`
+	ExportedAt   time.Time `json:"exported_at"`
+}
+
+// loadState reads the state of an output directory; a directory without one has no
+// exported files yet
+func loadState(dir string) (*state, error) {
+	st := &state{Version: stateVersion, Files: map[string]fileState{}}
+	data, err := os.ReadFile(filepath.Join(dir, StateFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return st, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading export state: %w", err)
+	}
+	if err := json.Unmarshal(data, st); err != nil {
+		return nil, fmt.Errorf("error reading export state %s: %w", filepath.Join(dir, StateFile), err)
+	}
+	if st.Version > stateVersion {
+		return nil, fmt.Errorf("export state version %d is newer than this build supports (%d)", st.Version, stateVersion)
+	}
+	if st.Files == nil {
+		st.Files = map[string]fileState{}
+	}
+	return st, nil
+}
+
+// save writes the state to a temporary file first, so an interrupted export leaves the
+// previous state intact
+func (st *state) save(dir string) error {
+	st.Version = stateVersion
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding export state: %w", err)
+	}
+	f, err := os.CreateTemp(dir, StateFile+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("error saving export state: %w", err)
+	}
+	defer os.Remove(f.Name()) // fails once the file is renamed
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("error saving export state: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("error saving export state: %w", err)
+	}
+	if err := os.Rename(f.Name(), filepath.Join(dir, StateFile)); err != nil {
+		return fmt.Errorf("error saving export state: %w", err)
+	}
+	return nil
+}
+
+// dirLocks holds a mutex per output directory, by absolute path
+var dirLocks sync.Map
+
+// lockDir waits until no other export of this process writes to dir and returns the
+// function releasing the directory
+func lockDir(dir string) func() {
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	mu, _ := dirLocks.LoadOrStore(dir, &sync.Mutex{})
+	mu.(*sync.Mutex).Lock()
+	return mu.(*sync.Mutex).Unlock
+}
@@ -0,0 +1,67 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// textFormat writes plain text with an underlined title and "name: value" metadata lines
+type textFormat struct{}
+
+func (textFormat) Ext() string { return ".txt" }
+func (textFormat) Book() bool  { return false }
+
+// Render writes the documents one after another. The bilingual layout alternates the
+// source and the translation chunk by chunk, each prefixed with its language.
+func (textFormat) Render(w io.Writer, _ string, docs []*Document, opts Options) error {
+	var b strings.Builder
+	for i, doc := range docs {
+		if i > 0 {
+			b.WriteString("\n\n")
+		}
+		heading(&b, doc.Title(), '=')
+		writeTextFields(&b, doc.Fields())
+
+		if opts.Bilingual {
+			source, target := doc.Headings()
+			src, dst := doc.Aligned()
+			for i := range src {
+				fmt.Fprintf(&b, "\n%s: %s\n%s: %s\n", source, singleLine(src[i]), target, singleLine(dst[i]))
+			}
+		} else {
+			fmt.Fprintf(&b, "\n%s\n", strings.Join(paragraphs(doc.Translation.TranslatedText), "\n\n"))
+			if opts.Original {
+				b.WriteString("\n")
+				heading(&b, "Original transcript", '-')
+				fmt.Fprintf(&b, "%s\n", strings.Join(paragraphs(doc.Transcription.Text), "\n\n"))
+			}
+		}
+
+		if doc.Media != nil {
+			b.WriteString("\n")
+			heading(&b, "Source media", '-')
+			writeTextFields(&b, mediaFields(doc.Media))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// heading writes a title underlined with the given character and a blank line
+func heading(b *strings.Builder, title string, underline rune) {
+	fmt.Fprintf(b, "%s\n%s\n\n", title, strings.Repeat(string(underline), utf8.RuneCountInString(title)))
+}
+
+// writeTextFields writes metadata as name: value lines
+func writeTextFields(b *strings.Builder, fields []Field) {
+	for _, f := range fields {
+		fmt.Fprintf(b, "%s: %s\n", f.Label, f.Value)
+	}
+}
+
+// singleLine joins the lines of a chunk with spaces
+func singleLine(chunk string) string {
+	return strings.Join(strings.Fields(chunk), " ")
+}
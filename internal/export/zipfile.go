@@ -0,0 +1,57 @@
+package export
+
+import (
+	"archive/zip"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"time"
+)
+
+// zipEntry is a file of a DOCX or EPUB container
+type zipEntry struct {
+	name   string
+	data   string
+	stored bool // written without compression, as EPUB requires for the mimetype file
+}
+
+// zipTime is the modification time of all entries, the earliest zip can store, so the
+// same documents always give the same bytes
+var zipTime = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// zipDOSDate is zipTime in the MS-DOS format of the local file header: day 1 of month 1
+// of 1980, at midnight
+const zipDOSDate = 1<<5 | 1
+
+// writeZip writes entries in order into a zip container. Stored entries are written raw
+// with their checksum and sizes up front, without the extended timestamp and data
+// descriptor of the other entries: the EPUB container requires the mimetype content to
+// follow its local file header directly.
+func writeZip(w io.Writer, entries []zipEntry) error {
+	zw := zip.NewWriter(w)
+	for _, e := range entries {
+		var (
+			f   io.Writer
+			err error
+		)
+		if e.stored {
+			f, err = zw.CreateRaw(&zip.FileHeader{
+				Name:               e.name,
+				Method:             zip.Store,
+				ModifiedDate:       zipDOSDate,
+				CRC32:              crc32.ChecksumIEEE([]byte(e.data)),
+				CompressedSize64:   uint64(len(e.data)),
+				UncompressedSize64: uint64(len(e.data)),
+			})
+		} else {
+			f, err = zw.CreateHeader(&zip.FileHeader{Name: e.name, Method: zip.Deflate, Modified: zipTime})
+		}
+		if err != nil {
+			return fmt.Errorf("error adding %s: %w", e.name, err)
+		}
+		if _, err := io.WriteString(f, e.data); err != nil {
+			return fmt.Errorf("error writing %s: %w", e.name, err)
+		}
+	}
+	return zw.Close()
+}
@@ -4,4 +4,5 @@
 package interfaces
 
 //go:generate moq -pkg=mocks -out=../mocks/database_mock.go . Database
+//go:generate moq -pkg=mocks -out=../mocks/repository_mock.go . Repository
 //go:generate moq -pkg=mocks -out=../mocks/transcriber_mock.go . Transcriber
@@ -2,16 +2,56 @@ package interfaces
 
 import (
 	"context"
+
+	"assemblyai-transcriber/internal/database"
+	"assemblyai-transcriber/internal/transcribe"
 )
 
 // Database abstracts database operations.
 type Database interface {
 	Setup() error
-	SaveTranscription(fileName, text string) (int64, error)
+	InsertTranscription(t *database.Transcription) (int64, error)
+	SaveSegments(transcriptionID int64, segments []database.Segment) error
+	SaveWords(transcriptionID int64, words []database.Word) error
+	SaveMedia(m *database.Media) error
+	SaveBundle(b *database.Bundle) (int64, error)
+	SavePending(p *database.PendingTranscription) (int64, error)
+	ListPending(status string) ([]database.PendingTranscription, error)
+	FailPending(id int64, message string) error
+	DeletePending(id int64) error
+	CompletePending(pendingID int64, b *database.Bundle) (int64, error)
+	GetAllTerms() ([]map[string]string, error)
 	Close() error
 }
 
+// Repository is the complete set of stored data operations used by the commands and
+// services: the operations of Database and translation.Database together with the
+// listings. database.DB implements it for SQLite and PostgreSQL.
+type Repository interface {
+	Database
+	GetTranscription(id int64) (string, error)
+	GetTranscriptionRecord(id int64) (*database.Transcription, error)
+	ListTranscriptions() ([]database.TranscriptionListItem, error)
+	GetSegments(transcriptionID int64) ([]database.Segment, error)
+	GetWords(transcriptionID int64) ([]database.Word, error)
+	GetMedia(transcriptionID int64) (*database.Media, error)
+	GetTranslation(transcriptionID int64) (string, error)
+	SaveTranslation(transcriptionID int64, text string) error
+	InsertTranslation(t *database.Translation) (int64, error)
+	ListTranslations() ([]database.Translation, error)
+	ListStaleTranslations() ([]int64, error)
+	GetTags(transcriptionID int64) ([]string, error)
+	GetCollections(transcriptionID int64) ([]string, error)
+	SaveTerm(term, description string) error
+	DeleteTerm(term string) error
+}
+
+var _ Repository = (*database.DB)(nil)
+
 // Transcriber abstracts transcription operations.
 type Transcriber interface {
-	TranscribeVideo(ctx context.Context, videoPath string) (string, error)
+	TranscribeVideo(ctx context.Context, videoPath string) (*transcribe.Result, error)
+	Submit(ctx context.Context, videoPath string) (*transcribe.Submission, error)
+	Collect(ctx context.Context, sub *transcribe.Submission) (*transcribe.Result, error)
+	AddWordBoost(words ...string)
 }
@@ -0,0 +1,145 @@
+package language
+
+import (
+	"strings"
+	"unicode"
+)
+
+// minDetectWords is the number of stopword hits below which Detect gives up on
+// Latin-script text
+const minDetectWords = 3
+
+// names maps ISO 639-1 codes to English language names used in prompts
+var names = map[string]string{
+	"ar": "Arabic", "de": "German", "el": "Greek", "en": "English", "es": "Spanish",
+	"fi": "Finnish", "fr": "French", "hi": "Hindi", "it": "Italian", "ja": "Japanese",
+	"ko": "Korean", "nl": "Dutch", "pl": "Polish", "pt": "Portuguese", "ru": "Russian",
+	"tr": "Turkish", "uk": "Ukrainian", "vi": "Vietnamese", "zh": "Chinese",
+}
+
+// stopwords are frequent short words that tell Latin-script languages apart
+var stopwords = map[string][]string{
+	"en": {"the", "and", "is", "are", "of", "to", "in", "that", "it", "you", "this", "with", "was", "for", "have"},
+	"de": {"der", "die", "das", "und", "ist", "nicht", "ich", "sie", "wir", "mit", "auf", "ein", "eine", "zu", "auch"},
+	"fr": {"le", "la", "les", "et", "est", "des", "une", "que", "pas", "nous", "vous", "dans", "pour", "avec", "sur"},
+	"es": {"el", "los", "las", "y", "es", "que", "una", "por", "para", "con", "del", "pero", "como", "muy", "está"},
+	"it": {"il", "gli", "di", "che", "è", "non", "una", "per", "con", "sono", "della", "anche", "come", "questo", "ma"},
+	"pt": {"o", "os", "as", "que", "não", "uma", "com", "para", "por", "mais", "são", "está", "você", "isso", "também"},
+	"nl": {"de", "het", "een", "en", "is", "niet", "dat", "van", "ik", "je", "wij", "met", "voor", "op", "ook"},
+	"pl": {"i", "nie", "się", "jest", "że", "na", "to", "w", "z", "do", "jak", "ale", "tak", "co", "czy"},
+	"tr": {"ve", "bir", "bu", "da", "de", "için", "ile", "çok", "ne", "ama", "gibi", "daha", "olarak", "var", "değil"},
+}
+
+// Normalize reduces a language code such as "en_us" or "pt-BR" to its lowercase
+// ISO 639-1 base, e.g. "en" or "pt"
+func Normalize(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if i := strings.IndexAny(code, "_-"); i >= 0 {
+		code = code[:i]
+	}
+	return code
+}
+
+// Same reports whether two language codes denote the same language
+func Same(a, b string) bool {
+	return a != "" && Normalize(a) == Normalize(b)
+}
+
+// Name returns the English name of a language code, or the code itself when it is unknown
+func Name(code string) string {
+	if name, ok := names[Normalize(code)]; ok {
+		return name
+	}
+	return code
+}
+
+// Detect guesses the language of a text from its script and, for Latin-script text,
+// from frequent words. It returns an ISO 639-1 code, or "" when the text is too short
+// or ambiguous.
+func Detect(text string) string {
+	if lang := detectScript(text); lang != "" {
+		return lang
+	}
+
+	counts := map[string]int{}
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	}) {
+		for lang, words := range stopwords {
+			for _, w := range words {
+				if w == word {
+					counts[lang]++
+					break
+				}
+			}
+		}
+	}
+
+	best, bestCount, ties := "", 0, false
+	for lang, n := range counts {
+		switch {
+		case n > bestCount:
+			best, bestCount, ties = lang, n, false
+		case n == bestCount:
+			ties = true
+		}
+	}
+	if bestCount < minDetectWords || ties {
+		return ""
+	}
+	return best
+}
+
+// detectScript identifies languages written in a script of their own; most letters
+// of the text must be in that script
+func detectScript(text string) string {
+	counts := map[string]int{}
+	var letters int
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		switch {
+		case unicode.In(r, unicode.Cyrillic):
+			counts["cyrillic"]++
+			if strings.ContainsRune("іїєґ", unicode.ToLower(r)) {
+				counts["uk"]++
+			}
+		case unicode.In(r, unicode.Greek):
+			counts["el"]++
+		case unicode.In(r, unicode.Arabic):
+			counts["ar"]++
+		case unicode.In(r, unicode.Devanagari):
+			counts["hi"]++
+		case unicode.In(r, unicode.Hangul):
+			counts["ko"]++
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+			counts["ja"]++
+		case unicode.In(r, unicode.Han):
+			counts["han"]++
+		}
+	}
+	if letters == 0 {
+		return ""
+	}
+
+	major := func(n int) bool { return n*2 > letters }
+	switch {
+	case major(counts["cyrillic"]) && counts["uk"] > 0:
+		return "uk"
+	case major(counts["cyrillic"]):
+		return "ru"
+	case major(counts["ja"]+counts["han"]) && counts["ja"] > 0:
+		// Japanese mixes kana with Chinese characters
+		return "ja"
+	case major(counts["han"]):
+		return "zh"
+	}
+	for _, lang := range []string{"el", "ar", "hi", "ko"} {
+		if major(counts[lang]) {
+			return lang
+		}
+	}
+	return ""
+}
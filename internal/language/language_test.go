@@ -0,0 +1,50 @@
+package language
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, "en", Normalize("en_us"))
+	assert.Equal(t, "pt", Normalize("pt-BR"))
+	assert.Equal(t, "de", Normalize(" DE "))
+	assert.Equal(t, "", Normalize(""))
+}
+
+func TestSame(t *testing.T) {
+	assert.True(t, Same("en_us", "en"))
+	assert.False(t, Same("en", "ru"))
+	assert.False(t, Same("", ""))
+}
+
+func TestName(t *testing.T) {
+	assert.Equal(t, "English", Name("en_au"))
+	assert.Equal(t, "Russian", Name("ru"))
+	assert.Equal(t, "xx", Name("xx"))
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"english", "This is the plan and we have to finish it in time for the release.", "en"},
+		{"german", "Das ist nicht der Plan, und wir müssen auch die Tests mit einer Liste schreiben.", "de"},
+		{"french", "Nous avons une réunion avec les clients et le plan est prêt pour la semaine.", "fr"},
+		{"spanish", "El equipo está listo para la reunión y los planes son muy buenos para el cliente.", "es"},
+		{"russian", "Сегодня мы поговорим о новой версии приложения.", "ru"},
+		{"ukrainian", "Сьогодні ми поговоримо про її нову версію.", "uk"},
+		{"japanese", "今日は新しいバージョンについて話します。", "ja"},
+		{"chinese", "今天我们讨论新版本。", "zh"},
+		{"too short", "Kubernetes gRPC", ""},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Detect(tt.text))
+		})
+	}
+}
@@ -4,6 +4,7 @@
 package mocks
 
 import (
+	"assemblyai-transcriber/internal/database"
 	"assemblyai-transcriber/internal/interfaces"
 	"sync"
 )
@@ -21,8 +22,38 @@ var _ interfaces.Database = &DatabaseMock{}
 //			CloseFunc: func() error {
 //				panic("mock out the Close method")
 //			},
-//			SaveTranscriptionFunc: func(fileName string, text string) (int64, error) {
-//				panic("mock out the SaveTranscription method")
+//			CompletePendingFunc: func(pendingID int64, b *database.Bundle) (int64, error) {
+//				panic("mock out the CompletePending method")
+//			},
+//			DeletePendingFunc: func(id int64) error {
+//				panic("mock out the DeletePending method")
+//			},
+//			FailPendingFunc: func(id int64, message string) error {
+//				panic("mock out the FailPending method")
+//			},
+//			GetAllTermsFunc: func() ([]map[string]string, error) {
+//				panic("mock out the GetAllTerms method")
+//			},
+//			InsertTranscriptionFunc: func(t *database.Transcription) (int64, error) {
+//				panic("mock out the InsertTranscription method")
+//			},
+//			ListPendingFunc: func(status string) ([]database.PendingTranscription, error) {
+//				panic("mock out the ListPending method")
+//			},
+//			SaveBundleFunc: func(b *database.Bundle) (int64, error) {
+//				panic("mock out the SaveBundle method")
+//			},
+//			SaveMediaFunc: func(m *database.Media) error {
+//				panic("mock out the SaveMedia method")
+//			},
+//			SavePendingFunc: func(p *database.PendingTranscription) (int64, error) {
+//				panic("mock out the SavePending method")
+//			},
+//			SaveSegmentsFunc: func(transcriptionID int64, segments []database.Segment) error {
+//				panic("mock out the SaveSegments method")
+//			},
+//			SaveWordsFunc: func(transcriptionID int64, words []database.Word) error {
+//				panic("mock out the SaveWords method")
 //			},
 //			SetupFunc: func() error {
 //				panic("mock out the Setup method")
@@ -37,8 +68,38 @@ type DatabaseMock struct {
 	// CloseFunc mocks the Close method.
 	CloseFunc func() error
 
-	// SaveTranscriptionFunc mocks the SaveTranscription method.
-	SaveTranscriptionFunc func(fileName string, text string) (int64, error)
+	// CompletePendingFunc mocks the CompletePending method.
+	CompletePendingFunc func(pendingID int64, b *database.Bundle) (int64, error)
+
+	// DeletePendingFunc mocks the DeletePending method.
+	DeletePendingFunc func(id int64) error
+
+	// FailPendingFunc mocks the FailPending method.
+	FailPendingFunc func(id int64, message string) error
+
+	// GetAllTermsFunc mocks the GetAllTerms method.
+	GetAllTermsFunc func() ([]map[string]string, error)
+
+	// InsertTranscriptionFunc mocks the InsertTranscription method.
+	InsertTranscriptionFunc func(t *database.Transcription) (int64, error)
+
+	// ListPendingFunc mocks the ListPending method.
+	ListPendingFunc func(status string) ([]database.PendingTranscription, error)
+
+	// SaveBundleFunc mocks the SaveBundle method.
+	SaveBundleFunc func(b *database.Bundle) (int64, error)
+
+	// SaveMediaFunc mocks the SaveMedia method.
+	SaveMediaFunc func(m *database.Media) error
+
+	// SavePendingFunc mocks the SavePending method.
+	SavePendingFunc func(p *database.PendingTranscription) (int64, error)
+
+	// SaveSegmentsFunc mocks the SaveSegments method.
+	SaveSegmentsFunc func(transcriptionID int64, segments []database.Segment) error
+
+	// SaveWordsFunc mocks the SaveWords method.
+	SaveWordsFunc func(transcriptionID int64, words []database.Word) error
 
 	// SetupFunc mocks the Setup method.
 	SetupFunc func() error
@@ -48,20 +109,84 @@ type DatabaseMock struct {
 		// Close holds details about calls to the Close method.
 		Close []struct {
 		}
-		// SaveTranscription holds details about calls to the SaveTranscription method.
-		SaveTranscription []struct {
-			// FileName is the fileName argument value.
-			FileName string
-			// Text is the text argument value.
-			Text string
+		// CompletePending holds details about calls to the CompletePending method.
+		CompletePending []struct {
+			// PendingID is the pendingID argument value.
+			PendingID int64
+			// B is the b argument value.
+			B *database.Bundle
+		}
+		// DeletePending holds details about calls to the DeletePending method.
+		DeletePending []struct {
+			// ID is the id argument value.
+			ID int64
+		}
+		// FailPending holds details about calls to the FailPending method.
+		FailPending []struct {
+			// ID is the id argument value.
+			ID int64
+			// Message is the message argument value.
+			Message string
+		}
+		// GetAllTerms holds details about calls to the GetAllTerms method.
+		GetAllTerms []struct {
+		}
+		// InsertTranscription holds details about calls to the InsertTranscription method.
+		InsertTranscription []struct {
+			// T is the t argument value.
+			T *database.Transcription
+		}
+		// ListPending holds details about calls to the ListPending method.
+		ListPending []struct {
+			// Status is the status argument value.
+			Status string
+		}
+		// SaveBundle holds details about calls to the SaveBundle method.
+		SaveBundle []struct {
+			// B is the b argument value.
+			B *database.Bundle
+		}
+		// SaveMedia holds details about calls to the SaveMedia method.
+		SaveMedia []struct {
+			// M is the m argument value.
+			M *database.Media
+		}
+		// SavePending holds details about calls to the SavePending method.
+		SavePending []struct {
+			// P is the p argument value.
+			P *database.PendingTranscription
+		}
+		// SaveSegments holds details about calls to the SaveSegments method.
+		SaveSegments []struct {
+			// TranscriptionID is the transcriptionID argument value.
+			TranscriptionID int64
+			// Segments is the segments argument value.
+			Segments []database.Segment
+		}
+		// SaveWords holds details about calls to the SaveWords method.
+		SaveWords []struct {
+			// TranscriptionID is the transcriptionID argument value.
+			TranscriptionID int64
+			// Words is the words argument value.
+			Words []database.Word
 		}
 		// Setup holds details about calls to the Setup method.
 		Setup []struct {
 		}
 	}
-	lockClose             sync.RWMutex
-	lockSaveTranscription sync.RWMutex
-	lockSetup             sync.RWMutex
+	lockClose               sync.RWMutex
+	lockCompletePending     sync.RWMutex
+	lockDeletePending       sync.RWMutex
+	lockFailPending         sync.RWMutex
+	lockGetAllTerms         sync.RWMutex
+	lockInsertTranscription sync.RWMutex
+	lockListPending         sync.RWMutex
+	lockSaveBundle          sync.RWMutex
+	lockSaveMedia           sync.RWMutex
+	lockSavePending         sync.RWMutex
+	lockSaveSegments        sync.RWMutex
+	lockSaveWords           sync.RWMutex
+	lockSetup               sync.RWMutex
 }
 
 // Close calls CloseFunc.
@@ -91,39 +216,366 @@ func (mock *DatabaseMock) CloseCalls() []struct {
 	return calls
 }
 
-// SaveTranscription calls SaveTranscriptionFunc.
-func (mock *DatabaseMock) SaveTranscription(fileName string, text string) (int64, error) {
-	if mock.SaveTranscriptionFunc == nil {
-		panic("DatabaseMock.SaveTranscriptionFunc: method is nil but Database.SaveTranscription was just called")
+// CompletePending calls CompletePendingFunc.
+func (mock *DatabaseMock) CompletePending(pendingID int64, b *database.Bundle) (int64, error) {
+	if mock.CompletePendingFunc == nil {
+		panic("DatabaseMock.CompletePendingFunc: method is nil but Database.CompletePending was just called")
+	}
+	callInfo := struct {
+		PendingID int64
+		B         *database.Bundle
+	}{
+		PendingID: pendingID,
+		B:         b,
+	}
+	mock.lockCompletePending.Lock()
+	mock.calls.CompletePending = append(mock.calls.CompletePending, callInfo)
+	mock.lockCompletePending.Unlock()
+	return mock.CompletePendingFunc(pendingID, b)
+}
+
+// CompletePendingCalls gets all the calls that were made to CompletePending.
+// Check the length with:
+//
+//	len(mockedDatabase.CompletePendingCalls())
+func (mock *DatabaseMock) CompletePendingCalls() []struct {
+	PendingID int64
+	B         *database.Bundle
+} {
+	var calls []struct {
+		PendingID int64
+		B         *database.Bundle
+	}
+	mock.lockCompletePending.RLock()
+	calls = mock.calls.CompletePending
+	mock.lockCompletePending.RUnlock()
+	return calls
+}
+
+// DeletePending calls DeletePendingFunc.
+func (mock *DatabaseMock) DeletePending(id int64) error {
+	if mock.DeletePendingFunc == nil {
+		panic("DatabaseMock.DeletePendingFunc: method is nil but Database.DeletePending was just called")
+	}
+	callInfo := struct {
+		ID int64
+	}{
+		ID: id,
+	}
+	mock.lockDeletePending.Lock()
+	mock.calls.DeletePending = append(mock.calls.DeletePending, callInfo)
+	mock.lockDeletePending.Unlock()
+	return mock.DeletePendingFunc(id)
+}
+
+// DeletePendingCalls gets all the calls that were made to DeletePending.
+// Check the length with:
+//
+//	len(mockedDatabase.DeletePendingCalls())
+func (mock *DatabaseMock) DeletePendingCalls() []struct {
+	ID int64
+} {
+	var calls []struct {
+		ID int64
+	}
+	mock.lockDeletePending.RLock()
+	calls = mock.calls.DeletePending
+	mock.lockDeletePending.RUnlock()
+	return calls
+}
+
+// FailPending calls FailPendingFunc.
+func (mock *DatabaseMock) FailPending(id int64, message string) error {
+	if mock.FailPendingFunc == nil {
+		panic("DatabaseMock.FailPendingFunc: method is nil but Database.FailPending was just called")
+	}
+	callInfo := struct {
+		ID      int64
+		Message string
+	}{
+		ID:      id,
+		Message: message,
+	}
+	mock.lockFailPending.Lock()
+	mock.calls.FailPending = append(mock.calls.FailPending, callInfo)
+	mock.lockFailPending.Unlock()
+	return mock.FailPendingFunc(id, message)
+}
+
+// FailPendingCalls gets all the calls that were made to FailPending.
+// Check the length with:
+//
+//	len(mockedDatabase.FailPendingCalls())
+func (mock *DatabaseMock) FailPendingCalls() []struct {
+	ID      int64
+	Message string
+} {
+	var calls []struct {
+		ID      int64
+		Message string
+	}
+	mock.lockFailPending.RLock()
+	calls = mock.calls.FailPending
+	mock.lockFailPending.RUnlock()
+	return calls
+}
+
+// GetAllTerms calls GetAllTermsFunc.
+func (mock *DatabaseMock) GetAllTerms() ([]map[string]string, error) {
+	if mock.GetAllTermsFunc == nil {
+		panic("DatabaseMock.GetAllTermsFunc: method is nil but Database.GetAllTerms was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockGetAllTerms.Lock()
+	mock.calls.GetAllTerms = append(mock.calls.GetAllTerms, callInfo)
+	mock.lockGetAllTerms.Unlock()
+	return mock.GetAllTermsFunc()
+}
+
+// GetAllTermsCalls gets all the calls that were made to GetAllTerms.
+// Check the length with:
+//
+//	len(mockedDatabase.GetAllTermsCalls())
+func (mock *DatabaseMock) GetAllTermsCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockGetAllTerms.RLock()
+	calls = mock.calls.GetAllTerms
+	mock.lockGetAllTerms.RUnlock()
+	return calls
+}
+
+// InsertTranscription calls InsertTranscriptionFunc.
+func (mock *DatabaseMock) InsertTranscription(t *database.Transcription) (int64, error) {
+	if mock.InsertTranscriptionFunc == nil {
+		panic("DatabaseMock.InsertTranscriptionFunc: method is nil but Database.InsertTranscription was just called")
+	}
+	callInfo := struct {
+		T *database.Transcription
+	}{
+		T: t,
+	}
+	mock.lockInsertTranscription.Lock()
+	mock.calls.InsertTranscription = append(mock.calls.InsertTranscription, callInfo)
+	mock.lockInsertTranscription.Unlock()
+	return mock.InsertTranscriptionFunc(t)
+}
+
+// InsertTranscriptionCalls gets all the calls that were made to InsertTranscription.
+// Check the length with:
+//
+//	len(mockedDatabase.InsertTranscriptionCalls())
+func (mock *DatabaseMock) InsertTranscriptionCalls() []struct {
+	T *database.Transcription
+} {
+	var calls []struct {
+		T *database.Transcription
+	}
+	mock.lockInsertTranscription.RLock()
+	calls = mock.calls.InsertTranscription
+	mock.lockInsertTranscription.RUnlock()
+	return calls
+}
+
+// ListPending calls ListPendingFunc.
+func (mock *DatabaseMock) ListPending(status string) ([]database.PendingTranscription, error) {
+	if mock.ListPendingFunc == nil {
+		panic("DatabaseMock.ListPendingFunc: method is nil but Database.ListPending was just called")
+	}
+	callInfo := struct {
+		Status string
+	}{
+		Status: status,
+	}
+	mock.lockListPending.Lock()
+	mock.calls.ListPending = append(mock.calls.ListPending, callInfo)
+	mock.lockListPending.Unlock()
+	return mock.ListPendingFunc(status)
+}
+
+// ListPendingCalls gets all the calls that were made to ListPending.
+// Check the length with:
+//
+//	len(mockedDatabase.ListPendingCalls())
+func (mock *DatabaseMock) ListPendingCalls() []struct {
+	Status string
+} {
+	var calls []struct {
+		Status string
+	}
+	mock.lockListPending.RLock()
+	calls = mock.calls.ListPending
+	mock.lockListPending.RUnlock()
+	return calls
+}
+
+// SaveBundle calls SaveBundleFunc.
+func (mock *DatabaseMock) SaveBundle(b *database.Bundle) (int64, error) {
+	if mock.SaveBundleFunc == nil {
+		panic("DatabaseMock.SaveBundleFunc: method is nil but Database.SaveBundle was just called")
+	}
+	callInfo := struct {
+		B *database.Bundle
+	}{
+		B: b,
+	}
+	mock.lockSaveBundle.Lock()
+	mock.calls.SaveBundle = append(mock.calls.SaveBundle, callInfo)
+	mock.lockSaveBundle.Unlock()
+	return mock.SaveBundleFunc(b)
+}
+
+// SaveBundleCalls gets all the calls that were made to SaveBundle.
+// Check the length with:
+//
+//	len(mockedDatabase.SaveBundleCalls())
+func (mock *DatabaseMock) SaveBundleCalls() []struct {
+	B *database.Bundle
+} {
+	var calls []struct {
+		B *database.Bundle
+	}
+	mock.lockSaveBundle.RLock()
+	calls = mock.calls.SaveBundle
+	mock.lockSaveBundle.RUnlock()
+	return calls
+}
+
+// SaveMedia calls SaveMediaFunc.
+func (mock *DatabaseMock) SaveMedia(m *database.Media) error {
+	if mock.SaveMediaFunc == nil {
+		panic("DatabaseMock.SaveMediaFunc: method is nil but Database.SaveMedia was just called")
+	}
+	callInfo := struct {
+		M *database.Media
+	}{
+		M: m,
+	}
+	mock.lockSaveMedia.Lock()
+	mock.calls.SaveMedia = append(mock.calls.SaveMedia, callInfo)
+	mock.lockSaveMedia.Unlock()
+	return mock.SaveMediaFunc(m)
+}
+
+// SaveMediaCalls gets all the calls that were made to SaveMedia.
+// Check the length with:
+//
+//	len(mockedDatabase.SaveMediaCalls())
+func (mock *DatabaseMock) SaveMediaCalls() []struct {
+	M *database.Media
+} {
+	var calls []struct {
+		M *database.Media
+	}
+	mock.lockSaveMedia.RLock()
+	calls = mock.calls.SaveMedia
+	mock.lockSaveMedia.RUnlock()
+	return calls
+}
+
+// SavePending calls SavePendingFunc.
+func (mock *DatabaseMock) SavePending(p *database.PendingTranscription) (int64, error) {
+	if mock.SavePendingFunc == nil {
+		panic("DatabaseMock.SavePendingFunc: method is nil but Database.SavePending was just called")
+	}
+	callInfo := struct {
+		P *database.PendingTranscription
+	}{
+		P: p,
+	}
+	mock.lockSavePending.Lock()
+	mock.calls.SavePending = append(mock.calls.SavePending, callInfo)
+	mock.lockSavePending.Unlock()
+	return mock.SavePendingFunc(p)
+}
+
+// SavePendingCalls gets all the calls that were made to SavePending.
+// Check the length with:
+//
+//	len(mockedDatabase.SavePendingCalls())
+func (mock *DatabaseMock) SavePendingCalls() []struct {
+	P *database.PendingTranscription
+} {
+	var calls []struct {
+		P *database.PendingTranscription
+	}
+	mock.lockSavePending.RLock()
+	calls = mock.calls.SavePending
+	mock.lockSavePending.RUnlock()
+	return calls
+}
+
+// SaveSegments calls SaveSegmentsFunc.
+func (mock *DatabaseMock) SaveSegments(transcriptionID int64, segments []database.Segment) error {
+	if mock.SaveSegmentsFunc == nil {
+		panic("DatabaseMock.SaveSegmentsFunc: method is nil but Database.SaveSegments was just called")
+	}
+	callInfo := struct {
+		TranscriptionID int64
+		Segments        []database.Segment
+	}{
+		TranscriptionID: transcriptionID,
+		Segments:        segments,
+	}
+	mock.lockSaveSegments.Lock()
+	mock.calls.SaveSegments = append(mock.calls.SaveSegments, callInfo)
+	mock.lockSaveSegments.Unlock()
+	return mock.SaveSegmentsFunc(transcriptionID, segments)
+}
+
+// SaveSegmentsCalls gets all the calls that were made to SaveSegments.
+// Check the length with:
+//
+//	len(mockedDatabase.SaveSegmentsCalls())
+func (mock *DatabaseMock) SaveSegmentsCalls() []struct {
+	TranscriptionID int64
+	Segments        []database.Segment
+} {
+	var calls []struct {
+		TranscriptionID int64
+		Segments        []database.Segment
+	}
+	mock.lockSaveSegments.RLock()
+	calls = mock.calls.SaveSegments
+	mock.lockSaveSegments.RUnlock()
+	return calls
+}
+
+// SaveWords calls SaveWordsFunc.
+func (mock *DatabaseMock) SaveWords(transcriptionID int64, words []database.Word) error {
+	if mock.SaveWordsFunc == nil {
+		panic("DatabaseMock.SaveWordsFunc: method is nil but Database.SaveWords was just called")
 	}
 	callInfo := struct {
-		FileName string
-		Text     string
+		TranscriptionID int64
+		Words           []database.Word
 	}{
-		FileName: fileName,
-		Text:     text,
+		TranscriptionID: transcriptionID,
+		Words:           words,
 	}
-	mock.lockSaveTranscription.Lock()
-	mock.calls.SaveTranscription = append(mock.calls.SaveTranscription, callInfo)
-	mock.lockSaveTranscription.Unlock()
-	return mock.SaveTranscriptionFunc(fileName, text)
+	mock.lockSaveWords.Lock()
+	mock.calls.SaveWords = append(mock.calls.SaveWords, callInfo)
+	mock.lockSaveWords.Unlock()
+	return mock.SaveWordsFunc(transcriptionID, words)
 }
 
-// SaveTranscriptionCalls gets all the calls that were made to SaveTranscription.
+// SaveWordsCalls gets all the calls that were made to SaveWords.
 // Check the length with:
 //
-//	len(mockedDatabase.SaveTranscriptionCalls())
-func (mock *DatabaseMock) SaveTranscriptionCalls() []struct {
-	FileName string
-	Text     string
+//	len(mockedDatabase.SaveWordsCalls())
+func (mock *DatabaseMock) SaveWordsCalls() []struct {
+	TranscriptionID int64
+	Words           []database.Word
 } {
 	var calls []struct {
-		FileName string
-		Text     string
+		TranscriptionID int64
+		Words           []database.Word
 	}
-	mock.lockSaveTranscription.RLock()
-	calls = mock.calls.SaveTranscription
-	mock.lockSaveTranscription.RUnlock()
+	mock.lockSaveWords.RLock()
+	calls = mock.calls.SaveWords
+	mock.lockSaveWords.RUnlock()
 	return calls
 }
 
@@ -0,0 +1,1258 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package mocks
+
+import (
+	"assemblyai-transcriber/internal/database"
+	"assemblyai-transcriber/internal/interfaces"
+	"sync"
+)
+
+// Ensure, that RepositoryMock does implement interfaces.Repository.
+// If this is not the case, regenerate this file with moq.
+var _ interfaces.Repository = &RepositoryMock{}
+
+// RepositoryMock is a mock implementation of interfaces.Repository.
+//
+//	func TestSomethingThatUsesRepository(t *testing.T) {
+//
+//		// make and configure a mocked interfaces.Repository
+//		mockedRepository := &RepositoryMock{
+//			CloseFunc: func() error {
+//				panic("mock out the Close method")
+//			},
+//			CompletePendingFunc: func(pendingID int64, b *database.Bundle) (int64, error) {
+//				panic("mock out the CompletePending method")
+//			},
+//			DeletePendingFunc: func(id int64) error {
+//				panic("mock out the DeletePending method")
+//			},
+//			DeleteTermFunc: func(term string) error {
+//				panic("mock out the DeleteTerm method")
+//			},
+//			FailPendingFunc: func(id int64, message string) error {
+//				panic("mock out the FailPending method")
+//			},
+//			GetAllTermsFunc: func() ([]map[string]string, error) {
+//				panic("mock out the GetAllTerms method")
+//			},
+//			GetCollectionsFunc: func(transcriptionID int64) ([]string, error) {
+//				panic("mock out the GetCollections method")
+//			},
+//			GetMediaFunc: func(transcriptionID int64) (*database.Media, error) {
+//				panic("mock out the GetMedia method")
+//			},
+//			GetSegmentsFunc: func(transcriptionID int64) ([]database.Segment, error) {
+//				panic("mock out the GetSegments method")
+//			},
+//			GetTagsFunc: func(transcriptionID int64) ([]string, error) {
+//				panic("mock out the GetTags method")
+//			},
+//			GetTranscriptionFunc: func(id int64) (string, error) {
+//				panic("mock out the GetTranscription method")
+//			},
+//			GetTranscriptionRecordFunc: func(id int64) (*database.Transcription, error) {
+//				panic("mock out the GetTranscriptionRecord method")
+//			},
+//			GetTranslationFunc: func(transcriptionID int64) (string, error) {
+//				panic("mock out the GetTranslation method")
+//			},
+//			GetWordsFunc: func(transcriptionID int64) ([]database.Word, error) {
+//				panic("mock out the GetWords method")
+//			},
+//			InsertTranscriptionFunc: func(t *database.Transcription) (int64, error) {
+//				panic("mock out the InsertTranscription method")
+//			},
+//			InsertTranslationFunc: func(t *database.Translation) (int64, error) {
+//				panic("mock out the InsertTranslation method")
+//			},
+//			ListPendingFunc: func(status string) ([]database.PendingTranscription, error) {
+//				panic("mock out the ListPending method")
+//			},
+//			ListStaleTranslationsFunc: func() ([]int64, error) {
+//				panic("mock out the ListStaleTranslations method")
+//			},
+//			ListTranscriptionsFunc: func() ([]database.TranscriptionListItem, error) {
+//				panic("mock out the ListTranscriptions method")
+//			},
+//			ListTranslationsFunc: func() ([]database.Translation, error) {
+//				panic("mock out the ListTranslations method")
+//			},
+//			SaveBundleFunc: func(b *database.Bundle) (int64, error) {
+//				panic("mock out the SaveBundle method")
+//			},
+//			SaveMediaFunc: func(m *database.Media) error {
+//				panic("mock out the SaveMedia method")
+//			},
+//			SavePendingFunc: func(p *database.PendingTranscription) (int64, error) {
+//				panic("mock out the SavePending method")
+//			},
+//			SaveSegmentsFunc: func(transcriptionID int64, segments []database.Segment) error {
+//				panic("mock out the SaveSegments method")
+//			},
+//			SaveTermFunc: func(term string, description string) error {
+//				panic("mock out the SaveTerm method")
+//			},
+//			SaveTranslationFunc: func(transcriptionID int64, text string) error {
+//				panic("mock out the SaveTranslation method")
+//			},
+//			SaveWordsFunc: func(transcriptionID int64, words []database.Word) error {
+//				panic("mock out the SaveWords method")
+//			},
+//			SetupFunc: func() error {
+//				panic("mock out the Setup method")
+//			},
+//		}
+//
+//		// use mockedRepository in code that requires interfaces.Repository
+//		// and then make assertions.
+//
+//	}
+type RepositoryMock struct {
+	// CloseFunc mocks the Close method.
+	CloseFunc func() error
+
+	// CompletePendingFunc mocks the CompletePending method.
+	CompletePendingFunc func(pendingID int64, b *database.Bundle) (int64, error)
+
+	// DeletePendingFunc mocks the DeletePending method.
+	DeletePendingFunc func(id int64) error
+
+	// DeleteTermFunc mocks the DeleteTerm method.
+	DeleteTermFunc func(term string) error
+
+	// FailPendingFunc mocks the FailPending method.
+	FailPendingFunc func(id int64, message string) error
+
+	// GetAllTermsFunc mocks the GetAllTerms method.
+	GetAllTermsFunc func() ([]map[string]string, error)
+
+	// GetCollectionsFunc mocks the GetCollections method.
+	GetCollectionsFunc func(transcriptionID int64) ([]string, error)
+
+	// GetMediaFunc mocks the GetMedia method.
+	GetMediaFunc func(transcriptionID int64) (*database.Media, error)
+
+	// GetSegmentsFunc mocks the GetSegments method.
+	GetSegmentsFunc func(transcriptionID int64) ([]database.Segment, error)
+
+	// GetTagsFunc mocks the GetTags method.
+	GetTagsFunc func(transcriptionID int64) ([]string, error)
+
+	// GetTranscriptionFunc mocks the GetTranscription method.
+	GetTranscriptionFunc func(id int64) (string, error)
+
+	// GetTranscriptionRecordFunc mocks the GetTranscriptionRecord method.
+	GetTranscriptionRecordFunc func(id int64) (*database.Transcription, error)
+
+	// GetTranslationFunc mocks the GetTranslation method.
+	GetTranslationFunc func(transcriptionID int64) (string, error)
+
+	// GetWordsFunc mocks the GetWords method.
+	GetWordsFunc func(transcriptionID int64) ([]database.Word, error)
+
+	// InsertTranscriptionFunc mocks the InsertTranscription method.
+	InsertTranscriptionFunc func(t *database.Transcription) (int64, error)
+
+	// InsertTranslationFunc mocks the InsertTranslation method.
+	InsertTranslationFunc func(t *database.Translation) (int64, error)
+
+	// ListPendingFunc mocks the ListPending method.
+	ListPendingFunc func(status string) ([]database.PendingTranscription, error)
+
+	// ListStaleTranslationsFunc mocks the ListStaleTranslations method.
+	ListStaleTranslationsFunc func() ([]int64, error)
+
+	// ListTranscriptionsFunc mocks the ListTranscriptions method.
+	ListTranscriptionsFunc func() ([]database.TranscriptionListItem, error)
+
+	// ListTranslationsFunc mocks the ListTranslations method.
+	ListTranslationsFunc func() ([]database.Translation, error)
+
+	// SaveBundleFunc mocks the SaveBundle method.
+	SaveBundleFunc func(b *database.Bundle) (int64, error)
+
+	// SaveMediaFunc mocks the SaveMedia method.
+	SaveMediaFunc func(m *database.Media) error
+
+	// SavePendingFunc mocks the SavePending method.
+	SavePendingFunc func(p *database.PendingTranscription) (int64, error)
+
+	// SaveSegmentsFunc mocks the SaveSegments method.
+	SaveSegmentsFunc func(transcriptionID int64, segments []database.Segment) error
+
+	// SaveTermFunc mocks the SaveTerm method.
+	SaveTermFunc func(term string, description string) error
+
+	// SaveTranslationFunc mocks the SaveTranslation method.
+	SaveTranslationFunc func(transcriptionID int64, text string) error
+
+	// SaveWordsFunc mocks the SaveWords method.
+	SaveWordsFunc func(transcriptionID int64, words []database.Word) error
+
+	// SetupFunc mocks the Setup method.
+	SetupFunc func() error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Close holds details about calls to the Close method.
+		Close []struct {
+		}
+		// CompletePending holds details about calls to the CompletePending method.
+		CompletePending []struct {
+			// PendingID is the pendingID argument value.
+			PendingID int64
+			// B is the b argument value.
+			B *database.Bundle
+		}
+		// DeletePending holds details about calls to the DeletePending method.
+		DeletePending []struct {
+			// ID is the id argument value.
+			ID int64
+		}
+		// DeleteTerm holds details about calls to the DeleteTerm method.
+		DeleteTerm []struct {
+			// Term is the term argument value.
+			Term string
+		}
+		// FailPending holds details about calls to the FailPending method.
+		FailPending []struct {
+			// ID is the id argument value.
+			ID int64
+			// Message is the message argument value.
+			Message string
+		}
+		// GetAllTerms holds details about calls to the GetAllTerms method.
+		GetAllTerms []struct {
+		}
+		// GetCollections holds details about calls to the GetCollections method.
+		GetCollections []struct {
+			// TranscriptionID is the transcriptionID argument value.
+			TranscriptionID int64
+		}
+		// GetMedia holds details about calls to the GetMedia method.
+		GetMedia []struct {
+			// TranscriptionID is the transcriptionID argument value.
+			TranscriptionID int64
+		}
+		// GetSegments holds details about calls to the GetSegments method.
+		GetSegments []struct {
+			// TranscriptionID is the transcriptionID argument value.
+			TranscriptionID int64
+		}
+		// GetTags holds details about calls to the GetTags method.
+		GetTags []struct {
+			// TranscriptionID is the transcriptionID argument value.
+			TranscriptionID int64
+		}
+		// GetTranscription holds details about calls to the GetTranscription method.
+		GetTranscription []struct {
+			// ID is the id argument value.
+			ID int64
+		}
+		// GetTranscriptionRecord holds details about calls to the GetTranscriptionRecord method.
+		GetTranscriptionRecord []struct {
+			// ID is the id argument value.
+			ID int64
+		}
+		// GetTranslation holds details about calls to the GetTranslation method.
+		GetTranslation []struct {
+			// TranscriptionID is the transcriptionID argument value.
+			TranscriptionID int64
+		}
+		// GetWords holds details about calls to the GetWords method.
+		GetWords []struct {
+			// TranscriptionID is the transcriptionID argument value.
+			TranscriptionID int64
+		}
+		// InsertTranscription holds details about calls to the InsertTranscription method.
+		InsertTranscription []struct {
+			// T is the t argument value.
+			T *database.Transcription
+		}
+		// InsertTranslation holds details about calls to the InsertTranslation method.
+		InsertTranslation []struct {
+			// T is the t argument value.
+			T *database.Translation
+		}
+		// ListPending holds details about calls to the ListPending method.
+		ListPending []struct {
+			// Status is the status argument value.
+			Status string
+		}
+		// ListStaleTranslations holds details about calls to the ListStaleTranslations method.
+		ListStaleTranslations []struct {
+		}
+		// ListTranscriptions holds details about calls to the ListTranscriptions method.
+		ListTranscriptions []struct {
+		}
+		// ListTranslations holds details about calls to the ListTranslations method.
+		ListTranslations []struct {
+		}
+		// SaveBundle holds details about calls to the SaveBundle method.
+		SaveBundle []struct {
+			// B is the b argument value.
+			B *database.Bundle
+		}
+		// SaveMedia holds details about calls to the SaveMedia method.
+		SaveMedia []struct {
+			// M is the m argument value.
+			M *database.Media
+		}
+		// SavePending holds details about calls to the SavePending method.
+		SavePending []struct {
+			// P is the p argument value.
+			P *database.PendingTranscription
+		}
+		// SaveSegments holds details about calls to the SaveSegments method.
+		SaveSegments []struct {
+			// TranscriptionID is the transcriptionID argument value.
+			TranscriptionID int64
+			// Segments is the segments argument value.
+			Segments []database.Segment
+		}
+		// SaveTerm holds details about calls to the SaveTerm method.
+		SaveTerm []struct {
+			// Term is the term argument value.
+			Term string
+			// Description is the description argument value.
+			Description string
+		}
+		// SaveTranslation holds details about calls to the SaveTranslation method.
+		SaveTranslation []struct {
+			// TranscriptionID is the transcriptionID argument value.
+			TranscriptionID int64
+			// Text is the text argument value.
+			Text string
+		}
+		// SaveWords holds details about calls to the SaveWords method.
+		SaveWords []struct {
+			// TranscriptionID is the transcriptionID argument value.
+			TranscriptionID int64
+			// Words is the words argument value.
+			Words []database.Word
+		}
+		// Setup holds details about calls to the Setup method.
+		Setup []struct {
+		}
+	}
+	lockClose                  sync.RWMutex
+	lockCompletePending        sync.RWMutex
+	lockDeletePending          sync.RWMutex
+	lockDeleteTerm             sync.RWMutex
+	lockFailPending            sync.RWMutex
+	lockGetAllTerms            sync.RWMutex
+	lockGetCollections         sync.RWMutex
+	lockGetMedia               sync.RWMutex
+	lockGetSegments            sync.RWMutex
+	lockGetTags                sync.RWMutex
+	lockGetTranscription       sync.RWMutex
+	lockGetTranscriptionRecord sync.RWMutex
+	lockGetTranslation         sync.RWMutex
+	lockGetWords               sync.RWMutex
+	lockInsertTranscription    sync.RWMutex
+	lockInsertTranslation      sync.RWMutex
+	lockListPending            sync.RWMutex
+	lockListStaleTranslations  sync.RWMutex
+	lockListTranscriptions     sync.RWMutex
+	lockListTranslations       sync.RWMutex
+	lockSaveBundle             sync.RWMutex
+	lockSaveMedia              sync.RWMutex
+	lockSavePending            sync.RWMutex
+	lockSaveSegments           sync.RWMutex
+	lockSaveTerm               sync.RWMutex
+	lockSaveTranslation        sync.RWMutex
+	lockSaveWords              sync.RWMutex
+	lockSetup                  sync.RWMutex
+}
+
+// Close calls CloseFunc.
+func (mock *RepositoryMock) Close() error {
+	if mock.CloseFunc == nil {
+		panic("RepositoryMock.CloseFunc: method is nil but Repository.Close was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockClose.Lock()
+	mock.calls.Close = append(mock.calls.Close, callInfo)
+	mock.lockClose.Unlock()
+	return mock.CloseFunc()
+}
+
+// CloseCalls gets all the calls that were made to Close.
+// Check the length with:
+//
+//	len(mockedRepository.CloseCalls())
+func (mock *RepositoryMock) CloseCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockClose.RLock()
+	calls = mock.calls.Close
+	mock.lockClose.RUnlock()
+	return calls
+}
+
+// CompletePending calls CompletePendingFunc.
+func (mock *RepositoryMock) CompletePending(pendingID int64, b *database.Bundle) (int64, error) {
+	if mock.CompletePendingFunc == nil {
+		panic("RepositoryMock.CompletePendingFunc: method is nil but Repository.CompletePending was just called")
+	}
+	callInfo := struct {
+		PendingID int64
+		B         *database.Bundle
+	}{
+		PendingID: pendingID,
+		B:         b,
+	}
+	mock.lockCompletePending.Lock()
+	mock.calls.CompletePending = append(mock.calls.CompletePending, callInfo)
+	mock.lockCompletePending.Unlock()
+	return mock.CompletePendingFunc(pendingID, b)
+}
+
+// CompletePendingCalls gets all the calls that were made to CompletePending.
+// Check the length with:
+//
+//	len(mockedRepository.CompletePendingCalls())
+func (mock *RepositoryMock) CompletePendingCalls() []struct {
+	PendingID int64
+	B         *database.Bundle
+} {
+	var calls []struct {
+		PendingID int64
+		B         *database.Bundle
+	}
+	mock.lockCompletePending.RLock()
+	calls = mock.calls.CompletePending
+	mock.lockCompletePending.RUnlock()
+	return calls
+}
+
+// DeletePending calls DeletePendingFunc.
+func (mock *RepositoryMock) DeletePending(id int64) error {
+	if mock.DeletePendingFunc == nil {
+		panic("RepositoryMock.DeletePendingFunc: method is nil but Repository.DeletePending was just called")
+	}
+	callInfo := struct {
+		ID int64
+	}{
+		ID: id,
+	}
+	mock.lockDeletePending.Lock()
+	mock.calls.DeletePending = append(mock.calls.DeletePending, callInfo)
+	mock.lockDeletePending.Unlock()
+	return mock.DeletePendingFunc(id)
+}
+
+// DeletePendingCalls gets all the calls that were made to DeletePending.
+// Check the length with:
+//
+//	len(mockedRepository.DeletePendingCalls())
+func (mock *RepositoryMock) DeletePendingCalls() []struct {
+	ID int64
+} {
+	var calls []struct {
+		ID int64
+	}
+	mock.lockDeletePending.RLock()
+	calls = mock.calls.DeletePending
+	mock.lockDeletePending.RUnlock()
+	return calls
+}
+
+// DeleteTerm calls DeleteTermFunc.
+func (mock *RepositoryMock) DeleteTerm(term string) error {
+	if mock.DeleteTermFunc == nil {
+		panic("RepositoryMock.DeleteTermFunc: method is nil but Repository.DeleteTerm was just called")
+	}
+	callInfo := struct {
+		Term string
+	}{
+		Term: term,
+	}
+	mock.lockDeleteTerm.Lock()
+	mock.calls.DeleteTerm = append(mock.calls.DeleteTerm, callInfo)
+	mock.lockDeleteTerm.Unlock()
+	return mock.DeleteTermFunc(term)
+}
+
+// DeleteTermCalls gets all the calls that were made to DeleteTerm.
+// Check the length with:
+//
+//	len(mockedRepository.DeleteTermCalls())
+func (mock *RepositoryMock) DeleteTermCalls() []struct {
+	Term string
+} {
+	var calls []struct {
+		Term string
+	}
+	mock.lockDeleteTerm.RLock()
+	calls = mock.calls.DeleteTerm
+	mock.lockDeleteTerm.RUnlock()
+	return calls
+}
+
+// FailPending calls FailPendingFunc.
+func (mock *RepositoryMock) FailPending(id int64, message string) error {
+	if mock.FailPendingFunc == nil {
+		panic("RepositoryMock.FailPendingFunc: method is nil but Repository.FailPending was just called")
+	}
+	callInfo := struct {
+		ID      int64
+		Message string
+	}{
+		ID:      id,
+		Message: message,
+	}
+	mock.lockFailPending.Lock()
+	mock.calls.FailPending = append(mock.calls.FailPending, callInfo)
+	mock.lockFailPending.Unlock()
+	return mock.FailPendingFunc(id, message)
+}
+
+// FailPendingCalls gets all the calls that were made to FailPending.
+// Check the length with:
+//
+//	len(mockedRepository.FailPendingCalls())
+func (mock *RepositoryMock) FailPendingCalls() []struct {
+	ID      int64
+	Message string
+} {
+	var calls []struct {
+		ID      int64
+		Message string
+	}
+	mock.lockFailPending.RLock()
+	calls = mock.calls.FailPending
+	mock.lockFailPending.RUnlock()
+	return calls
+}
+
+// GetAllTerms calls GetAllTermsFunc.
+func (mock *RepositoryMock) GetAllTerms() ([]map[string]string, error) {
+	if mock.GetAllTermsFunc == nil {
+		panic("RepositoryMock.GetAllTermsFunc: method is nil but Repository.GetAllTerms was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockGetAllTerms.Lock()
+	mock.calls.GetAllTerms = append(mock.calls.GetAllTerms, callInfo)
+	mock.lockGetAllTerms.Unlock()
+	return mock.GetAllTermsFunc()
+}
+
+// GetAllTermsCalls gets all the calls that were made to GetAllTerms.
+// Check the length with:
+//
+//	len(mockedRepository.GetAllTermsCalls())
+func (mock *RepositoryMock) GetAllTermsCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockGetAllTerms.RLock()
+	calls = mock.calls.GetAllTerms
+	mock.lockGetAllTerms.RUnlock()
+	return calls
+}
+
+// GetCollections calls GetCollectionsFunc.
+func (mock *RepositoryMock) GetCollections(transcriptionID int64) ([]string, error) {
+	if mock.GetCollectionsFunc == nil {
+		panic("RepositoryMock.GetCollectionsFunc: method is nil but Repository.GetCollections was just called")
+	}
+	callInfo := struct {
+		TranscriptionID int64
+	}{
+		TranscriptionID: transcriptionID,
+	}
+	mock.lockGetCollections.Lock()
+	mock.calls.GetCollections = append(mock.calls.GetCollections, callInfo)
+	mock.lockGetCollections.Unlock()
+	return mock.GetCollectionsFunc(transcriptionID)
+}
+
+// GetCollectionsCalls gets all the calls that were made to GetCollections.
+// Check the length with:
+//
+//	len(mockedRepository.GetCollectionsCalls())
+func (mock *RepositoryMock) GetCollectionsCalls() []struct {
+	TranscriptionID int64
+} {
+	var calls []struct {
+		TranscriptionID int64
+	}
+	mock.lockGetCollections.RLock()
+	calls = mock.calls.GetCollections
+	mock.lockGetCollections.RUnlock()
+	return calls
+}
+
+// GetMedia calls GetMediaFunc.
+func (mock *RepositoryMock) GetMedia(transcriptionID int64) (*database.Media, error) {
+	if mock.GetMediaFunc == nil {
+		panic("RepositoryMock.GetMediaFunc: method is nil but Repository.GetMedia was just called")
+	}
+	callInfo := struct {
+		TranscriptionID int64
+	}{
+		TranscriptionID: transcriptionID,
+	}
+	mock.lockGetMedia.Lock()
+	mock.calls.GetMedia = append(mock.calls.GetMedia, callInfo)
+	mock.lockGetMedia.Unlock()
+	return mock.GetMediaFunc(transcriptionID)
+}
+
+// GetMediaCalls gets all the calls that were made to GetMedia.
+// Check the length with:
+//
+//	len(mockedRepository.GetMediaCalls())
+func (mock *RepositoryMock) GetMediaCalls() []struct {
+	TranscriptionID int64
+} {
+	var calls []struct {
+		TranscriptionID int64
+	}
+	mock.lockGetMedia.RLock()
+	calls = mock.calls.GetMedia
+	mock.lockGetMedia.RUnlock()
+	return calls
+}
+
+// GetSegments calls GetSegmentsFunc.
+func (mock *RepositoryMock) GetSegments(transcriptionID int64) ([]database.Segment, error) {
+	if mock.GetSegmentsFunc == nil {
+		panic("RepositoryMock.GetSegmentsFunc: method is nil but Repository.GetSegments was just called")
+	}
+	callInfo := struct {
+		TranscriptionID int64
+	}{
+		TranscriptionID: transcriptionID,
+	}
+	mock.lockGetSegments.Lock()
+	mock.calls.GetSegments = append(mock.calls.GetSegments, callInfo)
+	mock.lockGetSegments.Unlock()
+	return mock.GetSegmentsFunc(transcriptionID)
+}
+
+// GetSegmentsCalls gets all the calls that were made to GetSegments.
+// Check the length with:
+//
+//	len(mockedRepository.GetSegmentsCalls())
+func (mock *RepositoryMock) GetSegmentsCalls() []struct {
+	TranscriptionID int64
+} {
+	var calls []struct {
+		TranscriptionID int64
+	}
+	mock.lockGetSegments.RLock()
+	calls = mock.calls.GetSegments
+	mock.lockGetSegments.RUnlock()
+	return calls
+}
+
+// GetTags calls GetTagsFunc.
+func (mock *RepositoryMock) GetTags(transcriptionID int64) ([]string, error) {
+	if mock.GetTagsFunc == nil {
+		panic("RepositoryMock.GetTagsFunc: method is nil but Repository.GetTags was just called")
+	}
+	callInfo := struct {
+		TranscriptionID int64
+	}{
+		TranscriptionID: transcriptionID,
+	}
+	mock.lockGetTags.Lock()
+	mock.calls.GetTags = append(mock.calls.GetTags, callInfo)
+	mock.lockGetTags.Unlock()
+	return mock.GetTagsFunc(transcriptionID)
+}
+
+// GetTagsCalls gets all the calls that were made to GetTags.
+// Check the length with:
+//
+//	len(mockedRepository.GetTagsCalls())
+func (mock *RepositoryMock) GetTagsCalls() []struct {
+	TranscriptionID int64
+} {
+	var calls []struct {
+		TranscriptionID int64
+	}
+	mock.lockGetTags.RLock()
+	calls = mock.calls.GetTags
+	mock.lockGetTags.RUnlock()
+	return calls
+}
+
+// GetTranscription calls GetTranscriptionFunc.
+func (mock *RepositoryMock) GetTranscription(id int64) (string, error) {
+	if mock.GetTranscriptionFunc == nil {
+		panic("RepositoryMock.GetTranscriptionFunc: method is nil but Repository.GetTranscription was just called")
+	}
+	callInfo := struct {
+		ID int64
+	}{
+		ID: id,
+	}
+	mock.lockGetTranscription.Lock()
+	mock.calls.GetTranscription = append(mock.calls.GetTranscription, callInfo)
+	mock.lockGetTranscription.Unlock()
+	return mock.GetTranscriptionFunc(id)
+}
+
+// GetTranscriptionCalls gets all the calls that were made to GetTranscription.
+// Check the length with:
+//
+//	len(mockedRepository.GetTranscriptionCalls())
+func (mock *RepositoryMock) GetTranscriptionCalls() []struct {
+	ID int64
+} {
+	var calls []struct {
+		ID int64
+	}
+	mock.lockGetTranscription.RLock()
+	calls = mock.calls.GetTranscription
+	mock.lockGetTranscription.RUnlock()
+	return calls
+}
+
+// GetTranscriptionRecord calls GetTranscriptionRecordFunc.
+func (mock *RepositoryMock) GetTranscriptionRecord(id int64) (*database.Transcription, error) {
+	if mock.GetTranscriptionRecordFunc == nil {
+		panic("RepositoryMock.GetTranscriptionRecordFunc: method is nil but Repository.GetTranscriptionRecord was just called")
+	}
+	callInfo := struct {
+		ID int64
+	}{
+		ID: id,
+	}
+	mock.lockGetTranscriptionRecord.Lock()
+	mock.calls.GetTranscriptionRecord = append(mock.calls.GetTranscriptionRecord, callInfo)
+	mock.lockGetTranscriptionRecord.Unlock()
+	return mock.GetTranscriptionRecordFunc(id)
+}
+
+// GetTranscriptionRecordCalls gets all the calls that were made to GetTranscriptionRecord.
+// Check the length with:
+//
+//	len(mockedRepository.GetTranscriptionRecordCalls())
+func (mock *RepositoryMock) GetTranscriptionRecordCalls() []struct {
+	ID int64
+} {
+	var calls []struct {
+		ID int64
+	}
+	mock.lockGetTranscriptionRecord.RLock()
+	calls = mock.calls.GetTranscriptionRecord
+	mock.lockGetTranscriptionRecord.RUnlock()
+	return calls
+}
+
+// GetTranslation calls GetTranslationFunc.
+func (mock *RepositoryMock) GetTranslation(transcriptionID int64) (string, error) {
+	if mock.GetTranslationFunc == nil {
+		panic("RepositoryMock.GetTranslationFunc: method is nil but Repository.GetTranslation was just called")
+	}
+	callInfo := struct {
+		TranscriptionID int64
+	}{
+		TranscriptionID: transcriptionID,
+	}
+	mock.lockGetTranslation.Lock()
+	mock.calls.GetTranslation = append(mock.calls.GetTranslation, callInfo)
+	mock.lockGetTranslation.Unlock()
+	return mock.GetTranslationFunc(transcriptionID)
+}
+
+// GetTranslationCalls gets all the calls that were made to GetTranslation.
+// Check the length with:
+//
+//	len(mockedRepository.GetTranslationCalls())
+func (mock *RepositoryMock) GetTranslationCalls() []struct {
+	TranscriptionID int64
+} {
+	var calls []struct {
+		TranscriptionID int64
+	}
+	mock.lockGetTranslation.RLock()
+	calls = mock.calls.GetTranslation
+	mock.lockGetTranslation.RUnlock()
+	return calls
+}
+
+// GetWords calls GetWordsFunc.
+func (mock *RepositoryMock) GetWords(transcriptionID int64) ([]database.Word, error) {
+	if mock.GetWordsFunc == nil {
+		panic("RepositoryMock.GetWordsFunc: method is nil but Repository.GetWords was just called")
+	}
+	callInfo := struct {
+		TranscriptionID int64
+	}{
+		TranscriptionID: transcriptionID,
+	}
+	mock.lockGetWords.Lock()
+	mock.calls.GetWords = append(mock.calls.GetWords, callInfo)
+	mock.lockGetWords.Unlock()
+	return mock.GetWordsFunc(transcriptionID)
+}
+
+// GetWordsCalls gets all the calls that were made to GetWords.
+// Check the length with:
+//
+//	len(mockedRepository.GetWordsCalls())
+func (mock *RepositoryMock) GetWordsCalls() []struct {
+	TranscriptionID int64
+} {
+	var calls []struct {
+		TranscriptionID int64
+	}
+	mock.lockGetWords.RLock()
+	calls = mock.calls.GetWords
+	mock.lockGetWords.RUnlock()
+	return calls
+}
+
+// InsertTranscription calls InsertTranscriptionFunc.
+func (mock *RepositoryMock) InsertTranscription(t *database.Transcription) (int64, error) {
+	if mock.InsertTranscriptionFunc == nil {
+		panic("RepositoryMock.InsertTranscriptionFunc: method is nil but Repository.InsertTranscription was just called")
+	}
+	callInfo := struct {
+		T *database.Transcription
+	}{
+		T: t,
+	}
+	mock.lockInsertTranscription.Lock()
+	mock.calls.InsertTranscription = append(mock.calls.InsertTranscription, callInfo)
+	mock.lockInsertTranscription.Unlock()
+	return mock.InsertTranscriptionFunc(t)
+}
+
+// InsertTranscriptionCalls gets all the calls that were made to InsertTranscription.
+// Check the length with:
+//
+//	len(mockedRepository.InsertTranscriptionCalls())
+func (mock *RepositoryMock) InsertTranscriptionCalls() []struct {
+	T *database.Transcription
+} {
+	var calls []struct {
+		T *database.Transcription
+	}
+	mock.lockInsertTranscription.RLock()
+	calls = mock.calls.InsertTranscription
+	mock.lockInsertTranscription.RUnlock()
+	return calls
+}
+
+// InsertTranslation calls InsertTranslationFunc.
+func (mock *RepositoryMock) InsertTranslation(t *database.Translation) (int64, error) {
+	if mock.InsertTranslationFunc == nil {
+		panic("RepositoryMock.InsertTranslationFunc: method is nil but Repository.InsertTranslation was just called")
+	}
+	callInfo := struct {
+		T *database.Translation
+	}{
+		T: t,
+	}
+	mock.lockInsertTranslation.Lock()
+	mock.calls.InsertTranslation = append(mock.calls.InsertTranslation, callInfo)
+	mock.lockInsertTranslation.Unlock()
+	return mock.InsertTranslationFunc(t)
+}
+
+// InsertTranslationCalls gets all the calls that were made to InsertTranslation.
+// Check the length with:
+//
+//	len(mockedRepository.InsertTranslationCalls())
+func (mock *RepositoryMock) InsertTranslationCalls() []struct {
+	T *database.Translation
+} {
+	var calls []struct {
+		T *database.Translation
+	}
+	mock.lockInsertTranslation.RLock()
+	calls = mock.calls.InsertTranslation
+	mock.lockInsertTranslation.RUnlock()
+	return calls
+}
+
+// ListPending calls ListPendingFunc.
+func (mock *RepositoryMock) ListPending(status string) ([]database.PendingTranscription, error) {
+	if mock.ListPendingFunc == nil {
+		panic("RepositoryMock.ListPendingFunc: method is nil but Repository.ListPending was just called")
+	}
+	callInfo := struct {
+		Status string
+	}{
+		Status: status,
+	}
+	mock.lockListPending.Lock()
+	mock.calls.ListPending = append(mock.calls.ListPending, callInfo)
+	mock.lockListPending.Unlock()
+	return mock.ListPendingFunc(status)
+}
+
+// ListPendingCalls gets all the calls that were made to ListPending.
+// Check the length with:
+//
+//	len(mockedRepository.ListPendingCalls())
+func (mock *RepositoryMock) ListPendingCalls() []struct {
+	Status string
+} {
+	var calls []struct {
+		Status string
+	}
+	mock.lockListPending.RLock()
+	calls = mock.calls.ListPending
+	mock.lockListPending.RUnlock()
+	return calls
+}
+
+// ListStaleTranslations calls ListStaleTranslationsFunc.
+func (mock *RepositoryMock) ListStaleTranslations() ([]int64, error) {
+	if mock.ListStaleTranslationsFunc == nil {
+		panic("RepositoryMock.ListStaleTranslationsFunc: method is nil but Repository.ListStaleTranslations was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockListStaleTranslations.Lock()
+	mock.calls.ListStaleTranslations = append(mock.calls.ListStaleTranslations, callInfo)
+	mock.lockListStaleTranslations.Unlock()
+	return mock.ListStaleTranslationsFunc()
+}
+
+// ListStaleTranslationsCalls gets all the calls that were made to ListStaleTranslations.
+// Check the length with:
+//
+//	len(mockedRepository.ListStaleTranslationsCalls())
+func (mock *RepositoryMock) ListStaleTranslationsCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockListStaleTranslations.RLock()
+	calls = mock.calls.ListStaleTranslations
+	mock.lockListStaleTranslations.RUnlock()
+	return calls
+}
+
+// ListTranscriptions calls ListTranscriptionsFunc.
+func (mock *RepositoryMock) ListTranscriptions() ([]database.TranscriptionListItem, error) {
+	if mock.ListTranscriptionsFunc == nil {
+		panic("RepositoryMock.ListTranscriptionsFunc: method is nil but Repository.ListTranscriptions was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockListTranscriptions.Lock()
+	mock.calls.ListTranscriptions = append(mock.calls.ListTranscriptions, callInfo)
+	mock.lockListTranscriptions.Unlock()
+	return mock.ListTranscriptionsFunc()
+}
+
+// ListTranscriptionsCalls gets all the calls that were made to ListTranscriptions.
+// Check the length with:
+//
+//	len(mockedRepository.ListTranscriptionsCalls())
+func (mock *RepositoryMock) ListTranscriptionsCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockListTranscriptions.RLock()
+	calls = mock.calls.ListTranscriptions
+	mock.lockListTranscriptions.RUnlock()
+	return calls
+}
+
+// ListTranslations calls ListTranslationsFunc.
+func (mock *RepositoryMock) ListTranslations() ([]database.Translation, error) {
+	if mock.ListTranslationsFunc == nil {
+		panic("RepositoryMock.ListTranslationsFunc: method is nil but Repository.ListTranslations was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockListTranslations.Lock()
+	mock.calls.ListTranslations = append(mock.calls.ListTranslations, callInfo)
+	mock.lockListTranslations.Unlock()
+	return mock.ListTranslationsFunc()
+}
+
+// ListTranslationsCalls gets all the calls that were made to ListTranslations.
+// Check the length with:
+//
+//	len(mockedRepository.ListTranslationsCalls())
+func (mock *RepositoryMock) ListTranslationsCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockListTranslations.RLock()
+	calls = mock.calls.ListTranslations
+	mock.lockListTranslations.RUnlock()
+	return calls
+}
+
+// SaveBundle calls SaveBundleFunc.
+func (mock *RepositoryMock) SaveBundle(b *database.Bundle) (int64, error) {
+	if mock.SaveBundleFunc == nil {
+		panic("RepositoryMock.SaveBundleFunc: method is nil but Repository.SaveBundle was just called")
+	}
+	callInfo := struct {
+		B *database.Bundle
+	}{
+		B: b,
+	}
+	mock.lockSaveBundle.Lock()
+	mock.calls.SaveBundle = append(mock.calls.SaveBundle, callInfo)
+	mock.lockSaveBundle.Unlock()
+	return mock.SaveBundleFunc(b)
+}
+
+// SaveBundleCalls gets all the calls that were made to SaveBundle.
+// Check the length with:
+//
+//	len(mockedRepository.SaveBundleCalls())
+func (mock *RepositoryMock) SaveBundleCalls() []struct {
+	B *database.Bundle
+} {
+	var calls []struct {
+		B *database.Bundle
+	}
+	mock.lockSaveBundle.RLock()
+	calls = mock.calls.SaveBundle
+	mock.lockSaveBundle.RUnlock()
+	return calls
+}
+
+// SaveMedia calls SaveMediaFunc.
+func (mock *RepositoryMock) SaveMedia(m *database.Media) error {
+	if mock.SaveMediaFunc == nil {
+		panic("RepositoryMock.SaveMediaFunc: method is nil but Repository.SaveMedia was just called")
+	}
+	callInfo := struct {
+		M *database.Media
+	}{
+		M: m,
+	}
+	mock.lockSaveMedia.Lock()
+	mock.calls.SaveMedia = append(mock.calls.SaveMedia, callInfo)
+	mock.lockSaveMedia.Unlock()
+	return mock.SaveMediaFunc(m)
+}
+
+// SaveMediaCalls gets all the calls that were made to SaveMedia.
+// Check the length with:
+//
+//	len(mockedRepository.SaveMediaCalls())
+func (mock *RepositoryMock) SaveMediaCalls() []struct {
+	M *database.Media
+} {
+	var calls []struct {
+		M *database.Media
+	}
+	mock.lockSaveMedia.RLock()
+	calls = mock.calls.SaveMedia
+	mock.lockSaveMedia.RUnlock()
+	return calls
+}
+
+// SavePending calls SavePendingFunc.
+func (mock *RepositoryMock) SavePending(p *database.PendingTranscription) (int64, error) {
+	if mock.SavePendingFunc == nil {
+		panic("RepositoryMock.SavePendingFunc: method is nil but Repository.SavePending was just called")
+	}
+	callInfo := struct {
+		P *database.PendingTranscription
+	}{
+		P: p,
+	}
+	mock.lockSavePending.Lock()
+	mock.calls.SavePending = append(mock.calls.SavePending, callInfo)
+	mock.lockSavePending.Unlock()
+	return mock.SavePendingFunc(p)
+}
+
+// SavePendingCalls gets all the calls that were made to SavePending.
+// Check the length with:
+//
+//	len(mockedRepository.SavePendingCalls())
+func (mock *RepositoryMock) SavePendingCalls() []struct {
+	P *database.PendingTranscription
+} {
+	var calls []struct {
+		P *database.PendingTranscription
+	}
+	mock.lockSavePending.RLock()
+	calls = mock.calls.SavePending
+	mock.lockSavePending.RUnlock()
+	return calls
+}
+
+// SaveSegments calls SaveSegmentsFunc.
+func (mock *RepositoryMock) SaveSegments(transcriptionID int64, segments []database.Segment) error {
+	if mock.SaveSegmentsFunc == nil {
+		panic("RepositoryMock.SaveSegmentsFunc: method is nil but Repository.SaveSegments was just called")
+	}
+	callInfo := struct {
+		TranscriptionID int64
+		Segments        []database.Segment
+	}{
+		TranscriptionID: transcriptionID,
+		Segments:        segments,
+	}
+	mock.lockSaveSegments.Lock()
+	mock.calls.SaveSegments = append(mock.calls.SaveSegments, callInfo)
+	mock.lockSaveSegments.Unlock()
+	return mock.SaveSegmentsFunc(transcriptionID, segments)
+}
+
+// SaveSegmentsCalls gets all the calls that were made to SaveSegments.
+// Check the length with:
+//
+//	len(mockedRepository.SaveSegmentsCalls())
+func (mock *RepositoryMock) SaveSegmentsCalls() []struct {
+	TranscriptionID int64
+	Segments        []database.Segment
+} {
+	var calls []struct {
+		TranscriptionID int64
+		Segments        []database.Segment
+	}
+	mock.lockSaveSegments.RLock()
+	calls = mock.calls.SaveSegments
+	mock.lockSaveSegments.RUnlock()
+	return calls
+}
+
+// SaveTerm calls SaveTermFunc.
+func (mock *RepositoryMock) SaveTerm(term string, description string) error {
+	if mock.SaveTermFunc == nil {
+		panic("RepositoryMock.SaveTermFunc: method is nil but Repository.SaveTerm was just called")
+	}
+	callInfo := struct {
+		Term        string
+		Description string
+	}{
+		Term:        term,
+		Description: description,
+	}
+	mock.lockSaveTerm.Lock()
+	mock.calls.SaveTerm = append(mock.calls.SaveTerm, callInfo)
+	mock.lockSaveTerm.Unlock()
+	return mock.SaveTermFunc(term, description)
+}
+
+// SaveTermCalls gets all the calls that were made to SaveTerm.
+// Check the length with:
+//
+//	len(mockedRepository.SaveTermCalls())
+func (mock *RepositoryMock) SaveTermCalls() []struct {
+	Term        string
+	Description string
+} {
+	var calls []struct {
+		Term        string
+		Description string
+	}
+	mock.lockSaveTerm.RLock()
+	calls = mock.calls.SaveTerm
+	mock.lockSaveTerm.RUnlock()
+	return calls
+}
+
+// SaveTranslation calls SaveTranslationFunc.
+func (mock *RepositoryMock) SaveTranslation(transcriptionID int64, text string) error {
+	if mock.SaveTranslationFunc == nil {
+		panic("RepositoryMock.SaveTranslationFunc: method is nil but Repository.SaveTranslation was just called")
+	}
+	callInfo := struct {
+		TranscriptionID int64
+		Text            string
+	}{
+		TranscriptionID: transcriptionID,
+		Text:            text,
+	}
+	mock.lockSaveTranslation.Lock()
+	mock.calls.SaveTranslation = append(mock.calls.SaveTranslation, callInfo)
+	mock.lockSaveTranslation.Unlock()
+	return mock.SaveTranslationFunc(transcriptionID, text)
+}
+
+// SaveTranslationCalls gets all the calls that were made to SaveTranslation.
+// Check the length with:
+//
+//	len(mockedRepository.SaveTranslationCalls())
+func (mock *RepositoryMock) SaveTranslationCalls() []struct {
+	TranscriptionID int64
+	Text            string
+} {
+	var calls []struct {
+		TranscriptionID int64
+		Text            string
+	}
+	mock.lockSaveTranslation.RLock()
+	calls = mock.calls.SaveTranslation
+	mock.lockSaveTranslation.RUnlock()
+	return calls
+}
+
+// SaveWords calls SaveWordsFunc.
+func (mock *RepositoryMock) SaveWords(transcriptionID int64, words []database.Word) error {
+	if mock.SaveWordsFunc == nil {
+		panic("RepositoryMock.SaveWordsFunc: method is nil but Repository.SaveWords was just called")
+	}
+	callInfo := struct {
+		TranscriptionID int64
+		Words           []database.Word
+	}{
+		TranscriptionID: transcriptionID,
+		Words:           words,
+	}
+	mock.lockSaveWords.Lock()
+	mock.calls.SaveWords = append(mock.calls.SaveWords, callInfo)
+	mock.lockSaveWords.Unlock()
+	return mock.SaveWordsFunc(transcriptionID, words)
+}
+
+// SaveWordsCalls gets all the calls that were made to SaveWords.
+// Check the length with:
+//
+//	len(mockedRepository.SaveWordsCalls())
+func (mock *RepositoryMock) SaveWordsCalls() []struct {
+	TranscriptionID int64
+	Words           []database.Word
+} {
+	var calls []struct {
+		TranscriptionID int64
+		Words           []database.Word
+	}
+	mock.lockSaveWords.RLock()
+	calls = mock.calls.SaveWords
+	mock.lockSaveWords.RUnlock()
+	return calls
+}
+
+// Setup calls SetupFunc.
+func (mock *RepositoryMock) Setup() error {
+	if mock.SetupFunc == nil {
+		panic("RepositoryMock.SetupFunc: method is nil but Repository.Setup was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockSetup.Lock()
+	mock.calls.Setup = append(mock.calls.Setup, callInfo)
+	mock.lockSetup.Unlock()
+	return mock.SetupFunc()
+}
+
+// SetupCalls gets all the calls that were made to Setup.
+// Check the length with:
+//
+//	len(mockedRepository.SetupCalls())
+func (mock *RepositoryMock) SetupCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockSetup.RLock()
+	calls = mock.calls.Setup
+	mock.lockSetup.RUnlock()
+	return calls
+}
@@ -5,6 +5,7 @@ package mocks
 
 import (
 	"assemblyai-transcriber/internal/interfaces"
+	"assemblyai-transcriber/internal/transcribe"
 	"context"
 	"sync"
 )
@@ -19,7 +20,16 @@ var _ interfaces.Transcriber = &TranscriberMock{}
 //
 //		// make and configure a mocked interfaces.Transcriber
 //		mockedTranscriber := &TranscriberMock{
-//			TranscribeVideoFunc: func(ctx context.Context, videoPath string) (string, error) {
+//			AddWordBoostFunc: func(words ...string)  {
+//				panic("mock out the AddWordBoost method")
+//			},
+//			CollectFunc: func(ctx context.Context, sub *transcribe.Submission) (*transcribe.Result, error) {
+//				panic("mock out the Collect method")
+//			},
+//			SubmitFunc: func(ctx context.Context, videoPath string) (*transcribe.Submission, error) {
+//				panic("mock out the Submit method")
+//			},
+//			TranscribeVideoFunc: func(ctx context.Context, videoPath string) (*transcribe.Result, error) {
 //				panic("mock out the TranscribeVideo method")
 //			},
 //		}
@@ -29,11 +39,39 @@ var _ interfaces.Transcriber = &TranscriberMock{}
 //
 //	}
 type TranscriberMock struct {
+	// AddWordBoostFunc mocks the AddWordBoost method.
+	AddWordBoostFunc func(words ...string)
+
+	// CollectFunc mocks the Collect method.
+	CollectFunc func(ctx context.Context, sub *transcribe.Submission) (*transcribe.Result, error)
+
+	// SubmitFunc mocks the Submit method.
+	SubmitFunc func(ctx context.Context, videoPath string) (*transcribe.Submission, error)
+
 	// TranscribeVideoFunc mocks the TranscribeVideo method.
-	TranscribeVideoFunc func(ctx context.Context, videoPath string) (string, error)
+	TranscribeVideoFunc func(ctx context.Context, videoPath string) (*transcribe.Result, error)
 
 	// calls tracks calls to the methods.
 	calls struct {
+		// AddWordBoost holds details about calls to the AddWordBoost method.
+		AddWordBoost []struct {
+			// Words is the words argument value.
+			Words []string
+		}
+		// Collect holds details about calls to the Collect method.
+		Collect []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Sub is the sub argument value.
+			Sub *transcribe.Submission
+		}
+		// Submit holds details about calls to the Submit method.
+		Submit []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// VideoPath is the videoPath argument value.
+			VideoPath string
+		}
 		// TranscribeVideo holds details about calls to the TranscribeVideo method.
 		TranscribeVideo []struct {
 			// Ctx is the ctx argument value.
@@ -42,11 +80,118 @@ type TranscriberMock struct {
 			VideoPath string
 		}
 	}
+	lockAddWordBoost    sync.RWMutex
+	lockCollect         sync.RWMutex
+	lockSubmit          sync.RWMutex
 	lockTranscribeVideo sync.RWMutex
 }
 
+// AddWordBoost calls AddWordBoostFunc.
+func (mock *TranscriberMock) AddWordBoost(words ...string) {
+	if mock.AddWordBoostFunc == nil {
+		panic("TranscriberMock.AddWordBoostFunc: method is nil but Transcriber.AddWordBoost was just called")
+	}
+	callInfo := struct {
+		Words []string
+	}{
+		Words: words,
+	}
+	mock.lockAddWordBoost.Lock()
+	mock.calls.AddWordBoost = append(mock.calls.AddWordBoost, callInfo)
+	mock.lockAddWordBoost.Unlock()
+	mock.AddWordBoostFunc(words...)
+}
+
+// AddWordBoostCalls gets all the calls that were made to AddWordBoost.
+// Check the length with:
+//
+//	len(mockedTranscriber.AddWordBoostCalls())
+func (mock *TranscriberMock) AddWordBoostCalls() []struct {
+	Words []string
+} {
+	var calls []struct {
+		Words []string
+	}
+	mock.lockAddWordBoost.RLock()
+	calls = mock.calls.AddWordBoost
+	mock.lockAddWordBoost.RUnlock()
+	return calls
+}
+
+// Collect calls CollectFunc.
+func (mock *TranscriberMock) Collect(ctx context.Context, sub *transcribe.Submission) (*transcribe.Result, error) {
+	if mock.CollectFunc == nil {
+		panic("TranscriberMock.CollectFunc: method is nil but Transcriber.Collect was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Sub *transcribe.Submission
+	}{
+		Ctx: ctx,
+		Sub: sub,
+	}
+	mock.lockCollect.Lock()
+	mock.calls.Collect = append(mock.calls.Collect, callInfo)
+	mock.lockCollect.Unlock()
+	return mock.CollectFunc(ctx, sub)
+}
+
+// CollectCalls gets all the calls that were made to Collect.
+// Check the length with:
+//
+//	len(mockedTranscriber.CollectCalls())
+func (mock *TranscriberMock) CollectCalls() []struct {
+	Ctx context.Context
+	Sub *transcribe.Submission
+} {
+	var calls []struct {
+		Ctx context.Context
+		Sub *transcribe.Submission
+	}
+	mock.lockCollect.RLock()
+	calls = mock.calls.Collect
+	mock.lockCollect.RUnlock()
+	return calls
+}
+
+// Submit calls SubmitFunc.
+func (mock *TranscriberMock) Submit(ctx context.Context, videoPath string) (*transcribe.Submission, error) {
+	if mock.SubmitFunc == nil {
+		panic("TranscriberMock.SubmitFunc: method is nil but Transcriber.Submit was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		VideoPath string
+	}{
+		Ctx:       ctx,
+		VideoPath: videoPath,
+	}
+	mock.lockSubmit.Lock()
+	mock.calls.Submit = append(mock.calls.Submit, callInfo)
+	mock.lockSubmit.Unlock()
+	return mock.SubmitFunc(ctx, videoPath)
+}
+
+// SubmitCalls gets all the calls that were made to Submit.
+// Check the length with:
+//
+//	len(mockedTranscriber.SubmitCalls())
+func (mock *TranscriberMock) SubmitCalls() []struct {
+	Ctx       context.Context
+	VideoPath string
+} {
+	var calls []struct {
+		Ctx       context.Context
+		VideoPath string
+	}
+	mock.lockSubmit.RLock()
+	calls = mock.calls.Submit
+	mock.lockSubmit.RUnlock()
+	return calls
+}
+
 // TranscribeVideo calls TranscribeVideoFunc.
-func (mock *TranscriberMock) TranscribeVideo(ctx context.Context, videoPath string) (string, error) {
+func (mock *TranscriberMock) TranscribeVideo(ctx context.Context, videoPath string) (*transcribe.Result, error) {
 	if mock.TranscribeVideoFunc == nil {
 		panic("TranscriberMock.TranscribeVideoFunc: method is nil but Transcriber.TranscribeVideo was just called")
 	}
@@ -18,6 +18,9 @@ const (
 	defaultTimeout = 300 * time.Second
 )
 
+// Model is the model used for term analysis and translation
+const Model = "meta-llama/llama-4-maverick"
+
 // Client represents the OpenRouter API client
 type Client struct {
 	apiKey     string
@@ -85,7 +88,7 @@ func (c *Client) AnalyzeTerms(text string) (*TermAnalysis, error) {
 
 	// create the completion request
 	req := CompletionRequest{
-		Model: "meta-llama/llama-4-maverick",
+		Model: Model,
 		Messages: []Message{
 			{
 				Role:    "user",
@@ -150,8 +153,9 @@ func (c *Client) AnalyzeTerms(text string) (*TermAnalysis, error) {
 	return &analysis, nil
 }
 
-// TranslateTextChunk translates a single chunk of text from English to Russian, preserving specified terms
-func (c *Client) TranslateTextChunk(chunk string, terms []string) (string, error) {
+// TranslateTextChunk translates a single chunk of text between the named languages, e.g. from
+// "English" to "Russian", preserving specified terms
+func (c *Client) TranslateTextChunk(chunk string, terms []string, source, target string) (string, error) {
 	if strings.TrimSpace(chunk) == "" {
 		return "", fmt.Errorf("input chunk is empty")
 	}
@@ -161,11 +165,11 @@ func (c *Client) TranslateTextChunk(chunk string, terms []string) (string, error
 		termsList += "- " + term + "\n"
 	}
 
-	prompt := fmt.Sprintf(translateTextPrompt, "English", "Russian", termsList, chunk)
+	prompt := fmt.Sprintf(translateTextPrompt, source, target, termsList, chunk)
 
 	// create the completion request
 	req := CompletionRequest{
-		Model: "meta-llama/llama-4-maverick",
+		Model: Model,
 		Messages: []Message{
 			{
 				Role:    "user",
@@ -188,8 +192,8 @@ func (c *Client) TranslateTextChunk(chunk string, terms []string) (string, error
 	return resp.Choices[0].Message.Content, nil
 }
 
-// TranslateText translates text from English to Russian in chunks, preserving specified terms
-func (c *Client) TranslateText(text string, terms []string) (string, error) {
+// TranslateText translates text between the named languages in chunks, preserving specified terms
+func (c *Client) TranslateText(text string, terms []string, source, target string) (string, error) {
 	// split text into paragraphs
 	paragraphs := splitIntoParagraphs(text)
 
@@ -216,7 +220,7 @@ func (c *Client) TranslateText(text string, terms []string) (string, error) {
 	for i, chunk := range chunks {
 		fmt.Printf("Translating chunk %d of %d...\n", i+1, len(chunks))
 
-		translatedChunk, err := c.TranslateTextChunk(chunk, terms)
+		translatedChunk, err := c.TranslateTextChunk(chunk, terms, source, target)
 		if err != nil {
 			return "", fmt.Errorf("error translating chunk %d: %w", i+1, err)
 		}
@@ -71,7 +71,7 @@ func TestClient_AnalyzeTerms_EmptyInput(t *testing.T) {
 
 func TestClient_TranslateTextChunk_EmptyInput(t *testing.T) {
 	client := newTestClient("", http.StatusOK)
-	result, err := client.TranslateTextChunk("", nil)
+	result, err := client.TranslateTextChunk("", nil, "English", "Russian")
 	require.Error(t, err)
 	require.Empty(t, result)
 	require.Contains(t, err.Error(), "input chunk is empty")
@@ -0,0 +1,66 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+const defaultWidth = 30
+
+// Bar renders a single-line text progress bar that is redrawn in place
+type Bar struct {
+	mu      sync.Mutex
+	w       io.Writer
+	width   int
+	stage   string
+	percent int
+	drawn   bool
+}
+
+// NewBar creates a new progress bar writing to w
+func NewBar(w io.Writer) *Bar {
+	return &Bar{w: w, width: defaultWidth, percent: -1}
+}
+
+// IsTerminal reports whether f is attached to a terminal, where a redrawn bar makes sense
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// Update redraws the bar for the given stage; it only writes when the whole percent
+// value or the stage changes
+func (b *Bar) Update(stage string, percent float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	p := int(min(max(percent, 0), 100))
+	if p == b.percent && stage == b.stage {
+		return
+	}
+	if stage != b.stage && b.drawn {
+		// keep the finished stage on its own line
+		fmt.Fprintln(b.w)
+	}
+	b.stage, b.percent, b.drawn = stage, p, true
+
+	filled := b.width * p / 100
+	fmt.Fprintf(b.w, "\r%-18s [%s%s] %3d%%", stage, strings.Repeat("#", filled), strings.Repeat("-", b.width-filled), p)
+}
+
+// Finish ends the bar line so that following output starts on a new line
+func (b *Bar) Finish() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.drawn {
+		fmt.Fprintln(b.w)
+		b.drawn = false
+	}
+}
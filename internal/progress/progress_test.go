@@ -0,0 +1,50 @@
+package progress
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBar_Update(t *testing.T) {
+	var buf bytes.Buffer
+	bar := NewBar(&buf)
+	bar.width = 10
+
+	bar.Update("extracting audio", 50.4)
+	bar.Update("extracting audio", 50.9) // same whole percent, not redrawn
+	bar.Update("extracting audio", 120)
+	bar.Finish()
+
+	require.Equal(t,
+		"\rextracting audio   [#####-----]  50%"+
+			"\rextracting audio   [##########] 100%\n",
+		buf.String())
+}
+
+func TestBar_NewStageStartsNewLine(t *testing.T) {
+	var buf bytes.Buffer
+	bar := NewBar(&buf)
+	bar.width = 4
+
+	bar.Update("extracting audio", 100)
+	bar.Update("splitting audio", 0)
+	bar.Finish()
+	bar.Finish() // nothing to finish
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 2)
+	require.Contains(t, lines[0], "extracting audio")
+	require.Contains(t, lines[1], "splitting audio")
+	require.Contains(t, lines[1], "[----]   0%")
+}
+
+func TestIsTerminal(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "out")
+	require.NoError(t, err)
+	defer f.Close()
+	require.False(t, IsTerminal(f))
+}
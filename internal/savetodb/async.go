@@ -0,0 +1,169 @@
+package savetodb
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"assemblyai-transcriber/internal/database"
+	"assemblyai-transcriber/internal/interfaces"
+	"assemblyai-transcriber/internal/transcribe"
+)
+
+// errBadSubmission marks a pending transcription whose stored job description is unreadable
+var errBadSubmission = errors.New("invalid submission")
+
+// SubmitResult is the outcome of submitting a video for asynchronous transcription
+type SubmitResult struct {
+	PendingID       int64 // set when transcription jobs were started
+	TranscriptionID int64 // set when a cached transcript was saved right away
+}
+
+// PollResult is the outcome of checking one pending transcription
+type PollResult struct {
+	PendingID       int64
+	FileName        string
+	TranscriptionID int64 // set once the transcript is collected and saved
+	Failed          bool  // the transcription failed and is not polled again
+	Collected       bool  // another process saved the transcript first and removed the job
+	Err             error // set when checking failed or the transcription failed
+}
+
+// SubmitTranscript starts transcription of a video without waiting for it and records
+// the AssemblyAI jobs in the database, so that CollectPending can save the transcript
+// later, possibly from another process.
+func (s *Service) SubmitTranscript(ctx context.Context, opts SaveTranscriptOptions) (*SubmitResult, error) {
+	if opts.VideoPath == "" || opts.DatabasePath == "" {
+		return nil, fmt.Errorf("video path and database path must be provided")
+	}
+
+	cfg, err := s.ConfigLoader()
+	if err != nil {
+		return nil, fmt.Errorf("load config: %w", err)
+	}
+
+	transcriber, err := s.newTranscriber(cfg, opts.DatabasePath)
+	if err != nil {
+		return nil, err
+	}
+	sub, err := transcriber.Submit(ctx, opts.VideoPath)
+	if err != nil {
+		return nil, fmt.Errorf("submit video: %w", err)
+	}
+
+	dbImpl, err := s.openDatabase(opts.DatabasePath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = dbImpl.Close()
+	}()
+
+	fileName := filepath.Base(opts.VideoPath)
+	if sub.Result != nil {
+		id, err := saveResult(dbImpl, fileName, sub.Result)
+		if err != nil {
+			return nil, err
+		}
+		return &SubmitResult{TranscriptionID: id}, nil
+	}
+
+	data, err := json.Marshal(sub)
+	if err != nil {
+		return nil, fmt.Errorf("encode submission: %w", err)
+	}
+	id, err := dbImpl.SavePending(&database.PendingTranscription{
+		FileName:      fileName,
+		TranscriptIDs: strings.Join(sub.TranscriptIDs(), ","),
+		Submission:    string(data),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("save pending transcription: %w", err)
+	}
+	return &SubmitResult{PendingID: id}, nil
+}
+
+// CollectPending checks every pending transcription, saves finished transcripts and marks
+// failed ones. onResult, if not nil, is called for each checked transcription. An error is
+// returned only when the pending transcriptions cannot be read.
+func (s *Service) CollectPending(ctx context.Context, dbPath string, onResult func(PollResult)) ([]PollResult, error) {
+	cfg, err := s.ConfigLoader()
+	if err != nil {
+		return nil, fmt.Errorf("load config: %w", err)
+	}
+
+	dbImpl, err := s.openDatabase(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = dbImpl.Close()
+	}()
+
+	pending, err := dbImpl.ListPending(database.PendingStatusPending)
+	if err != nil {
+		return nil, fmt.Errorf("list pending transcriptions: %w", err)
+	}
+
+	transcriber := s.TranscriberFactory(cfg.AssemblyAIAPIKey)
+	results := make([]PollResult, 0, len(pending))
+	for _, p := range pending {
+		id, err := collect(ctx, dbImpl, transcriber, p)
+		if ctx.Err() != nil {
+			// interrupted, the transcription stays pending
+			break
+		}
+
+		res := PollResult{PendingID: p.ID, FileName: p.FileName, TranscriptionID: id, Err: err}
+		switch {
+		case errors.Is(err, transcribe.ErrTranscriptionFailed) || errors.Is(err, errBadSubmission):
+			// a failed job never completes, keep it for inspection but stop polling it;
+			// other errors such as network failures are retried on the next poll
+			res.Failed = true
+			if err := dbImpl.FailPending(p.ID, res.Err.Error()); err != nil {
+				res.Err = fmt.Errorf("%w; %w", res.Err, err)
+			}
+		case errors.Is(err, sql.ErrNoRows):
+			// another process such as savetodb --poll next to watch collected the job
+			// since it was listed; it is done and no poll returns it again
+			res.Collected, res.Err = true, nil
+		}
+
+		results = append(results, res)
+		if onResult != nil {
+			onResult(res)
+		}
+	}
+	return results, nil
+}
+
+// collect saves the transcript of a pending transcription once it is ready and returns
+// its ID, or zero while it is still in progress. The pending transcription is deleted in
+// the same transaction, so a failed save is collected again on the next poll.
+func collect(
+	ctx context.Context, dbImpl interfaces.Database, transcriber interfaces.Transcriber, p database.PendingTranscription,
+) (int64, error) {
+	var sub transcribe.Submission
+	if err := json.Unmarshal([]byte(p.Submission), &sub); err != nil {
+		return 0, fmt.Errorf("%w: %w", errBadSubmission, err)
+	}
+
+	result, err := transcriber.Collect(ctx, &sub)
+	if err != nil || result == nil {
+		return 0, err
+	}
+
+	b, err := newBundle(p.FileName, result)
+	if err != nil {
+		return 0, err
+	}
+	id, err := dbImpl.CompletePending(p.ID, b)
+	if err != nil {
+		return 0, fmt.Errorf("save to database: %w", err)
+	}
+	return id, nil
+}
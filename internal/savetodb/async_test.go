@@ -0,0 +1,152 @@
+package savetodb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"assemblyai-transcriber/internal/config"
+	"assemblyai-transcriber/internal/database"
+	"assemblyai-transcriber/internal/interfaces"
+	"assemblyai-transcriber/internal/mocks"
+	"assemblyai-transcriber/internal/transcribe"
+)
+
+func newAsyncService(db interfaces.Database, transcriber interfaces.Transcriber) *Service {
+	return NewService(
+		func() (*config.Config, error) { return &config.Config{DatabasePath: "test.db"}, nil },
+		func(string) (interfaces.Database, error) { return db, nil },
+		func(string) interfaces.Transcriber { return transcriber },
+		nil,
+	)
+}
+
+func TestService_SubmitTranscript(t *testing.T) {
+	mockDB := &mocks.DatabaseMock{
+		SetupFunc: func() error { return nil },
+		SavePendingFunc: func(p *database.PendingTranscription) (int64, error) {
+			require.Equal(t, "talk.mp4", p.FileName)
+			require.Equal(t, "t1,t2", p.TranscriptIDs)
+			require.JSONEq(t, `{"parts": [{"transcript_id": "t1", "offset_ms": 0},
+				{"transcript_id": "t2", "offset_ms": 60000}], "profile": "speech"}`, p.Submission)
+			return 7, nil
+		},
+		CloseFunc: func() error { return nil },
+	}
+	mockTranscriber := &mocks.TranscriberMock{
+		SubmitFunc: func(ctx context.Context, videoPath string) (*transcribe.Submission, error) {
+			require.Equal(t, "videos/talk.mp4", videoPath)
+			return &transcribe.Submission{
+				Parts:   []transcribe.SubmittedPart{{TranscriptID: "t1"}, {TranscriptID: "t2", OffsetMS: 60000}},
+				Profile: "speech",
+			}, nil
+		},
+	}
+
+	res, err := newAsyncService(mockDB, mockTranscriber).SubmitTranscript(context.Background(),
+		SaveTranscriptOptions{VideoPath: "videos/talk.mp4", DatabasePath: "test.db"})
+	require.NoError(t, err)
+	require.Equal(t, &SubmitResult{PendingID: 7}, res)
+}
+
+func TestService_SubmitTranscript_Cached(t *testing.T) {
+	mockDB := &mocks.DatabaseMock{
+		SetupFunc: func() error { return nil },
+		SaveBundleFunc: func(tr *database.Bundle) (int64, error) {
+			require.Equal(t, "cached", tr.Text)
+			return 3, nil
+		},
+		CloseFunc: func() error { return nil },
+	}
+	mockTranscriber := &mocks.TranscriberMock{
+		SubmitFunc: func(ctx context.Context, videoPath string) (*transcribe.Submission, error) {
+			return &transcribe.Submission{Result: &transcribe.Result{Text: "cached"}}, nil
+		},
+	}
+
+	res, err := newAsyncService(mockDB, mockTranscriber).SubmitTranscript(context.Background(),
+		SaveTranscriptOptions{VideoPath: "talk.mp4", DatabasePath: "test.db"})
+	require.NoError(t, err)
+	require.Equal(t, &SubmitResult{TranscriptionID: 3}, res)
+	require.Empty(t, mockDB.SavePendingCalls())
+}
+
+func TestService_CollectPending(t *testing.T) {
+	submission := func(id string) string {
+		return fmt.Sprintf(`{"parts": [{"transcript_id": %q}]}`, id)
+	}
+	mockDB := &mocks.DatabaseMock{
+		SetupFunc: func() error { return nil },
+		ListPendingFunc: func(status string) ([]database.PendingTranscription, error) {
+			require.Equal(t, database.PendingStatusPending, status)
+			return []database.PendingTranscription{
+				{ID: 1, FileName: "done.mp4", Submission: submission("done")},
+				{ID: 2, FileName: "queued.mp4", Submission: submission("queued")},
+				{ID: 3, FileName: "broken.mp4", Submission: submission("broken")},
+				{ID: 4, FileName: "offline.mp4", Submission: submission("offline")},
+				{ID: 5, FileName: "garbage.mp4", Submission: "{"},
+				{ID: 6, FileName: "taken.mp4", Submission: submission("taken")},
+			}, nil
+		},
+		CompletePendingFunc: func(pendingID int64, b *database.Bundle) (int64, error) {
+			if pendingID == 6 {
+				// collected by another process since it was listed
+				return 0, fmt.Errorf("error completing pending transcription 6: %w", sql.ErrNoRows)
+			}
+			require.Equal(t, int64(1), pendingID)
+			require.Equal(t, "done.mp4", b.FileName)
+			return 42, nil
+		},
+		FailPendingFunc: func(int64, string) error { return nil },
+		CloseFunc:       func() error { return nil },
+	}
+	mockTranscriber := &mocks.TranscriberMock{
+		CollectFunc: func(ctx context.Context, sub *transcribe.Submission) (*transcribe.Result, error) {
+			switch sub.Parts[0].TranscriptID {
+			case "done", "taken":
+				return &transcribe.Result{Text: "done", Segments: []transcribe.Segment{{End: 100, Text: "done"}}}, nil
+			case "broken":
+				return nil, fmt.Errorf("transcript broken: %w: bad audio", transcribe.ErrTranscriptionFailed)
+			case "offline":
+				return nil, errors.New("connection refused")
+			}
+			return nil, nil
+		},
+	}
+
+	var reported []PollResult
+	results, err := newAsyncService(mockDB, mockTranscriber).CollectPending(context.Background(), "test.db",
+		func(res PollResult) { reported = append(reported, res) })
+	require.NoError(t, err)
+	require.Equal(t, results, reported)
+	require.Len(t, results, 6)
+
+	require.Equal(t, int64(42), results[0].TranscriptionID)
+	require.NoError(t, results[0].Err)
+
+	require.Zero(t, results[1].TranscriptionID)
+	require.NoError(t, results[1].Err)
+	require.False(t, results[1].Failed)
+
+	require.True(t, results[2].Failed)
+	require.ErrorIs(t, results[2].Err, transcribe.ErrTranscriptionFailed)
+
+	require.False(t, results[3].Failed, "network errors are retried")
+	require.EqualError(t, results[3].Err, "connection refused")
+
+	require.True(t, results[4].Failed)
+
+	require.True(t, results[5].Collected)
+	require.False(t, results[5].Failed)
+	require.NoError(t, results[5].Err, "a collected job is not retried")
+
+	failed := mockDB.FailPendingCalls()
+	require.Len(t, failed, 2)
+	require.Equal(t, int64(3), failed[0].ID)
+	require.Equal(t, "transcript broken: transcription failed: bad audio", failed[0].Message)
+	require.Equal(t, int64(5), failed[1].ID)
+}
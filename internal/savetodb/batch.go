@@ -0,0 +1,203 @@
+package savetodb
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultExtensions lists the media file extensions processed in batch mode
+// when no explicit filter is given.
+var DefaultExtensions = []string{".mp4", ".mkv", ".mov", ".avi", ".webm", ".mp3", ".m4a", ".wav"}
+
+// BatchOptions contains parameters for processing a directory of media files.
+type BatchOptions struct {
+	Dir          string
+	Patterns     []string // glob patterns matched against the file name, e.g. "lecture_*.mp4"
+	Extensions   []string // allowed extensions, e.g. ".mp4"; DefaultExtensions when empty
+	Recursive    bool
+	Workers      int
+	DatabasePath string
+}
+
+// FileResult describes the outcome of processing a single file in batch mode.
+type FileResult struct {
+	Path     string
+	ID       int64
+	Err      error
+	Duration time.Duration
+}
+
+// BatchSummary aggregates the results of a batch run.
+type BatchSummary struct {
+	Results   []FileResult
+	Succeeded int
+	Failed    int
+}
+
+// FindMediaFiles returns the files in opts.Dir matching the extension and pattern filters,
+// sorted by path.
+func FindMediaFiles(opts BatchOptions) ([]string, error) {
+	info, err := os.Stat(opts.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("stat directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", opts.Dir)
+	}
+
+	extensions := opts.Extensions
+	if len(extensions) == 0 {
+		extensions = DefaultExtensions
+	}
+
+	var files []string
+	err = filepath.WalkDir(opts.Dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != opts.Dir && !opts.Recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		ok, err := matchFile(d.Name(), extensions, opts.Patterns)
+		if err != nil {
+			return err
+		}
+		if ok {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walk directory: %w", err)
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
+
+// matchFile reports whether the file name passes the extension and glob filters.
+func matchFile(name string, extensions, patterns []string) (bool, error) {
+	ext := strings.ToLower(filepath.Ext(name))
+	extOK := false
+	for _, e := range extensions {
+		if ext == normalizeExtension(e) {
+			extOK = true
+			break
+		}
+	}
+	if !extOK {
+		return false, nil
+	}
+
+	if len(patterns) == 0 {
+		return true, nil
+	}
+	for _, pattern := range patterns {
+		matched, err := filepath.Match(pattern, name)
+		if err != nil {
+			return false, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+		if matched {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// normalizeExtension lowercases the extension and adds the leading dot if missing.
+func normalizeExtension(ext string) string {
+	ext = strings.ToLower(strings.TrimSpace(ext))
+	if ext != "" && !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	return ext
+}
+
+// SaveDirectory transcribes every matching media file in a directory and saves the
+// transcripts to the database. Files are processed by opts.Workers goroutines; onResult,
+// if not nil, is called as soon as each file completes. A failure of one file does not
+// stop the others.
+func (s *Service) SaveDirectory(ctx context.Context, opts BatchOptions, onResult func(FileResult)) (*BatchSummary, error) {
+	if opts.Dir == "" || opts.DatabasePath == "" {
+		return nil, fmt.Errorf("directory and database path must be provided")
+	}
+
+	files, err := FindMediaFiles(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	workers := opts.Workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	jobs := make(chan string)
+	results := make(chan FileResult)
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range jobs {
+				start := time.Now()
+				id, err := s.SaveTranscript(ctx, SaveTranscriptOptions{
+					VideoPath:    path,
+					DatabasePath: opts.DatabasePath,
+				})
+				results <- FileResult{Path: path, ID: id, Err: err, Duration: time.Since(start)}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for _, path := range files {
+			select {
+			case jobs <- path:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	summary := &BatchSummary{Results: make([]FileResult, 0, len(files))}
+	for res := range results {
+		if res.Err != nil {
+			summary.Failed++
+		} else {
+			summary.Succeeded++
+		}
+		summary.Results = append(summary.Results, res)
+		if onResult != nil {
+			onResult(res)
+		}
+	}
+
+	// files never handed to a worker because the context was cancelled count as failures
+	if skipped := len(files) - len(summary.Results); skipped > 0 {
+		summary.Failed += skipped
+	}
+
+	sort.Slice(summary.Results, func(i, j int) bool {
+		return summary.Results[i].Path < summary.Results[j].Path
+	})
+
+	return summary, ctx.Err()
+}
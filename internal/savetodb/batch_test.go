@@ -0,0 +1,141 @@
+package savetodb
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"assemblyai-transcriber/internal/config"
+	"assemblyai-transcriber/internal/database"
+	"assemblyai-transcriber/internal/interfaces"
+	"assemblyai-transcriber/internal/mocks"
+	"assemblyai-transcriber/internal/transcribe"
+)
+
+func createFiles(t *testing.T, dir string, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+		require.NoError(t, os.WriteFile(path, []byte("data"), 0o600))
+	}
+}
+
+func TestFindMediaFiles(t *testing.T) {
+	dir := t.TempDir()
+	createFiles(t, dir, "a.mp4", "b.MKV", "notes.txt", "lecture_1.mp4", "sub/c.mp4")
+
+	tests := []struct {
+		name string
+		opts BatchOptions
+		want []string
+	}{
+		{
+			name: "default extensions",
+			opts: BatchOptions{Dir: dir},
+			want: []string{"a.mp4", "b.MKV", "lecture_1.mp4"},
+		},
+		{
+			name: "recursive",
+			opts: BatchOptions{Dir: dir, Recursive: true},
+			want: []string{"a.mp4", "b.MKV", "lecture_1.mp4", "sub/c.mp4"},
+		},
+		{
+			name: "extension filter without dot",
+			opts: BatchOptions{Dir: dir, Extensions: []string{"mkv"}},
+			want: []string{"b.MKV"},
+		},
+		{
+			name: "glob pattern",
+			opts: BatchOptions{Dir: dir, Patterns: []string{"lecture_*"}},
+			want: []string{"lecture_1.mp4"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, err := FindMediaFiles(tt.opts)
+			require.NoError(t, err)
+			want := make([]string, 0, len(tt.want))
+			for _, name := range tt.want {
+				want = append(want, filepath.Join(dir, filepath.FromSlash(name)))
+			}
+			require.Equal(t, want, files)
+		})
+	}
+}
+
+func TestFindMediaFiles_InvalidPattern(t *testing.T) {
+	dir := t.TempDir()
+	createFiles(t, dir, "a.mp4")
+
+	_, err := FindMediaFiles(BatchOptions{Dir: dir, Patterns: []string{"["}})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid pattern")
+}
+
+func TestService_SaveDirectory(t *testing.T) {
+	dir := t.TempDir()
+	createFiles(t, dir, "ok1.mp4", "bad.mp4", "ok2.mp4")
+
+	var (
+		mu    sync.Mutex
+		saved []string
+	)
+	mockDB := &mocks.DatabaseMock{
+		SetupFunc: func() error { return nil },
+		SaveBundleFunc: func(tr *database.Bundle) (int64, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			saved = append(saved, tr.FileName)
+			return int64(len(saved)), nil
+		},
+		CloseFunc: func() error { return nil },
+	}
+	mockTranscriber := &mocks.TranscriberMock{
+		TranscribeVideoFunc: func(ctx context.Context, videoPath string) (*transcribe.Result, error) {
+			if filepath.Base(videoPath) == "bad.mp4" {
+				return nil, fmt.Errorf("ffmpeg failed")
+			}
+			return &transcribe.Result{Text: "transcript of " + filepath.Base(videoPath)}, nil
+		},
+	}
+	service := NewService(
+		func() (*config.Config, error) { return &config.Config{}, nil },
+		func(path string) (interfaces.Database, error) { return mockDB, nil },
+		func(apiKey string) interfaces.Transcriber { return mockTranscriber },
+		nil,
+	)
+
+	var reported []string
+	summary, err := service.SaveDirectory(context.Background(), BatchOptions{
+		Dir:          dir,
+		Workers:      2,
+		DatabasePath: "test.db",
+	}, func(res FileResult) {
+		mu.Lock()
+		defer mu.Unlock()
+		reported = append(reported, filepath.Base(res.Path))
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, summary.Succeeded)
+	require.Equal(t, 1, summary.Failed)
+	require.Len(t, summary.Results, 3)
+	require.ElementsMatch(t, []string{"bad.mp4", "ok1.mp4", "ok2.mp4"}, reported)
+	require.ElementsMatch(t, []string{"ok1.mp4", "ok2.mp4"}, saved)
+
+	require.Equal(t, "bad.mp4", filepath.Base(summary.Results[0].Path))
+	require.Error(t, summary.Results[0].Err)
+	require.Contains(t, summary.Results[0].Err.Error(), "ffmpeg failed")
+}
+
+func TestService_SaveDirectory_MissingOptions(t *testing.T) {
+	service := NewService(nil, nil, nil, nil)
+	_, err := service.SaveDirectory(context.Background(), BatchOptions{Dir: "videos"}, nil)
+	require.Error(t, err)
+}
@@ -2,11 +2,15 @@ package savetodb
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"path/filepath"
 
 	"assemblyai-transcriber/internal/config"
+	"assemblyai-transcriber/internal/database"
 	"assemblyai-transcriber/internal/interfaces"
+	"assemblyai-transcriber/internal/language"
+	"assemblyai-transcriber/internal/transcribe"
 )
 
 // ConfigLoader abstracts config loading.
@@ -45,7 +49,7 @@ func NewService(
 type SaveTranscriptOptions struct {
 	TranscriptPath string
 	VideoPath      string
-	DatabasePath   string
+	DatabasePath   string // SQLite file or postgres:// URL
 }
 
 // SaveTranscript saves a transcript (from file or video) to the database.
@@ -59,48 +63,147 @@ func (s *Service) SaveTranscript(ctx context.Context, opts SaveTranscriptOptions
 		return 0, fmt.Errorf("load config: %w", err)
 	}
 
+	// the loaded config may be shared between concurrent calls, so it is not modified
+	dbPath := cfg.DatabaseDSN()
 	if opts.DatabasePath != "" {
-		cfg.DatabasePath = opts.DatabasePath
+		dbPath = opts.DatabasePath
 	}
 
-	var transcriptText string
+	var (
+		result   *transcribe.Result
+		fileName string
+	)
 
 	if opts.VideoPath != "" {
-		transcriber := s.TranscriberFactory(cfg.AssemblyAIAPIKey)
-		transcriptText, err = transcriber.TranscribeVideo(ctx, opts.VideoPath)
+		transcriber, err := s.newTranscriber(cfg, dbPath)
+		if err != nil {
+			return 0, err
+		}
+		result, err = transcriber.TranscribeVideo(ctx, opts.VideoPath)
 		if err != nil {
 			return 0, fmt.Errorf("transcribe video: %w", err)
 		}
+		fileName = filepath.Base(opts.VideoPath)
 	} else {
 		transcriptTextBytes, err := s.FileReader(filepath.Clean(opts.TranscriptPath))
 		if err != nil {
 			return 0, fmt.Errorf("read transcript file: %w", err)
 		}
-		transcriptText = string(transcriptTextBytes)
+		result = &transcribe.Result{Text: string(transcriptTextBytes)}
+		fileName = filepath.Base(opts.TranscriptPath)
 	}
 
-	dbImpl, err := s.DatabaseFactory(cfg.DatabasePath)
+	dbImpl, err := s.openDatabase(dbPath)
 	if err != nil {
-		return 0, fmt.Errorf("init database: %w", err)
+		return 0, err
 	}
 	defer func() {
 		_ = dbImpl.Close()
 	}()
 
+	return saveResult(dbImpl, fileName, result)
+}
+
+// newTranscriber creates a transcriber; with glossary boost configured, the stored
+// glossary terms are added to its custom vocabulary
+func (s *Service) newTranscriber(cfg *config.Config, dbPath string) (interfaces.Transcriber, error) {
+	transcriber := s.TranscriberFactory(cfg.AssemblyAIAPIKey)
+	if !cfg.TranscribeGlossaryBoost {
+		return transcriber, nil
+	}
+
+	dbImpl, err := s.openDatabase(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = dbImpl.Close()
+	}()
+
+	terms, err := dbImpl.GetAllTerms()
+	if err != nil {
+		return nil, fmt.Errorf("load glossary: %w", err)
+	}
+	words := make([]string, 0, len(terms))
+	for _, term := range terms {
+		words = append(words, term["term"])
+	}
+	transcriber.AddWordBoost(words...)
+	return transcriber, nil
+}
+
+// openDatabase creates a database connection and prepares its schema
+func (s *Service) openDatabase(dbPath string) (interfaces.Database, error) {
+	dbImpl, err := s.DatabaseFactory(dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("init database: %w", err)
+	}
+
 	if err := dbImpl.Setup(); err != nil {
-		return 0, fmt.Errorf("setup database: %w", err)
+		_ = dbImpl.Close()
+		return nil, fmt.Errorf("setup database: %w", err)
 	}
+	return dbImpl, nil
+}
 
-	var fileName string
-	if opts.VideoPath != "" {
-		fileName = filepath.Base(opts.VideoPath)
-	} else {
-		fileName = filepath.Base(opts.TranscriptPath)
+// saveResult stores a transcript with its segments, words and media metadata in one
+// transaction and returns its ID
+func saveResult(dbImpl interfaces.Database, fileName string, result *transcribe.Result) (int64, error) {
+	b, err := newBundle(fileName, result)
+	if err != nil {
+		return 0, err
 	}
-	id, err := dbImpl.SaveTranscription(fileName, transcriptText)
+	id, err := dbImpl.SaveBundle(b)
 	if err != nil {
 		return 0, fmt.Errorf("save to database: %w", err)
 	}
-
 	return id, nil
 }
+
+// newBundle converts a transcription result to the records stored for it
+func newBundle(fileName string, result *transcribe.Result) (*database.Bundle, error) {
+	var options string
+	if result.Options != nil {
+		data, err := json.Marshal(result.Options)
+		if err != nil {
+			return nil, fmt.Errorf("encode transcription options: %w", err)
+		}
+		options = string(data)
+	}
+
+	// transcripts read from files and older AssemblyAI results carry no language
+	lang := result.Language
+	if lang == "" {
+		lang = language.Detect(result.Text)
+	}
+
+	b := &database.Bundle{Transcription: database.Transcription{
+		FileName:     fileName,
+		Text:         result.Text,
+		AudioProfile: result.Profile,
+		Options:      options,
+		Language:     lang,
+	}}
+	for _, seg := range result.Segments {
+		b.Segments = append(b.Segments, database.Segment{
+			Start: seg.Start, End: seg.End, Text: seg.Text, Confidence: seg.Confidence,
+		})
+	}
+	for _, w := range result.Words {
+		b.Words = append(b.Words, database.Word{Start: w.Start, End: w.End, Text: w.Text, Confidence: w.Confidence})
+	}
+	if media := result.Media; media != nil {
+		b.Media = &database.Media{
+			DurationMS:     media.Duration.Milliseconds(),
+			Container:      media.Container,
+			VideoCodec:     media.VideoCodec,
+			AudioCodec:     media.AudioCodec,
+			BitRate:        media.BitRate,
+			AudioChannels:  media.AudioChannels,
+			FileSize:       media.Size,
+			SHA256:         media.SHA256,
+			MediaCreatedAt: media.CreatedAt,
+		}
+	}
+	return b, nil
+}
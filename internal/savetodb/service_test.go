@@ -3,20 +3,25 @@ package savetodb
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"assemblyai-transcriber/internal/config"
+	"assemblyai-transcriber/internal/database"
 	"assemblyai-transcriber/internal/interfaces"
 	"assemblyai-transcriber/internal/mocks"
+	"assemblyai-transcriber/internal/transcribe"
 )
 
 func TestService_SaveTranscript_File_Success(t *testing.T) {
 	mockDB := &mocks.DatabaseMock{
 		SetupFunc: func() error { return nil },
-		SaveTranscriptionFunc: func(fileName, text string) (int64, error) {
-			require.Equal(t, "transcript.txt", fileName)
-			require.Equal(t, "test transcript", text)
+		SaveBundleFunc: func(tr *database.Bundle) (int64, error) {
+			require.Equal(t, "transcript.txt", tr.FileName)
+			require.Equal(t, "Das ist der Test, und er ist nicht lang.", tr.Text)
+			require.Empty(t, tr.AudioProfile)
+			require.Equal(t, "de", tr.Language, "detected from the text")
 			return 42, nil
 		},
 		CloseFunc: func() error { return nil },
@@ -32,7 +37,7 @@ func TestService_SaveTranscript_File_Success(t *testing.T) {
 		nil, // TranscriberFactory not used
 		func(path string) ([]byte, error) {
 			require.Equal(t, "transcript.txt", path)
-			return []byte("test transcript"), nil
+			return []byte("Das ist der Test, und er ist nicht lang."), nil
 		},
 	)
 
@@ -46,23 +51,49 @@ func TestService_SaveTranscript_File_Success(t *testing.T) {
 
 func TestService_SaveTranscript_Video_Success(t *testing.T) {
 	mockTranscriber := &mocks.TranscriberMock{
-		TranscribeVideoFunc: func(ctx context.Context, videoPath string) (string, error) {
+		TranscribeVideoFunc: func(ctx context.Context, videoPath string) (*transcribe.Result, error) {
 			require.Equal(t, "video.mp4", videoPath)
-			return "video transcript", nil
+			return &transcribe.Result{
+				Text:     "video transcript",
+				Segments: []transcribe.Segment{{Start: 0, End: 1200, Text: "video transcript", Confidence: 0.8}},
+				Words: []transcribe.Word{
+					{Start: 0, End: 500, Text: "video", Confidence: 0.9},
+					{Start: 600, End: 1200, Text: "transcript", Confidence: 0.7},
+				},
+				Profile:  "speech",
+				Options:  &transcribe.Options{LanguageCode: "de", Punctuate: true},
+				Language: "de",
+				Media:    &transcribe.Media{Duration: 1500 * time.Millisecond, Container: "mp4", SHA256: "abc"},
+			}, nil
+		},
+		AddWordBoostFunc: func(words ...string) {
+			require.Equal(t, []string{"Kubernetes", "gRPC"}, words)
 		},
 	}
 	mockDB := &mocks.DatabaseMock{
 		SetupFunc: func() error { return nil },
-		SaveTranscriptionFunc: func(fileName, text string) (int64, error) {
-			require.Equal(t, "video.mp4", fileName)
-			require.Equal(t, "video transcript", text)
+		SaveBundleFunc: func(tr *database.Bundle) (int64, error) {
+			require.Equal(t, "video.mp4", tr.FileName)
+			require.Equal(t, "video transcript", tr.Text)
+			require.Equal(t, "speech", tr.AudioProfile)
+			require.JSONEq(t, `{"language_code": "de", "punctuate": true, "format_text": false}`, tr.Options)
+			require.Equal(t, "de", tr.Language)
+			require.Equal(t, []database.Segment{{Start: 0, End: 1200, Text: "video transcript", Confidence: 0.8}}, tr.Segments)
+			require.Equal(t, []database.Word{
+				{Start: 0, End: 500, Text: "video", Confidence: 0.9},
+				{Start: 600, End: 1200, Text: "transcript", Confidence: 0.7},
+			}, tr.Words)
+			require.Equal(t, &database.Media{DurationMS: 1500, Container: "mp4", SHA256: "abc"}, tr.Media)
 			return 99, nil
 		},
+		GetAllTermsFunc: func() ([]map[string]string, error) {
+			return []map[string]string{{"term": "Kubernetes"}, {"term": "gRPC"}}, nil
+		},
 		CloseFunc: func() error { return nil },
 	}
 	service := NewService(
 		func() (*config.Config, error) {
-			return &config.Config{DatabasePath: "test.db", AssemblyAIAPIKey: "key"}, nil
+			return &config.Config{DatabasePath: "test.db", AssemblyAIAPIKey: "key", TranscribeGlossaryBoost: true}, nil
 		},
 		func(path string) (interfaces.Database, error) {
 			require.Equal(t, "test.db", path)
@@ -81,4 +112,6 @@ func TestService_SaveTranscript_Video_Success(t *testing.T) {
 	})
 	require.NoError(t, err)
 	require.Equal(t, int64(99), id)
+	require.Len(t, mockDB.SaveBundleCalls(), 1)
+	require.Len(t, mockTranscriber.AddWordBoostCalls(), 1)
 }
@@ -0,0 +1,102 @@
+package server
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// JobStatus describes the state of a background job
+type JobStatus string
+
+// Job states
+const (
+	JobQueued    JobStatus = "queued"
+	JobRunning   JobStatus = "running"
+	JobCompleted JobStatus = "completed"
+	JobFailed    JobStatus = "failed"
+)
+
+// Job types
+const (
+	JobTranscribe = "transcribe"
+	JobTranslate  = "translate"
+	JobExport     = "export"
+)
+
+// Job is a long-running operation started through the API
+type Job struct {
+	ID              int64      `json:"id"`
+	Type            string     `json:"type"`
+	Status          JobStatus  `json:"status"`
+	Source          string     `json:"source,omitempty"`
+	TranscriptionID int64      `json:"transcription_id,omitempty"`
+	Files           []string   `json:"files,omitempty"`
+	Error           string     `json:"error,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	FinishedAt      *time.Time `json:"finished_at,omitempty"`
+}
+
+// jobStore keeps jobs in memory; job history does not survive a restart
+type jobStore struct {
+	mu     sync.Mutex
+	nextID int64
+	jobs   map[int64]*Job
+}
+
+func newJobStore() *jobStore {
+	return &jobStore{jobs: map[int64]*Job{}}
+}
+
+// create registers a new queued job and returns a copy of it
+func (s *jobStore) create(jobType, source string, transcriptionID int64) Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextID++
+	job := &Job{
+		ID:              s.nextID,
+		Type:            jobType,
+		Status:          JobQueued,
+		Source:          source,
+		TranscriptionID: transcriptionID,
+		CreatedAt:       time.Now().UTC(),
+	}
+	s.jobs[job.ID] = job
+	return *job
+}
+
+// update applies fn to the job under the lock
+func (s *jobStore) update(id int64, fn func(job *Job)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if job, ok := s.jobs[id]; ok {
+		fn(job)
+	}
+}
+
+// get returns a copy of the job
+func (s *jobStore) get(id int64) (Job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+	return *job, true
+}
+
+// list returns copies of all jobs ordered by ID
+func (s *jobStore) list() []Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobs := make([]Job, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		jobs = append(jobs, *job)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].ID < jobs[j].ID })
+	return jobs
+}
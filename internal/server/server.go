@@ -0,0 +1,450 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"assemblyai-transcriber/internal/database"
+	"assemblyai-transcriber/internal/export"
+)
+
+const defaultMaxUploadMB = 2048
+
+// Store defines database operations used by the API
+type Store interface {
+	export.Store
+	GetTranscriptionRecord(id int64) (*database.Transcription, error)
+	GetSegments(transcriptionID int64) ([]database.Segment, error)
+	GetTranslation(transcriptionID int64) (string, error)
+	GetAllTerms() ([]map[string]string, error)
+	SaveTerm(term, description string) error
+	DeleteTerm(term string) error
+}
+
+// TranscribeFunc transcribes a media file and returns the ID of the saved transcription
+type TranscribeFunc func(ctx context.Context, path string) (int64, error)
+
+// TranslateFunc translates a saved transcription and stores the result
+type TranslateFunc func(ctx context.Context, transcriptionID int64) error
+
+// Options configures the server
+type Options struct {
+	UploadDir   string // uploaded files are kept here while they are transcribed
+	MediaDir    string // submitted paths must be inside this directory; empty disables path submission
+	ExportDir   string // output directory for export jobs
+	MaxUploadMB int
+}
+
+// Server exposes transcription, translation and export over a JSON REST API
+type Server struct {
+	store      Store
+	transcribe TranscribeFunc
+	translate  TranslateFunc
+	opts       Options
+	jobs       *jobStore
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// jobOutput holds what a finished job produced
+type jobOutput struct {
+	transcriptionID int64
+	files           []string
+}
+
+// New creates a new API server
+func New(store Store, transcribe TranscribeFunc, translate TranslateFunc, opts Options) *Server {
+	if opts.UploadDir == "" {
+		opts.UploadDir = os.TempDir()
+	}
+	if opts.ExportDir == "" {
+		opts.ExportDir = "./translations"
+	}
+	if opts.MaxUploadMB <= 0 {
+		opts.MaxUploadMB = defaultMaxUploadMB
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Server{
+		store:      store,
+		transcribe: transcribe,
+		translate:  translate,
+		opts:       opts,
+		jobs:       newJobStore(),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// Handler returns the HTTP handler with all API routes
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/jobs/transcribe", s.handleTranscribe)
+	mux.HandleFunc("POST /api/jobs/translate", s.handleTranslate)
+	mux.HandleFunc("POST /api/jobs/export", s.handleExport)
+	mux.HandleFunc("GET /api/jobs", s.handleListJobs)
+	mux.HandleFunc("GET /api/jobs/{id}", s.handleGetJob)
+	mux.HandleFunc("GET /api/transcriptions/{id}", s.handleGetTranscription)
+	mux.HandleFunc("GET /api/transcriptions/{id}/segments", s.handleGetSegments)
+	mux.HandleFunc("GET /api/transcriptions/{id}/translation", s.handleGetTranslation)
+	mux.HandleFunc("GET /api/transcriptions/{id}/media", s.handleGetMedia)
+	mux.HandleFunc("GET /api/terms", s.handleListTerms)
+	mux.HandleFunc("POST /api/terms", s.handleSaveTerm)
+	mux.HandleFunc("DELETE /api/terms/{term}", s.handleDeleteTerm)
+	return mux
+}
+
+// Close cancels running jobs and waits for them to finish
+func (s *Server) Close() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+// handleTranscribe starts a transcription job for an uploaded file (multipart field "file")
+// or for a file inside the media directory (JSON body {"path": "..."})
+func (s *Server) handleTranscribe(w http.ResponseWriter, r *http.Request) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		s.handleUpload(w, r)
+		return
+	}
+
+	var req struct {
+		Path string `json:"path"`
+	}
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	path, err := s.resolveMediaPath(req.Path)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	job := s.jobs.create(JobTranscribe, filepath.Base(path), 0)
+	s.startJob(job.ID, func(ctx context.Context) (jobOutput, error) {
+		id, err := s.transcribe(ctx, path)
+		return jobOutput{transcriptionID: id}, err
+	}, nil)
+	writeJSON(w, http.StatusAccepted, job)
+}
+
+// handleUpload stores an uploaded media file and starts a transcription job for it
+func (s *Server) handleUpload(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, int64(s.opts.MaxUploadMB)*1024*1024)
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("read uploaded file: %v", err))
+		return
+	}
+	defer file.Close()
+
+	if err := os.MkdirAll(s.opts.UploadDir, 0o750); err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("create upload directory: %v", err))
+		return
+	}
+	// keep the original file name, it is stored with the transcription
+	dir, err := os.MkdirTemp(s.opts.UploadDir, "upload-*")
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("create upload directory: %v", err))
+		return
+	}
+	name := filepath.Base(filepath.Clean("/" + header.Filename))
+	if name == "/" || name == "." {
+		name = "upload"
+	}
+	path := filepath.Join(dir, name)
+
+	if err := saveUpload(file, path); err != nil {
+		_ = os.RemoveAll(dir)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	job := s.jobs.create(JobTranscribe, name, 0)
+	s.startJob(job.ID, func(ctx context.Context) (jobOutput, error) {
+		id, err := s.transcribe(ctx, path)
+		return jobOutput{transcriptionID: id}, err
+	}, func() { _ = os.RemoveAll(dir) })
+	writeJSON(w, http.StatusAccepted, job)
+}
+
+// handleTranslate starts a translation job for a stored transcription
+func (s *Server) handleTranslate(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		TranscriptionID int64 `json:"transcription_id"`
+	}
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if _, err := s.store.GetTranscriptionRecord(req.TranscriptionID); err != nil {
+		writeStoreError(w, err)
+		return
+	}
+
+	id := req.TranscriptionID
+	job := s.jobs.create(JobTranslate, "", id)
+	s.startJob(job.ID, func(ctx context.Context) (jobOutput, error) {
+		return jobOutput{transcriptionID: id}, s.translate(ctx, id)
+	}, nil)
+	writeJSON(w, http.StatusAccepted, job)
+}
+
+// handleExport starts a job that writes new and changed translations to the export
+// directory; the job lists the written files. Export jobs started while another one is
+// running wait for it, so each sees the files the previous one recorded.
+func (s *Server) handleExport(w http.ResponseWriter, _ *http.Request) {
+	job := s.jobs.create(JobExport, "", 0)
+	s.startJob(job.ID, func(_ context.Context) (jobOutput, error) {
+		result, err := export.Markdown(s.store, s.opts.ExportDir, export.Options{})
+		return jobOutput{files: result.Written()}, err
+	}, nil)
+	writeJSON(w, http.StatusAccepted, job)
+}
+
+func (s *Server) handleListJobs(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, s.jobs.list())
+}
+
+func (s *Server) handleGetJob(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	job, found := s.jobs.get(id)
+	if !found {
+		writeError(w, http.StatusNotFound, "job not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, job)
+}
+
+func (s *Server) handleGetTranscription(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	record, err := s.store.GetTranscriptionRecord(id)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, record)
+}
+
+func (s *Server) handleGetSegments(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	if _, err := s.store.GetTranscriptionRecord(id); err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	segments, err := s.store.GetSegments(id)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	if segments == nil {
+		segments = []database.Segment{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"transcription_id": id, "segments": segments})
+}
+
+func (s *Server) handleGetMedia(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	media, err := s.store.GetMedia(id)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, media)
+}
+
+func (s *Server) handleGetTranslation(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	text, err := s.store.GetTranslation(id)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"transcription_id": id, "text": text})
+}
+
+func (s *Server) handleListTerms(w http.ResponseWriter, _ *http.Request) {
+	terms, err := s.store.GetAllTerms()
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, terms)
+}
+
+func (s *Server) handleSaveTerm(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Term        string `json:"term"`
+		Description string `json:"description"`
+	}
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	req.Term = strings.TrimSpace(req.Term)
+	if req.Term == "" {
+		writeError(w, http.StatusBadRequest, "term must not be empty")
+		return
+	}
+	if err := s.store.SaveTerm(req.Term, req.Description); err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]string{"term": req.Term, "description": req.Description})
+}
+
+func (s *Server) handleDeleteTerm(w http.ResponseWriter, r *http.Request) {
+	if err := s.store.DeleteTerm(r.PathValue("term")); err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// startJob runs fn in the background and records its outcome; cleanup, if not nil,
+// runs after the job finishes
+func (s *Server) startJob(id int64, fn func(ctx context.Context) (jobOutput, error), cleanup func()) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		if cleanup != nil {
+			defer cleanup()
+		}
+
+		s.jobs.update(id, func(job *Job) { job.Status = JobRunning })
+		out, err := fn(s.ctx)
+		finished := time.Now().UTC()
+		s.jobs.update(id, func(job *Job) {
+			job.FinishedAt = &finished
+			if out.transcriptionID != 0 {
+				job.TranscriptionID = out.transcriptionID
+			}
+			job.Files = out.files
+			if err != nil {
+				job.Status = JobFailed
+				job.Error = err.Error()
+				return
+			}
+			job.Status = JobCompleted
+		})
+	}()
+}
+
+// resolveMediaPath checks that a submitted path points to a file inside the media directory
+func (s *Server) resolveMediaPath(path string) (string, error) {
+	if s.opts.MediaDir == "" {
+		return "", fmt.Errorf("path submission is disabled, upload the file instead")
+	}
+	if path == "" {
+		return "", fmt.Errorf("path must not be empty")
+	}
+
+	base, err := filepath.Abs(s.opts.MediaDir)
+	if err != nil {
+		return "", fmt.Errorf("resolve media directory: %w", err)
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(base, path)
+	}
+	path = filepath.Clean(path)
+
+	rel, err := filepath.Rel(base, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path must be inside the media directory")
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("media file not found: %s", rel)
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("path is a directory: %s", rel)
+	}
+	return path, nil
+}
+
+// saveUpload copies an uploaded file to path
+func saveUpload(src io.Reader, path string) error {
+	dst, err := os.OpenFile(filepath.Clean(path), os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0o600)
+	if err != nil {
+		return fmt.Errorf("create upload file: %w", err)
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		_ = dst.Close()
+		return fmt.Errorf("write upload file: %w", err)
+	}
+	if err := dst.Close(); err != nil {
+		return fmt.Errorf("close upload file: %w", err)
+	}
+	return nil
+}
+
+// pathID parses the {id} path parameter, writing a 400 response if it is invalid
+func pathID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id <= 0 {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return 0, false
+	}
+	return id, true
+}
+
+// decodeJSON decodes the request body, rejecting unknown fields
+func decodeJSON(r *http.Request, v any) error {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("invalid JSON body: %w", err)
+	}
+	return nil
+}
+
+// writeStoreError maps database errors to HTTP responses
+func writeStoreError(w http.ResponseWriter, err error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	writeError(w, http.StatusInternalServerError, err.Error())
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,448 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"assemblyai-transcriber/internal/config"
+	"assemblyai-transcriber/internal/database"
+	"assemblyai-transcriber/internal/interfaces"
+	"assemblyai-transcriber/internal/mocks"
+	"assemblyai-transcriber/internal/openrouter"
+	"assemblyai-transcriber/internal/savetodb"
+	"assemblyai-transcriber/internal/terms"
+	"assemblyai-transcriber/internal/transcribe"
+	"assemblyai-transcriber/internal/translation"
+)
+
+// fakeStore is an in-memory implementation of the database operations used by the
+// server and the services behind it
+type fakeStore struct {
+	mu             sync.Mutex
+	transcriptions map[int64]*database.Transcription
+	segments       map[int64][]database.Segment
+	media          map[int64]*database.Media
+	translations   map[int64]string
+	terms          map[string]string
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{
+		transcriptions: map[int64]*database.Transcription{},
+		segments:       map[int64][]database.Segment{},
+		media:          map[int64]*database.Media{},
+		translations:   map[int64]string{},
+		terms:          map[string]string{},
+	}
+}
+
+func (f *fakeStore) Setup() error { return nil }
+func (f *fakeStore) Close() error { return nil }
+
+func (f *fakeStore) InsertTranscription(t *database.Transcription) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	id := int64(len(f.transcriptions) + 1)
+	record := *t
+	record.ID, record.CreatedAt = id, time.Now()
+	f.transcriptions[id] = &record
+	return id, nil
+}
+
+func (f *fakeStore) SaveSegments(transcriptionID int64, segments []database.Segment) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i := range segments {
+		segments[i].Index = i
+	}
+	f.segments[transcriptionID] = segments
+	return nil
+}
+
+func (f *fakeStore) SaveWords(int64, []database.Word) error { return nil }
+
+func (f *fakeStore) SaveMedia(m *database.Media) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.media[m.TranscriptionID] = m
+	return nil
+}
+
+func (f *fakeStore) SaveBundle(b *database.Bundle) (int64, error) {
+	id, _ := f.InsertTranscription(&b.Transcription)
+	_ = f.SaveSegments(id, b.Segments)
+	if b.Media != nil {
+		media := *b.Media
+		media.TranscriptionID = id
+		_ = f.SaveMedia(&media)
+	}
+	return id, nil
+}
+
+func (f *fakeStore) GetMedia(transcriptionID int64) (*database.Media, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	m, ok := f.media[transcriptionID]
+	if !ok {
+		return nil, fmt.Errorf("error retrieving media: %w", sql.ErrNoRows)
+	}
+	return m, nil
+}
+
+// the server runs transcriptions synchronously, pending transcriptions are never created
+func (f *fakeStore) SavePending(*database.PendingTranscription) (int64, error) {
+	return 0, fmt.Errorf("not supported")
+}
+func (f *fakeStore) ListPending(string) ([]database.PendingTranscription, error) { return nil, nil }
+func (f *fakeStore) FailPending(int64, string) error                             { return nil }
+func (f *fakeStore) DeletePending(int64) error                                   { return nil }
+func (f *fakeStore) CompletePending(int64, *database.Bundle) (int64, error) {
+	return 0, fmt.Errorf("not supported")
+}
+
+func (f *fakeStore) GetTranscriptionRecord(id int64) (*database.Transcription, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	t, ok := f.transcriptions[id]
+	if !ok {
+		return nil, fmt.Errorf("error retrieving transcription: %w", sql.ErrNoRows)
+	}
+	return t, nil
+}
+
+func (f *fakeStore) GetTranscription(id int64) (string, error) {
+	t, err := f.GetTranscriptionRecord(id)
+	if err != nil {
+		return "", err
+	}
+	return t.Text, nil
+}
+
+func (f *fakeStore) GetSegments(transcriptionID int64) ([]database.Segment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.segments[transcriptionID], nil
+}
+
+func (f *fakeStore) GetTranslation(transcriptionID int64) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	text, ok := f.translations[transcriptionID]
+	if !ok {
+		return "", fmt.Errorf("error retrieving translation: %w", sql.ErrNoRows)
+	}
+	return text, nil
+}
+
+func (f *fakeStore) InsertTranslation(t *database.Translation) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.translations[t.TranscriptionID] = t.TranslatedText
+	return t.TranscriptionID, nil
+}
+
+func (f *fakeStore) ListTranslations() ([]database.Translation, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	result := make([]database.Translation, 0, len(f.translations))
+	for id, text := range f.translations {
+		result = append(result, database.Translation{ID: id, TranscriptionID: id, TranslatedText: text})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result, nil
+}
+
+func (f *fakeStore) GetTags(int64) ([]string, error)        { return nil, nil }
+func (f *fakeStore) GetCollections(int64) ([]string, error) { return nil, nil }
+
+func (f *fakeStore) GetAllTerms() ([]map[string]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	result := make([]map[string]string, 0, len(f.terms))
+	for term, desc := range f.terms {
+		result = append(result, map[string]string{"term": term, "description": desc})
+	}
+	return result, nil
+}
+
+func (f *fakeStore) SaveTerm(term, description string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.terms[term] = description
+	return nil
+}
+
+func (f *fakeStore) DeleteTerm(term string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.terms[term]; !ok {
+		return fmt.Errorf("error deleting term: %w", sql.ErrNoRows)
+	}
+	delete(f.terms, term)
+	return nil
+}
+
+// stubLLM replaces OpenRouter in translation tests
+type stubLLM struct{}
+
+func (stubLLM) AnalyzeTerms(string) (*openrouter.TermAnalysis, error) {
+	analysis := &openrouter.TermAnalysis{}
+	analysis.Terms = append(analysis.Terms, struct {
+		Term        string   `json:"term"`
+		Description string   `json:"description"`
+		Context     []string `json:"context,omitempty"`
+	}{Term: "API", Description: "interface"})
+	return analysis, nil
+}
+
+func (stubLLM) TranslateText(text string, _ []string, _, _ string) (string, error) {
+	return "translated: " + text, nil
+}
+
+type testEnv struct {
+	srv      *httptest.Server
+	store    *fakeStore
+	mediaDir string
+	exportTo string
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	store := newFakeStore()
+	transcriber := &mocks.TranscriberMock{
+		TranscribeVideoFunc: func(ctx context.Context, videoPath string) (*transcribe.Result, error) {
+			data, err := os.ReadFile(videoPath)
+			if err != nil {
+				return nil, err
+			}
+			return &transcribe.Result{
+				Text:     "transcript: " + string(data),
+				Segments: []transcribe.Segment{{Start: 0, End: 1500, Text: "transcript: " + string(data)}},
+				Media:    &transcribe.Media{Duration: 1500 * time.Millisecond, Container: "mp4", Size: int64(len(data))},
+			}, nil
+		},
+	}
+	saveService := savetodb.NewService(
+		func() (*config.Config, error) { return &config.Config{DatabasePath: "test.db"}, nil },
+		func(string) (interfaces.Database, error) { return store, nil },
+		func(string) interfaces.Transcriber { return transcriber },
+		os.ReadFile,
+	)
+
+	mediaDir := t.TempDir()
+	exportDir := filepath.Join(t.TempDir(), "export")
+	api := New(store,
+		func(ctx context.Context, path string) (int64, error) {
+			return saveService.SaveTranscript(ctx, savetodb.SaveTranscriptOptions{VideoPath: path, DatabasePath: "test.db"})
+		},
+		func(_ context.Context, id int64) error {
+			service := translation.New(store, stubLLM{})
+			service.SetTermPolicy(terms.PolicyAcceptAll)
+			return service.ProcessTranscription(id)
+		},
+		Options{UploadDir: t.TempDir(), MediaDir: mediaDir, ExportDir: exportDir},
+	)
+	srv := httptest.NewServer(api.Handler())
+	t.Cleanup(func() {
+		srv.Close()
+		api.Close()
+	})
+	return &testEnv{srv: srv, store: store, mediaDir: mediaDir, exportTo: exportDir}
+}
+
+func (e *testEnv) do(t *testing.T, method, path string, body any) (int, []byte) {
+	t.Helper()
+	var reader *bytes.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		reader = bytes.NewReader(data)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+	req, err := http.NewRequest(method, e.srv.URL+path, reader)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	return e.send(t, req)
+}
+
+func (e *testEnv) send(t *testing.T, req *http.Request) (int, []byte) {
+	t.Helper()
+	resp, err := e.srv.Client().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var buf bytes.Buffer
+	_, err = buf.ReadFrom(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, buf.Bytes()
+}
+
+// waitJob polls the job endpoint until the job finishes
+func (e *testEnv) waitJob(t *testing.T, id int64) Job {
+	t.Helper()
+	var job Job
+	require.Eventually(t, func() bool {
+		status, body := e.do(t, http.MethodGet, fmt.Sprintf("/api/jobs/%d", id), nil)
+		require.Equal(t, http.StatusOK, status)
+		require.NoError(t, json.Unmarshal(body, &job))
+		return job.Status == JobCompleted || job.Status == JobFailed
+	}, 5*time.Second, 10*time.Millisecond)
+	return job
+}
+
+func TestServer_TranscribeTranslateExport(t *testing.T) {
+	env := newTestEnv(t)
+	require.NoError(t, os.WriteFile(filepath.Join(env.mediaDir, "talk.mp4"), []byte("hello"), 0o600))
+
+	// submit a path inside the media directory
+	status, body := env.do(t, http.MethodPost, "/api/jobs/transcribe", map[string]string{"path": "talk.mp4"})
+	require.Equal(t, http.StatusAccepted, status, string(body))
+	var job Job
+	require.NoError(t, json.Unmarshal(body, &job))
+	require.Equal(t, JobTranscribe, job.Type)
+
+	job = env.waitJob(t, job.ID)
+	require.Equal(t, JobCompleted, job.Status, job.Error)
+	require.Equal(t, int64(1), job.TranscriptionID)
+
+	// fetch transcript and segments
+	status, body = env.do(t, http.MethodGet, "/api/transcriptions/1", nil)
+	require.Equal(t, http.StatusOK, status)
+	var record database.Transcription
+	require.NoError(t, json.Unmarshal(body, &record))
+	require.Equal(t, "talk.mp4", record.FileName)
+	require.Equal(t, "transcript: hello", record.Text)
+
+	status, body = env.do(t, http.MethodGet, "/api/transcriptions/1/segments", nil)
+	require.Equal(t, http.StatusOK, status)
+	var segments struct {
+		Segments []database.Segment `json:"segments"`
+	}
+	require.NoError(t, json.Unmarshal(body, &segments))
+	require.Len(t, segments.Segments, 1)
+	require.Equal(t, int64(1500), segments.Segments[0].End)
+
+	status, body = env.do(t, http.MethodGet, "/api/transcriptions/1/media", nil)
+	require.Equal(t, http.StatusOK, status)
+	var media database.Media
+	require.NoError(t, json.Unmarshal(body, &media))
+	require.Equal(t, int64(1500), media.DurationMS)
+	require.Equal(t, int64(5), media.FileSize)
+
+	// translate
+	status, body = env.do(t, http.MethodPost, "/api/jobs/translate", map[string]int64{"transcription_id": 1})
+	require.Equal(t, http.StatusAccepted, status, string(body))
+	require.NoError(t, json.Unmarshal(body, &job))
+	job = env.waitJob(t, job.ID)
+	require.Equal(t, JobCompleted, job.Status, job.Error)
+
+	status, body = env.do(t, http.MethodGet, "/api/transcriptions/1/translation", nil)
+	require.Equal(t, http.StatusOK, status)
+	require.JSONEq(t, `{"transcription_id": 1, "text": "translated: transcript: hello"}`, string(body))
+
+	// glossary was filled by the accept-all term policy
+	status, body = env.do(t, http.MethodGet, "/api/terms", nil)
+	require.Equal(t, http.StatusOK, status)
+	require.JSONEq(t, `[{"term": "API", "description": "interface"}]`, string(body))
+
+	// export
+	status, body = env.do(t, http.MethodPost, "/api/jobs/export", nil)
+	require.Equal(t, http.StatusAccepted, status)
+	require.NoError(t, json.Unmarshal(body, &job))
+	job = env.waitJob(t, job.ID)
+	require.Equal(t, JobCompleted, job.Status, job.Error)
+	require.Equal(t, []string{filepath.Join(env.exportTo, "translation_1.md")}, job.Files)
+	require.FileExists(t, job.Files[0])
+}
+
+func TestServer_Upload(t *testing.T) {
+	env := newTestEnv(t)
+
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	fw, err := mw.CreateFormFile("file", "meeting.mp4")
+	require.NoError(t, err)
+	_, err = fw.Write([]byte("uploaded"))
+	require.NoError(t, err)
+	require.NoError(t, mw.Close())
+
+	req, err := http.NewRequest(http.MethodPost, env.srv.URL+"/api/jobs/transcribe", &buf)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	status, body := env.send(t, req)
+	require.Equal(t, http.StatusAccepted, status, string(body))
+
+	var job Job
+	require.NoError(t, json.Unmarshal(body, &job))
+	require.Equal(t, "meeting.mp4", job.Source)
+	job = env.waitJob(t, job.ID)
+	require.Equal(t, JobCompleted, job.Status, job.Error)
+
+	record, err := env.store.GetTranscriptionRecord(job.TranscriptionID)
+	require.NoError(t, err)
+	require.Equal(t, "meeting.mp4", record.FileName)
+	require.Equal(t, "transcript: uploaded", record.Text)
+}
+
+func TestServer_Errors(t *testing.T) {
+	env := newTestEnv(t)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   any
+		status int
+	}{
+		{name: "path outside media dir", method: http.MethodPost, path: "/api/jobs/transcribe",
+			body: map[string]string{"path": "../secret.mp4"}, status: http.StatusBadRequest},
+		{name: "missing media file", method: http.MethodPost, path: "/api/jobs/transcribe",
+			body: map[string]string{"path": "missing.mp4"}, status: http.StatusBadRequest},
+		{name: "unknown field", method: http.MethodPost, path: "/api/jobs/transcribe",
+			body: map[string]string{"file": "x"}, status: http.StatusBadRequest},
+		{name: "translate unknown transcription", method: http.MethodPost, path: "/api/jobs/translate",
+			body: map[string]int64{"transcription_id": 42}, status: http.StatusNotFound},
+		{name: "unknown job", method: http.MethodGet, path: "/api/jobs/7", status: http.StatusNotFound},
+		{name: "invalid id", method: http.MethodGet, path: "/api/transcriptions/abc", status: http.StatusBadRequest},
+		{name: "unknown transcription", method: http.MethodGet, path: "/api/transcriptions/5", status: http.StatusNotFound},
+		{name: "missing translation", method: http.MethodGet, path: "/api/transcriptions/5/translation",
+			status: http.StatusNotFound},
+		{name: "empty term", method: http.MethodPost, path: "/api/terms",
+			body: map[string]string{"term": " "}, status: http.StatusBadRequest},
+		{name: "delete unknown term", method: http.MethodDelete, path: "/api/terms/nope", status: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := env.do(t, tt.method, tt.path, tt.body)
+			require.Equal(t, tt.status, status, string(body))
+			require.Contains(t, string(body), `"error"`)
+		})
+	}
+}
+
+func TestServer_Terms(t *testing.T) {
+	env := newTestEnv(t)
+
+	status, _ := env.do(t, http.MethodPost, "/api/terms", map[string]string{"term": "SQLite", "description": "database"})
+	require.Equal(t, http.StatusCreated, status)
+	require.Equal(t, "database", env.store.terms["SQLite"])
+
+	status, _ = env.do(t, http.MethodDelete, "/api/terms/SQLite", nil)
+	require.Equal(t, http.StatusNoContent, status)
+	require.Empty(t, env.store.terms)
+}
@@ -4,10 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
-	"path/filepath"
-	"runtime"
 	"strings"
+
+	"assemblyai-transcriber/internal/editor"
 )
 
 // Term represents a term that should not be translated
@@ -18,19 +17,60 @@ type Term struct {
 	Keep        bool     `json:"keep_untranslated"`
 }
 
+// Policy defines how found terms are resolved
+type Policy string
+
+// Supported term policies
+const (
+	PolicyInteractive Policy = "interactive"
+	PolicyAcceptAll   Policy = "accept"
+	PolicyRejectAll   Policy = "reject"
+)
+
+// ParsePolicy converts a string to a Policy
+func ParsePolicy(value string) (Policy, error) {
+	switch p := Policy(strings.ToLower(strings.TrimSpace(value))); p {
+	case PolicyInteractive, PolicyAcceptAll, PolicyRejectAll:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown term policy %q (expected interactive, accept or reject)", value)
+	}
+}
+
 // TermManager manages the terms analysis and user interaction
 type TermManager struct {
-	terms []*Term
-	input *strings.Reader // for testing
+	terms  []*Term
+	input  *strings.Reader // for testing
+	policy Policy
 }
 
 // New creates a new term manager
 func New() *TermManager {
 	return &TermManager{
-		terms: []*Term{},
+		terms:  []*Term{},
+		policy: PolicyInteractive,
 	}
 }
 
+// SetPolicy sets how terms are resolved by ProcessTerms
+func (tm *TermManager) SetPolicy(policy Policy) {
+	tm.policy = policy
+}
+
+// ProcessTerms resolves terms according to the configured policy, asking the user
+// only in interactive mode
+func (tm *TermManager) ProcessTerms() error {
+	switch tm.policy {
+	case PolicyAcceptAll:
+		tm.acceptAllTerms()
+	case PolicyRejectAll:
+		tm.rejectAllTerms()
+	default:
+		return tm.ProcessTermsInteractive()
+	}
+	return nil
+}
+
 // SetInput sets the input reader for testing
 func (tm *TermManager) SetInput(input *strings.Reader) {
 	tm.input = input
@@ -241,15 +281,8 @@ func (tm *TermManager) editInTextEditor() error {
 		return fmt.Errorf("error closing temp file: %w", err)
 	}
 
-	editor := getDefaultEditor()
-	cmd := exec.Command(editor, filepath.Clean(tmpFile.Name())) // #nosec G204
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-
-	fmt.Printf("Opening terms in %s. Save and exit the editor when finished.\n", editor)
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("error running editor: %w", err)
+	if err := editor.Edit(tmpFile.Name()); err != nil {
+		return err
 	}
 
 	editedData, err := os.ReadFile(tmpFile.Name())
@@ -283,21 +316,3 @@ func (tm *TermManager) GetUntranslatableTerms() []string {
 func (tm *TermManager) GetAllTerms() []*Term {
 	return tm.terms
 }
-
-// getDefaultEditor returns the default text editor based on OS
-func getDefaultEditor() string {
-	switch runtime.GOOS {
-	case "windows":
-		return "notepad"
-	case "darwin":
-		return "open -a TextEdit"
-	default:
-		if editor := os.Getenv("EDITOR"); editor != "" {
-			return editor
-		}
-		if editor := os.Getenv("VISUAL"); editor != "" {
-			return editor
-		}
-		return "nano"
-	}
-}
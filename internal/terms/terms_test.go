@@ -83,3 +83,40 @@ func TestTermManager_ProcessTermsInteractive_InvalidChoice(t *testing.T) {
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "invalid choice")
 }
+
+func TestTermManager_ProcessTerms_Policy(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy Policy
+		want   bool
+	}{
+		{name: "accept", policy: PolicyAcceptAll, want: true},
+		{name: "reject", policy: PolicyRejectAll, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := New()
+			tm.AddTerms([]*Term{
+				{Term: "term1", Keep: !tt.want},
+				{Term: "term2", Keep: !tt.want},
+			})
+			tm.SetPolicy(tt.policy)
+
+			// no input is set, so any prompt would fail the test
+			require.NoError(t, tm.ProcessTerms())
+			for _, term := range tm.GetAllTerms() {
+				require.Equal(t, tt.want, term.Keep)
+			}
+		})
+	}
+}
+
+func TestParsePolicy(t *testing.T) {
+	p, err := ParsePolicy("Accept")
+	require.NoError(t, err)
+	require.Equal(t, PolicyAcceptAll, p)
+
+	_, err = ParsePolicy("maybe")
+	require.Error(t, err)
+}
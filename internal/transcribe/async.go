@@ -0,0 +1,123 @@
+package transcribe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	assemblyai "github.com/AssemblyAI/assemblyai-go-sdk"
+)
+
+// ErrTranscriptionFailed is returned by Collect when AssemblyAI reports a job as failed
+var ErrTranscriptionFailed = errors.New("transcription failed")
+
+// Submission describes transcription jobs started on AssemblyAI for one input, one per
+// audio part. It is stored between Submit and Collect, possibly across process restarts.
+type Submission struct {
+	Parts    []SubmittedPart `json:"parts"`
+	Profile  string          `json:"profile,omitempty"`
+	Options  *Options        `json:"options,omitempty"`
+	Media    *Media          `json:"media,omitempty"`
+	CacheKey string          `json:"cache_key,omitempty"`
+	// Result is set instead of Parts when a cached transcript made submitting unnecessary
+	Result *Result `json:"-"`
+}
+
+// SubmittedPart is a transcription job of an audio part; OffsetMS is the part position
+// in the original media
+type SubmittedPart struct {
+	TranscriptID string `json:"transcript_id"`
+	OffsetMS     int64  `json:"offset_ms"`
+}
+
+// TranscriptIDs returns the AssemblyAI transcript IDs of the submission
+func (s *Submission) TranscriptIDs() []string {
+	ids := make([]string, 0, len(s.Parts))
+	for _, p := range s.Parts {
+		ids = append(ids, p.TranscriptID)
+	}
+	return ids
+}
+
+// Submit prepares and uploads the audio of a video or audio file and starts its
+// transcription without waiting for it to finish
+func (c *Client) Submit(ctx context.Context, videoPath string) (*Submission, error) {
+	media, err := c.probe(ctx, videoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheKey := c.transcriptCacheKey(media)
+	if result, ok := c.cachedTranscript(cacheKey); ok {
+		return &Submission{Profile: c.profile.Name, Media: media, Result: c.finish(result, media)}, nil
+	}
+
+	workspace, err := c.newWorkspace()
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(workspace)
+
+	parts, err := c.prepareAudio(ctx, videoPath, workspace, media)
+	if err != nil {
+		return nil, err
+	}
+
+	client := c.apiClient()
+	options := c.options
+	sub := &Submission{Profile: c.profile.Name, Options: &options, Media: media, CacheKey: cacheKey}
+	for i, part := range parts {
+		if c.progress != nil {
+			c.progress("uploading", float64(i)/float64(len(parts))*100)
+		}
+		id, err := c.submitAudio(ctx, client, part.path)
+		if err != nil {
+			return nil, fmt.Errorf("part %d of %d: %w", i+1, len(parts), err)
+		}
+		sub.Parts = append(sub.Parts, SubmittedPart{TranscriptID: id, OffsetMS: part.offset.Milliseconds()})
+	}
+	if c.progress != nil {
+		c.progress("uploading", 100)
+	}
+	return sub, nil
+}
+
+// Collect checks the jobs of a submission and returns the merged result once all of them
+// have completed. It returns nil without an error while any job is still in progress.
+func (c *Client) Collect(ctx context.Context, sub *Submission) (*Result, error) {
+	if sub.Result != nil {
+		return sub.Result, nil
+	}
+	if len(sub.Parts) == 0 {
+		return nil, fmt.Errorf("submission has no transcription jobs")
+	}
+
+	client := c.apiClient()
+	parts := make([]audioPart, 0, len(sub.Parts))
+	results := make([]*Result, 0, len(sub.Parts))
+	for _, part := range sub.Parts {
+		transcript, err := client.Transcripts.Get(ctx, part.TranscriptID)
+		if err != nil {
+			return nil, fmt.Errorf("transcript %s status error: %v", part.TranscriptID, err)
+		}
+		switch transcript.Status {
+		case assemblyai.TranscriptStatusCompleted:
+		case assemblyai.TranscriptStatusError:
+			return nil, fmt.Errorf("transcript %s: %w: %s",
+				part.TranscriptID, ErrTranscriptionFailed, assemblyai.ToString(transcript.Error))
+		default:
+			return nil, nil
+		}
+		parts = append(parts, audioPart{offset: time.Duration(part.OffsetMS) * time.Millisecond})
+		results = append(results, resultFromTranscript(transcript))
+	}
+
+	result := mergeResults(parts, results)
+	c.storeTranscript(sub.CacheKey, result)
+	result.Profile = sub.Profile
+	result.Options = sub.Options
+	result.Media = sub.Media
+	return result, nil
+}
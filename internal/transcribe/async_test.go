@@ -0,0 +1,80 @@
+package transcribe
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAPI serves AssemblyAI transcript status responses by transcript ID
+func fakeAPI(t *testing.T, transcripts map[string]string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/v2/transcript/")
+		body, ok := transcripts[id]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestCollect(t *testing.T) {
+	srv := fakeAPI(t, map[string]string{
+		"first": `{"id": "first", "status": "completed", "text": "Hello.", "language_code": "en_us",
+			"words": [{"text": "Hello.", "start": 100, "end": 600, "confidence": 0.9}]}`,
+		"second": `{"id": "second", "status": "completed", "text": "Bye.",
+			"words": [{"text": "Bye.", "start": 50, "end": 400, "confidence": 0.5}]}`,
+		"queued": `{"id": "queued", "status": "processing"}`,
+		"broken": `{"id": "broken", "status": "error", "error": "audio too short"}`,
+	})
+	client := New("test_key", 100)
+	client.baseURL = srv.URL
+
+	sub := &Submission{
+		Parts:   []SubmittedPart{{TranscriptID: "first"}, {TranscriptID: "second", OffsetMS: 60000}},
+		Profile: "speech",
+	}
+	assert.Equal(t, []string{"first", "second"}, sub.TranscriptIDs())
+
+	result, err := client.Collect(context.Background(), sub)
+	require.NoError(t, err)
+	assert.Equal(t, &Result{
+		Text: "Hello. Bye.",
+		Segments: []Segment{
+			{Start: 100, End: 600, Text: "Hello.", Confidence: 0.9},
+			{Start: 60050, End: 60400, Text: "Bye.", Confidence: 0.5},
+		},
+		Words: []Word{
+			{Start: 100, End: 600, Text: "Hello.", Confidence: 0.9},
+			{Start: 60050, End: 60400, Text: "Bye.", Confidence: 0.5},
+		},
+		Language: "en_us",
+		Profile:  "speech",
+	}, result)
+
+	result, err = client.Collect(context.Background(), &Submission{
+		Parts: []SubmittedPart{{TranscriptID: "first"}, {TranscriptID: "queued"}},
+	})
+	require.NoError(t, err)
+	assert.Nil(t, result, "still in progress")
+
+	_, err = client.Collect(context.Background(), &Submission{Parts: []SubmittedPart{{TranscriptID: "broken"}}})
+	assert.ErrorIs(t, err, ErrTranscriptionFailed)
+	assert.EqualError(t, err, "transcript broken: transcription failed: audio too short")
+
+	cached := &Result{Text: "cached"}
+	result, err = client.Collect(context.Background(), &Submission{Result: cached})
+	require.NoError(t, err)
+	assert.Same(t, cached, result)
+}
@@ -0,0 +1,161 @@
+package transcribe
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"assemblyai-transcriber/internal/cache"
+)
+
+const (
+	transcriptFile = "transcript.json"
+	audioMetaFile  = "audio.json"
+)
+
+// audioMeta describes cached extracted audio
+type audioMeta struct {
+	File     string `json:"file"`
+	OffsetMS int64  `json:"offset_ms"`
+}
+
+// SetCache enables caching of extracted audio and transcripts
+func (c *Client) SetCache(store *cache.Cache) {
+	c.cache = store
+}
+
+// audioCacheKey identifies extracted audio by the media content and extraction settings
+func (c *Client) audioCacheKey(media *Media) string {
+	return cache.Key(media.SHA256, fmt.Sprintf("%+v", c.profile), fmt.Sprintf("%+v", c.selection))
+}
+
+// transcriptCacheKey identifies a transcript by the audio and the transcriber settings.
+// It is empty when caching is disabled or the media is unknown.
+func (c *Client) transcriptCacheKey(media *Media) string {
+	if c.cache == nil || media == nil {
+		return ""
+	}
+	options, err := json.Marshal(c.options)
+	if err != nil {
+		return ""
+	}
+	return cache.Key(c.audioCacheKey(media), "assemblyai", strconv.Itoa(c.maxFileSizeBytes), string(options))
+}
+
+// cachedTranscript returns the transcript stored under key, if any
+func (c *Client) cachedTranscript(key string) (*Result, bool) {
+	if c.cache == nil || key == "" {
+		return nil, false
+	}
+	dir, ok := c.cache.Get(cache.KindTranscript, key)
+	if !ok {
+		return nil, false
+	}
+	data, err := os.ReadFile(filepath.Join(dir, transcriptFile))
+	if err != nil {
+		return nil, false
+	}
+	var result Result
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, false
+	}
+	return &result, true
+}
+
+// storeTranscript saves a transcript in the cache under key
+func (c *Client) storeTranscript(key string, result *Result) {
+	if c.cache == nil || key == "" {
+		return
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return
+	}
+	// the transcript is already paid for, failing to cache it must not fail the run
+	_, _ = c.cache.Put(cache.KindTranscript, key, func(dir string) error {
+		return os.WriteFile(filepath.Join(dir, transcriptFile), data, 0o600)
+	})
+}
+
+// extractAudioCached returns extracted audio of the media, copied from the cache or
+// extracted and cached. The returned file is always in the workspace, never in the cache,
+// so pruning the cache from another process cannot remove it while it is uploaded. Audio
+// files used as they are do not need caching.
+func (c *Client) extractAudioCached(
+	ctx context.Context, inputPath, workspace string, media *Media,
+) (string, time.Duration, error) {
+	if c.cache == nil || media == nil {
+		return c.extractAudio(ctx, inputPath, workspace)
+	}
+
+	key := c.audioCacheKey(media)
+	if dir, ok := c.cache.Get(cache.KindAudio, key); ok {
+		if meta, err := readAudioMeta(dir); err == nil {
+			audioPath := filepath.Join(workspace, meta.File)
+			// the entry may be pruned while it is copied, extract the audio again then
+			if err := copyFile(filepath.Join(dir, meta.File), audioPath); err == nil {
+				return audioPath, time.Duration(meta.OffsetMS) * time.Millisecond, nil
+			}
+			_ = os.Remove(audioPath)
+		}
+	}
+
+	audioPath, offset, err := c.extractAudio(ctx, inputPath, workspace)
+	if err != nil || filepath.Dir(audioPath) != workspace {
+		return audioPath, offset, err
+	}
+
+	// failing to cache the audio must not fail the run
+	_, _ = c.cache.Put(cache.KindAudio, key, func(dir string) error {
+		meta := audioMeta{File: filepath.Base(audioPath), OffsetMS: offset.Milliseconds()}
+		data, err := json.Marshal(meta)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, audioMetaFile), data, 0o600); err != nil {
+			return err
+		}
+		return copyFile(audioPath, filepath.Join(dir, meta.File))
+	})
+	return audioPath, offset, nil
+}
+
+// readAudioMeta reads the description of a cached audio entry
+func readAudioMeta(dir string) (*audioMeta, error) {
+	data, err := os.ReadFile(filepath.Join(dir, audioMetaFile))
+	if err != nil {
+		return nil, err
+	}
+	var meta audioMeta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(filepath.Join(dir, meta.File)); err != nil {
+		return nil, err
+	}
+	return &meta, nil
+}
+
+// copyFile copies the contents of src to a new file dst
+func copyFile(src, dst string) error {
+	in, err := os.Open(filepath.Clean(src))
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(filepath.Clean(dst), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
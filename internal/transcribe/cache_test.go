@@ -0,0 +1,88 @@
+package transcribe
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"assemblyai-transcriber/internal/cache"
+)
+
+func TestTranscribeVideo_CachedTranscript(t *testing.T) {
+	videoPath := filepath.Join(t.TempDir(), "talk.mp4")
+	require.NoError(t, os.WriteFile(videoPath, []byte("video"), 0o600))
+
+	execCommand = func(ctx context.Context, name string, arg ...string) *exec.Cmd {
+		if name == "ffprobe" {
+			return fakeCommand(ctx, fmt.Sprintf(videoProbe, "10.0"), 0)
+		}
+		t.Fatalf("unexpected %s call", name)
+		return nil
+	}
+	defer func() { execCommand = exec.CommandContext }()
+
+	client := New("test_key", 100)
+	client.SetCache(cache.New(t.TempDir(), 0))
+
+	media, err := ProbeMedia(context.Background(), videoPath)
+	require.NoError(t, err)
+	client.storeTranscript(client.transcriptCacheKey(media), &Result{Text: "cached", Segments: []Segment{{Start: 0, End: 500, Text: "cached"}}})
+
+	result, err := client.TranscribeVideo(context.Background(), videoPath)
+	require.NoError(t, err)
+	assert.Equal(t, "cached", result.Text)
+	assert.Equal(t, []Segment{{Start: 0, End: 500, Text: "cached"}}, result.Segments)
+	assert.Equal(t, DefaultProfile, result.Profile)
+	assert.Equal(t, media, result.Media)
+
+	// another profile produces different audio, so the transcript is not reused
+	speech, err := LookupProfile("speech")
+	require.NoError(t, err)
+	client.SetProfile(speech)
+	_, ok := client.cachedTranscript(client.transcriptCacheKey(media))
+	assert.False(t, ok)
+}
+
+func TestExtractAudioCached(t *testing.T) {
+	var ffmpegCalls int
+	execCommand = func(ctx context.Context, name string, arg ...string) *exec.Cmd {
+		if name == "ffprobe" {
+			return fakeCommand(ctx, fmt.Sprintf(videoProbe, "600.0"), 0)
+		}
+		ffmpegCalls++
+		return exec.CommandContext(ctx, "sh", "-c", `printf 'audio' > "$0"`, arg[len(arg)-1])
+	}
+	defer func() { execCommand = exec.CommandContext }()
+
+	client := New("test_key", 100)
+	client.SetCache(cache.New(t.TempDir(), 0))
+	client.SetSelection(Selection{Start: time.Minute})
+	media := &Media{SHA256: "abc"}
+
+	first, offset, err := client.extractAudioCached(context.Background(), "test.mp4", t.TempDir(), media)
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, offset)
+
+	workspace := t.TempDir()
+	second, offset, err := client.extractAudioCached(context.Background(), "test.mp4", workspace, media)
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, offset)
+	assert.Equal(t, filepath.Base(first), filepath.Base(second))
+	assert.Equal(t, 1, ffmpegCalls)
+
+	// the cached audio is copied into the workspace, pruning the cache does not remove it
+	assert.Equal(t, workspace, filepath.Dir(second))
+	removed, err := client.cache.Prune(0, 1)
+	require.NoError(t, err)
+	require.Len(t, removed, 1)
+	data, err := os.ReadFile(second)
+	require.NoError(t, err)
+	assert.Equal(t, "audio", string(data))
+}
@@ -0,0 +1,107 @@
+package transcribe
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ProgressFunc receives the progress of a processing stage in percent (0-100).
+type ProgressFunc func(stage string, percent float64)
+
+// probeDuration returns the media duration reported by ffprobe
+func probeDuration(ctx context.Context, path string) (time.Duration, error) {
+	cmd := execCommand(ctx, "ffprobe",
+		"-v", "error",
+		"-show_entries", "format=duration",
+		"-of", "default=noprint_wrappers=1:nokey=1",
+		path,
+	)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return 0, fmt.Errorf("ffprobe error: %v, stderr: %s", err, stderr.String())
+	}
+	return parseSeconds(strings.TrimSpace(string(out)))
+}
+
+// parseSeconds converts a decimal number of seconds as printed by ffmpeg tools to a duration
+func parseSeconds(value string) (time.Duration, error) {
+	seconds, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q: %w", value, err)
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// runFFmpeg runs ffmpeg with the given arguments, killing it when the context is cancelled,
+// and returns its log output. When total is known, progress reported by ffmpeg on stdout
+// is passed to the progress callback.
+func (c *Client) runFFmpeg(ctx context.Context, stage string, total time.Duration, args ...string) (string, error) {
+	fullArgs := append([]string{"-y", "-nostats", "-progress", "pipe:1"}, args...)
+	cmd := execCommand(ctx, "ffmpeg", fullArgs...)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return "", fmt.Errorf("ffmpeg stdout error: %v", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return "", fmt.Errorf("ffmpeg start error: %v", err)
+	}
+
+	parseProgress(stdout, total, func(percent float64) {
+		if c.progress != nil {
+			c.progress(stage, percent)
+		}
+	})
+
+	if err := cmd.Wait(); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return "", ctxErr
+		}
+		return "", fmt.Errorf("ffmpeg error: %v, stderr: %s", err, stderr.String())
+	}
+	return stderr.String(), nil
+}
+
+// parseProgress reads ffmpeg "-progress" key=value output and reports the processed share
+// of the total duration. It consumes the reader until EOF.
+func parseProgress(r io.Reader, total time.Duration, report func(percent float64)) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok {
+			continue
+		}
+
+		switch key {
+		// out_time_ms is in microseconds as well, older ffmpeg versions only print this one
+		case "out_time_us", "out_time_ms":
+			if total <= 0 {
+				continue
+			}
+			us, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || us < 0 {
+				continue
+			}
+			percent := float64(time.Duration(us)*time.Microsecond) / float64(total) * 100
+			report(min(percent, 100))
+		case "progress":
+			if value == "end" {
+				report(100)
+			}
+		}
+	}
+	// drain the pipe so that ffmpeg never blocks on a full buffer
+	_, _ = io.Copy(io.Discard, r)
+}
@@ -0,0 +1,105 @@
+package transcribe
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	assemblyai "github.com/AssemblyAI/assemblyai-go-sdk"
+
+	"assemblyai-transcriber/internal/config"
+)
+
+// AssemblyAI limits for word boost
+const (
+	maxWordBoost       = 1000
+	maxWordBoostLength = 6 // words per phrase
+)
+
+var (
+	// SpeechModels are the speech models accepted by AssemblyAI
+	SpeechModels = []string{"best", "nano", "slam-1", "universal"}
+	// BoostParams are the accepted word boost weights
+	BoostParams = []string{"low", "default", "high"}
+)
+
+// Options are the AssemblyAI transcription settings. They are stored with each
+// transcription, so zero values mean the AssemblyAI default.
+type Options struct {
+	LanguageCode      string   `json:"language_code,omitempty"` // e.g. "en_us" or "de"
+	LanguageDetection bool     `json:"language_detection,omitempty"`
+	SpeechModel       string   `json:"speech_model,omitempty"`
+	WordBoost         []string `json:"word_boost,omitempty"`
+	BoostParam        string   `json:"boost_param,omitempty"`
+	Punctuate         bool     `json:"punctuate"`
+	FormatText        bool     `json:"format_text"`
+	FilterProfanity   bool     `json:"filter_profanity,omitempty"`
+}
+
+// DefaultOptions returns the options used when nothing is configured
+func DefaultOptions() Options {
+	return Options{Punctuate: true, FormatText: true}
+}
+
+// OptionsFromConfig returns the transcription options of the application config
+func OptionsFromConfig(cfg *config.Config) Options {
+	opts := Options{
+		LanguageCode:      cfg.TranscribeLanguage,
+		LanguageDetection: cfg.TranscribeLanguageDetection,
+		SpeechModel:       cfg.TranscribeSpeechModel,
+		BoostParam:        cfg.TranscribeBoostParam,
+		Punctuate:         cfg.TranscribePunctuate,
+		FormatText:        cfg.TranscribeFormatText,
+		FilterProfanity:   cfg.TranscribeFilterProfanity,
+	}
+	opts.AddWordBoost(cfg.TranscribeWordBoost...)
+	return opts
+}
+
+// Validate checks the options against the values AssemblyAI accepts
+func (o Options) Validate() error {
+	if o.LanguageCode != "" && o.LanguageDetection {
+		return fmt.Errorf("language code %q and language detection cannot be used together", o.LanguageCode)
+	}
+	if o.SpeechModel != "" && !slices.Contains(SpeechModels, o.SpeechModel) {
+		return fmt.Errorf("unknown speech model %q (available: %s)", o.SpeechModel, strings.Join(SpeechModels, ", "))
+	}
+	if o.BoostParam != "" && !slices.Contains(BoostParams, o.BoostParam) {
+		return fmt.Errorf("unknown boost param %q (available: %s)", o.BoostParam, strings.Join(BoostParams, ", "))
+	}
+	if o.BoostParam != "" && len(o.WordBoost) == 0 {
+		return fmt.Errorf("boost param %q requires word boost", o.BoostParam)
+	}
+	return nil
+}
+
+// AddWordBoost adds words and phrases to the custom vocabulary. Duplicates and phrases
+// AssemblyAI would reject are skipped, and the list is capped at the API limit.
+func (o *Options) AddWordBoost(words ...string) {
+	for _, w := range words {
+		w = strings.Join(strings.Fields(w), " ")
+		if w == "" || len(strings.Fields(w)) > maxWordBoostLength || len(o.WordBoost) >= maxWordBoost {
+			continue
+		}
+		if !slices.ContainsFunc(o.WordBoost, func(s string) bool { return strings.EqualFold(s, w) }) {
+			o.WordBoost = append(o.WordBoost, w)
+		}
+	}
+}
+
+// params returns the AssemblyAI request parameters
+func (o Options) params() *assemblyai.TranscriptOptionalParams {
+	params := &assemblyai.TranscriptOptionalParams{
+		LanguageCode:    assemblyai.TranscriptLanguageCode(o.LanguageCode),
+		SpeechModel:     assemblyai.SpeechModel(o.SpeechModel),
+		WordBoost:       o.WordBoost,
+		BoostParam:      assemblyai.TranscriptBoostParam(o.BoostParam),
+		Punctuate:       assemblyai.Bool(o.Punctuate),
+		FormatText:      assemblyai.Bool(o.FormatText),
+		FilterProfanity: assemblyai.Bool(o.FilterProfanity),
+	}
+	if o.LanguageDetection {
+		params.LanguageDetection = assemblyai.Bool(true)
+	}
+	return params
+}
@@ -0,0 +1,88 @@
+package transcribe
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"assemblyai-transcriber/internal/config"
+)
+
+func TestOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    Options
+		wantErr string
+	}{
+		{name: "defaults", opts: DefaultOptions()},
+		{name: "full", opts: Options{LanguageCode: "de", SpeechModel: "nano", WordBoost: []string{"gRPC"}, BoostParam: "high"}},
+		{
+			name:    "language code with detection",
+			opts:    Options{LanguageCode: "de", LanguageDetection: true},
+			wantErr: `language code "de" and language detection cannot be used together`,
+		},
+		{
+			name:    "unknown speech model",
+			opts:    Options{SpeechModel: "fast"},
+			wantErr: `unknown speech model "fast" (available: best, nano, slam-1, universal)`,
+		},
+		{
+			name:    "unknown boost param",
+			opts:    Options{WordBoost: []string{"gRPC"}, BoostParam: "max"},
+			wantErr: `unknown boost param "max" (available: low, default, high)`,
+		},
+		{name: "boost param without words", opts: Options{BoostParam: "high"}, wantErr: `boost param "high" requires word boost`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.Validate()
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestOptions_AddWordBoost(t *testing.T) {
+	var opts Options
+	opts.AddWordBoost(" Kubernetes ", "", "kubernetes", "service  mesh", "one two three four five six seven")
+	assert.Equal(t, []string{"Kubernetes", "service mesh"}, opts.WordBoost)
+
+	for i := 0; i < maxWordBoost+10; i++ {
+		opts.AddWordBoost(fmt.Sprintf("term%d", i))
+	}
+	assert.Len(t, opts.WordBoost, maxWordBoost)
+}
+
+func TestOptions_Params(t *testing.T) {
+	opts := OptionsFromConfig(&config.Config{
+		TranscribeLanguageDetection: true,
+		TranscribeSpeechModel:       "best",
+		TranscribeWordBoost:         []string{"gRPC"},
+		TranscribeBoostParam:        "high",
+		TranscribePunctuate:         true,
+	})
+
+	data, err := json.Marshal(opts.params())
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"language_detection": true, "speech_model": "best", "word_boost": ["gRPC"],
+		"boost_param": "high", "punctuate": true, "format_text": false, "filter_profanity": false}`, string(data))
+}
+
+func TestClient_AddWordBoost(t *testing.T) {
+	cfg := &config.Config{TranscribeWordBoost: []string{"gRPC"}}
+	opts := OptionsFromConfig(cfg)
+	first, second := New("key", 100), New("key", 100)
+	first.SetOptions(opts)
+	second.SetOptions(opts)
+
+	first.AddWordBoost("Kubernetes")
+	assert.Equal(t, []string{"gRPC", "Kubernetes"}, first.options.WordBoost)
+	assert.Equal(t, []string{"gRPC"}, second.options.WordBoost)
+	assert.Equal(t, []string{"gRPC"}, opts.WordBoost)
+}
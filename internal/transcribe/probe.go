@@ -0,0 +1,249 @@
+package transcribe
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// audioFormats are ffprobe format names of audio files that are uploaded as they are
+var audioFormats = map[string]bool{
+	"mp3": true, "wav": true, "flac": true, "ogg": true, "aac": true, "m4a": true,
+}
+
+// Selection chooses which part of the input is transcribed
+type Selection struct {
+	Track    int           // 1-based number of the audio track; 0 uses the default track
+	Language string        // language tag of the audio track, e.g. "eng"; ignored when Track is set
+	Start    time.Duration // start of the range to transcribe
+	End      time.Duration // end of the range to transcribe; 0 means the end of the input
+}
+
+// Media describes the input file
+type Media struct {
+	Duration      time.Duration `json:"duration"`
+	Container     string        `json:"container"`
+	VideoCodec    string        `json:"video_codec,omitempty"`
+	AudioCodec    string        `json:"audio_codec,omitempty"`
+	BitRate       int64         `json:"bit_rate"`
+	AudioChannels int           `json:"audio_channels"`
+	Size          int64         `json:"size"`
+	SHA256        string        `json:"sha256"`
+	CreatedAt     *time.Time    `json:"created_at,omitempty"` // from container metadata
+}
+
+// mediaStream is a stream of the input as reported by ffprobe
+type mediaStream struct {
+	Index       int    `json:"index"`
+	CodecType   string `json:"codec_type"`
+	CodecName   string `json:"codec_name"`
+	Channels    int    `json:"channels"`
+	Disposition struct {
+		AttachedPic int `json:"attached_pic"`
+	} `json:"disposition"`
+	Tags struct {
+		Language string `json:"language"`
+	} `json:"tags"`
+}
+
+// mediaInfo describes the input file as reported by ffprobe
+type mediaInfo struct {
+	FormatName   string
+	Duration     time.Duration
+	BitRate      int64
+	CreationTime time.Time
+	Streams      []mediaStream
+}
+
+// ProbeMedia returns metadata and the SHA-256 content hash of a media file
+func ProbeMedia(ctx context.Context, path string) (*Media, error) {
+	info, err := probeMedia(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("file open error: %v", err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return nil, fmt.Errorf("file hash error: %v", err)
+	}
+
+	media := &Media{
+		Duration:  info.Duration,
+		Container: info.FormatName,
+		BitRate:   info.BitRate,
+		Size:      size,
+		SHA256:    hex.EncodeToString(hash.Sum(nil)),
+	}
+	if !info.CreationTime.IsZero() {
+		media.CreatedAt = &info.CreationTime
+	}
+	for _, s := range info.Streams {
+		switch {
+		case s.CodecType == "video" && s.Disposition.AttachedPic == 0 && media.VideoCodec == "":
+			media.VideoCodec = s.CodecName
+		case s.CodecType == "audio" && media.AudioCodec == "":
+			media.AudioCodec, media.AudioChannels = s.CodecName, s.Channels
+		}
+	}
+	return media, nil
+}
+
+// probeMedia returns the container format, duration and streams of the input
+func probeMedia(ctx context.Context, path string) (*mediaInfo, error) {
+	cmd := execCommand(ctx, "ffprobe",
+		"-v", "error",
+		"-show_entries", "format=format_name,duration,bit_rate:format_tags=creation_time"+
+			":stream=index,codec_type,codec_name,channels:stream_disposition=attached_pic:stream_tags=language",
+		"-of", "json",
+		path,
+	)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("ffprobe error: %v, stderr: %s", err, stderr.String())
+	}
+	return parseProbe(out)
+}
+
+// parseProbe decodes ffprobe JSON output
+func parseProbe(data []byte) (*mediaInfo, error) {
+	var raw struct {
+		Format struct {
+			FormatName string `json:"format_name"`
+			Duration   string `json:"duration"`
+			BitRate    string `json:"bit_rate"`
+			Tags       struct {
+				CreationTime string `json:"creation_time"`
+			} `json:"tags"`
+		} `json:"format"`
+		Streams []mediaStream `json:"streams"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parse ffprobe output: %w", err)
+	}
+
+	info := &mediaInfo{FormatName: raw.Format.FormatName, Streams: raw.Streams}
+	// streams such as subtitles or raw audio in some containers have no duration
+	if raw.Format.Duration != "" {
+		if d, err := parseSeconds(raw.Format.Duration); err == nil {
+			info.Duration = d
+		}
+	}
+	if n, err := strconv.ParseInt(raw.Format.BitRate, 10, 64); err == nil {
+		info.BitRate = n
+	}
+	if t, err := time.Parse(time.RFC3339Nano, raw.Format.Tags.CreationTime); err == nil {
+		info.CreationTime = t
+	}
+	return info, nil
+}
+
+// audioStreams returns the audio streams of the input in file order
+func (m *mediaInfo) audioStreams() []mediaStream {
+	var streams []mediaStream
+	for _, s := range m.Streams {
+		if s.CodecType == "audio" {
+			streams = append(streams, s)
+		}
+	}
+	return streams
+}
+
+// hasVideo reports whether the input has a video stream other than embedded cover art
+func (m *mediaInfo) hasVideo() bool {
+	for _, s := range m.Streams {
+		if s.CodecType == "video" && s.Disposition.AttachedPic == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// isAudioFile reports whether the input is an audio file in a format accepted for upload
+func (m *mediaInfo) isAudioFile() bool {
+	if m.hasVideo() || len(m.audioStreams()) != 1 {
+		return false
+	}
+	for _, name := range strings.Split(m.FormatName, ",") {
+		if audioFormats[name] {
+			return true
+		}
+	}
+	return false
+}
+
+// selectStream returns the ffprobe index of the audio stream chosen by the selection,
+// or -1 when the default stream should be used
+func (m *mediaInfo) selectStream(sel Selection) (int, error) {
+	streams := m.audioStreams()
+	if len(streams) == 0 {
+		return 0, fmt.Errorf("input has no audio streams")
+	}
+
+	switch {
+	case sel.Track > 0:
+		if sel.Track > len(streams) {
+			return 0, fmt.Errorf("audio track %d not found, input has %d", sel.Track, len(streams))
+		}
+		return streams[sel.Track-1].Index, nil
+	case sel.Language != "":
+		var available []string
+		for _, s := range streams {
+			if strings.EqualFold(s.Tags.Language, sel.Language) {
+				return s.Index, nil
+			}
+			if s.Tags.Language != "" {
+				available = append(available, s.Tags.Language)
+			}
+		}
+		return 0, fmt.Errorf("no audio track with language %q (available: %s)", sel.Language, strings.Join(available, ", "))
+	}
+	return -1, nil
+}
+
+// ParseTimestamp parses a position in the media given as seconds ("90.5"),
+// clock time ("1:30", "01:02:03.5") or a Go duration ("1m30s")
+func ParseTimestamp(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		if d < 0 {
+			return 0, fmt.Errorf("negative timestamp %q", value)
+		}
+		return d, nil
+	}
+
+	fields := strings.Split(value, ":")
+	if len(fields) > 3 {
+		return 0, fmt.Errorf("invalid timestamp %q", value)
+	}
+	var total float64
+	for _, field := range fields {
+		n, err := strconv.ParseFloat(field, 64)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid timestamp %q", value)
+		}
+		total = total*60 + n
+	}
+	return time.Duration(total * float64(time.Second)), nil
+}
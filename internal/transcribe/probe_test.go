@@ -0,0 +1,133 @@
+package transcribe
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseProbe(t *testing.T) {
+	info, err := parseProbe([]byte(`{
+		"streams": [
+			{"index": 0, "codec_name": "h264", "codec_type": "video", "disposition": {"attached_pic": 0}},
+			{"index": 1, "codec_name": "aac", "codec_type": "audio", "tags": {"language": "eng"}}
+		],
+		"format": {"format_name": "mov,mp4,m4a,3gp,3g2,mj2", "duration": "12.500000"}
+	}`))
+	require.NoError(t, err)
+	assert.Equal(t, "mov,mp4,m4a,3gp,3g2,mj2", info.FormatName)
+	assert.Equal(t, 12500*time.Millisecond, info.Duration)
+	assert.True(t, info.hasVideo())
+	assert.False(t, info.isAudioFile())
+	require.Len(t, info.audioStreams(), 1)
+	assert.Equal(t, "eng", info.audioStreams()[0].Tags.Language)
+
+	_, err = parseProbe([]byte("10.0"))
+	assert.Error(t, err)
+}
+
+func TestProbeMedia(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "talk.mp4")
+	require.NoError(t, os.WriteFile(path, []byte("hello"), 0o600))
+
+	execCommand = func(ctx context.Context, name string, arg ...string) *exec.Cmd {
+		return fakeCommand(ctx, `{"format": {"format_name": "mov,mp4,m4a,3gp,3g2,mj2", "duration": "62.0",
+"bit_rate": "1200000", "tags": {"creation_time": "2024-05-01T10:00:00.000000Z"}},
+"streams": [{"index": 0, "codec_type": "video", "codec_name": "h264"},
+{"index": 1, "codec_type": "audio", "codec_name": "aac", "channels": 2},
+{"index": 2, "codec_type": "audio", "codec_name": "ac3", "channels": 6}]}`, 0)
+	}
+	defer func() { execCommand = exec.CommandContext }()
+
+	media, err := ProbeMedia(context.Background(), path)
+	require.NoError(t, err)
+	created := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	assert.Equal(t, &Media{
+		Duration:      62 * time.Second,
+		Container:     "mov,mp4,m4a,3gp,3g2,mj2",
+		VideoCodec:    "h264",
+		AudioCodec:    "aac",
+		BitRate:       1200000,
+		AudioChannels: 2,
+		Size:          5,
+		SHA256:        "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+		CreatedAt:     &created,
+	}, media)
+}
+
+func TestIsAudioFile(t *testing.T) {
+	audio := func(format string, streams ...mediaStream) *mediaInfo {
+		return &mediaInfo{FormatName: format, Streams: streams}
+	}
+	track := mediaStream{CodecType: "audio"}
+
+	assert.True(t, audio("wav", track).isAudioFile())
+	assert.True(t, audio("mov,mp4,m4a,3gp,3g2,mj2", track).isAudioFile())
+	assert.False(t, audio("amr", track).isAudioFile(), "unsupported format")
+	assert.False(t, audio("ogg", track, track).isAudioFile(), "several tracks need a selection")
+	assert.False(t, audio("mp3").isAudioFile(), "no audio")
+}
+
+func TestSelectStream(t *testing.T) {
+	info := &mediaInfo{Streams: []mediaStream{
+		{Index: 0, CodecType: "video"},
+		{Index: 1, CodecType: "audio"},
+		{Index: 2, CodecType: "audio"},
+	}}
+	info.Streams[1].Tags.Language = "eng"
+	info.Streams[2].Tags.Language = "fra"
+
+	idx, err := info.selectStream(Selection{})
+	require.NoError(t, err)
+	assert.Equal(t, -1, idx)
+
+	idx, err = info.selectStream(Selection{Track: 2})
+	require.NoError(t, err)
+	assert.Equal(t, 2, idx)
+
+	idx, err = info.selectStream(Selection{Language: "eng"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, idx)
+
+	_, err = info.selectStream(Selection{Language: "spa"})
+	assert.EqualError(t, err, `no audio track with language "spa" (available: eng, fra)`)
+
+	_, err = (&mediaInfo{Streams: info.Streams[:1]}).selectStream(Selection{})
+	assert.EqualError(t, err, "input has no audio streams")
+}
+
+func TestParseTimestamp(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{value: "", want: 0},
+		{value: "90", want: 90 * time.Second},
+		{value: "90.5", want: 90500 * time.Millisecond},
+		{value: "1:30", want: 90 * time.Second},
+		{value: "01:02:03.5", want: time.Hour + 2*time.Minute + 3500*time.Millisecond},
+		{value: "1m30s", want: 90 * time.Second},
+		{value: "-5s", wantErr: true},
+		{value: "1:2:3:4", wantErr: true},
+		{value: "soon", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseTimestamp(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
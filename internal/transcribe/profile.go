@@ -0,0 +1,157 @@
+package transcribe
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultProfile is the name of the profile used when none is selected
+const DefaultProfile = "default"
+
+const (
+	loudnormFilter = "loudnorm=I=-16:TP=-1.5:LRA=11"
+	denoiseFilter  = "afftdn=nf=-25"
+	// trimMargin is kept around speech when leading and trailing silence is trimmed
+	trimMargin = 250 * time.Millisecond
+)
+
+// Profile describes how audio is extracted and preprocessed before upload
+type Profile struct {
+	Name        string
+	Description string
+	SampleRate  string // ffmpeg -ar value, e.g. "16k"
+	Channels    int
+	Codec       string // ffmpeg audio encoder; the muxer default when empty
+	Bitrate     string // empty for lossless codecs
+	Format      string // ffmpeg muxer
+	Ext         string
+	Loudnorm    bool // EBU R128 loudness normalization
+	TrimSilence bool // cut leading and trailing silence
+	Denoise     bool // FFT-based noise reduction
+}
+
+var profiles = map[string]Profile{
+	DefaultProfile: {
+		Name:        DefaultProfile,
+		Description: "44.1kHz stereo MP3 128k, no filters",
+		SampleRate:  "44.1k",
+		Channels:    2,
+		Bitrate:     "128k",
+		Format:      "mp3",
+		Ext:         ".mp3",
+	},
+	"speech": {
+		Name:        "speech",
+		Description: "16kHz mono Opus 32k, loudness normalization, silence trim",
+		SampleRate:  "16k",
+		Channels:    1,
+		Codec:       "libopus",
+		Bitrate:     "32k",
+		Format:      "ogg",
+		Ext:         ".ogg",
+		Loudnorm:    true,
+		TrimSilence: true,
+	},
+	"speech-flac": {
+		Name:        "speech-flac",
+		Description: "16kHz mono FLAC (lossless), loudness normalization, silence trim",
+		SampleRate:  "16k",
+		Channels:    1,
+		Codec:       "flac",
+		Format:      "flac",
+		Ext:         ".flac",
+		Loudnorm:    true,
+		TrimSilence: true,
+	},
+	"speech-clean": {
+		Name:        "speech-clean",
+		Description: "speech profile with noise reduction for noisy recordings",
+		SampleRate:  "16k",
+		Channels:    1,
+		Codec:       "libopus",
+		Bitrate:     "32k",
+		Format:      "ogg",
+		Ext:         ".ogg",
+		Loudnorm:    true,
+		TrimSilence: true,
+		Denoise:     true,
+	},
+}
+
+// LookupProfile returns the preprocessing profile with the given name
+func LookupProfile(name string) (Profile, error) {
+	if name == "" {
+		name = DefaultProfile
+	}
+	p, ok := profiles[strings.ToLower(name)]
+	if !ok {
+		return Profile{}, fmt.Errorf("unknown audio profile %q (available: %s)", name, strings.Join(ProfileNames(), ", "))
+	}
+	return p, nil
+}
+
+// ProfileNames returns the names of all built-in profiles in alphabetical order
+func ProfileNames() []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// filters returns the ffmpeg audio filter chain of the profile
+func (p Profile) filters() string {
+	var chain []string
+	if p.Denoise {
+		chain = append(chain, denoiseFilter)
+	}
+	if p.Loudnorm {
+		chain = append(chain, loudnormFilter)
+	}
+	return strings.Join(chain, ",")
+}
+
+// passthrough reports whether audio that is already in an accepted format can be used
+// without re-encoding
+func (p Profile) passthrough() bool {
+	return p.filters() == "" && !p.TrimSilence
+}
+
+// outputArgs returns the ffmpeg output options that encode audio with this profile
+func (p Profile) outputArgs(outputPath string) []string {
+	args := []string{"-vn", "-ar", p.SampleRate, "-ac", fmt.Sprint(p.Channels)}
+	if p.Codec != "" {
+		args = append(args, "-c:a", p.Codec)
+	}
+	if p.Bitrate != "" {
+		args = append(args, "-ab", p.Bitrate)
+	}
+	if f := p.filters(); f != "" {
+		args = append(args, "-af", f)
+	}
+	return append(args, "-f", p.Format, outputPath)
+}
+
+// speechBounds returns the range to keep after trimming leading and trailing silence.
+// Timestamps stay relative to the original media because the start is used as an offset.
+func speechBounds(duration time.Duration, silences []silence) (start, end time.Duration) {
+	start, end = 0, duration
+	if len(silences) == 0 || duration <= 0 {
+		return start, end
+	}
+
+	if first := silences[0]; first.start <= trimMargin {
+		start = max(first.end-trimMargin, 0)
+	}
+	if last := silences[len(silences)-1]; duration-last.end <= trimMargin {
+		end = min(last.start+trimMargin, duration)
+	}
+	// the whole file is silent, keep it as is and let the transcriber decide
+	if end <= start {
+		return 0, duration
+	}
+	return start, end
+}
@@ -0,0 +1,67 @@
+package transcribe
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupProfile(t *testing.T) {
+	p, err := LookupProfile("")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultProfile, p.Name)
+
+	p, err = LookupProfile("Speech-Clean")
+	require.NoError(t, err)
+	assert.Equal(t, "afftdn=nf=-25,loudnorm=I=-16:TP=-1.5:LRA=11", p.filters())
+
+	_, err = LookupProfile("podcast")
+	assert.ErrorContains(t, err, `unknown audio profile "podcast"`)
+}
+
+func TestSpeechBounds(t *testing.T) {
+	tests := []struct {
+		name      string
+		silences  []silence
+		wantStart time.Duration
+		wantEnd   time.Duration
+	}{
+		{
+			name:    "no silences",
+			wantEnd: time.Minute,
+		},
+		{
+			name: "silence in the middle only",
+			silences: []silence{
+				{start: 20 * time.Second, end: 25 * time.Second},
+			},
+			wantEnd: time.Minute,
+		},
+		{
+			name: "leading and trailing silence",
+			silences: []silence{
+				{start: 0, end: 5 * time.Second},
+				{start: 50 * time.Second, end: time.Minute},
+			},
+			wantStart: 4750 * time.Millisecond,
+			wantEnd:   50250 * time.Millisecond,
+		},
+		{
+			name: "whole file is silent",
+			silences: []silence{
+				{start: 0, end: time.Minute},
+			},
+			wantEnd: time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := speechBounds(time.Minute, tt.silences)
+			assert.Equal(t, tt.wantStart, start)
+			assert.Equal(t, tt.wantEnd, end)
+		})
+	}
+}
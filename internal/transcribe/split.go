@@ -0,0 +1,243 @@
+package transcribe
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// sizeHeadroom keeps parts safely below the limit, bitrate is not constant across a file
+	sizeHeadroom = 0.9
+	// maxSplitAttempts bounds how many times splitting is retried with more parts
+	maxSplitAttempts = 3
+	silenceNoise     = "-30dB"
+	silenceMinLength = "0.5"
+)
+
+// audioPart is a piece of audio to transcribe with its position in the original file
+type audioPart struct {
+	path   string
+	offset time.Duration
+}
+
+// silence is a detected interval of silence in the audio
+type silence struct {
+	start time.Duration
+	end   time.Duration
+}
+
+// splitIfTooLarge returns the audio file as a single part when it fits the upload limit,
+// otherwise splits it at silence boundaries into parts under the limit
+func (c *Client) splitIfTooLarge(ctx context.Context, audioPath, workspace string) ([]audioPart, error) {
+	info, err := os.Stat(audioPath)
+	if err != nil {
+		return nil, fmt.Errorf("file stat error: %v", err)
+	}
+	if c.maxFileSizeBytes <= 0 || info.Size() <= int64(c.maxFileSizeBytes) {
+		return []audioPart{{path: audioPath}}, nil
+	}
+
+	duration, err := probeDuration(ctx, audioPath)
+	if err != nil {
+		return nil, err
+	}
+
+	logOutput, err := c.runFFmpeg(ctx, "detecting silence", duration,
+		"-i", audioPath, "-af", "silencedetect=noise="+silenceNoise+":d="+silenceMinLength, "-f", "null", "-")
+	if err != nil {
+		return nil, err
+	}
+	silences := parseSilences(logOutput)
+
+	limit := float64(c.maxFileSizeBytes) * sizeHeadroom
+	count := int(float64(info.Size())/limit) + 1
+	for attempt := 0; attempt < maxSplitAttempts; attempt++ {
+		cuts := planSplits(duration, count, silences)
+		parts, err := c.splitAudio(ctx, audioPath, workspace, duration, cuts)
+		if err != nil {
+			return nil, err
+		}
+		if oversized := largestPart(parts); oversized <= int64(c.maxFileSizeBytes) {
+			return parts, nil
+		}
+		for _, p := range parts {
+			_ = os.Remove(p.path)
+		}
+		count++
+	}
+
+	return nil, fmt.Errorf("could not split %d bytes into parts under %d bytes", info.Size(), c.maxFileSizeBytes)
+}
+
+// splitAudio cuts the audio at the given points without re-encoding
+func (c *Client) splitAudio(
+	ctx context.Context, audioPath, workspace string, duration time.Duration, cuts []time.Duration,
+) ([]audioPart, error) {
+	bounds := append(append([]time.Duration{0}, cuts...), duration)
+	ext := filepath.Ext(audioPath)
+
+	parts := make([]audioPart, 0, len(bounds)-1)
+	for i := 0; i < len(bounds)-1; i++ {
+		start, end := bounds[i], bounds[i+1]
+		partPath := filepath.Join(workspace, fmt.Sprintf("part_%03d%s", i, ext))
+
+		args := []string{"-i", audioPath, "-ss", formatSeconds(start)}
+		if i < len(bounds)-2 {
+			args = append(args, "-to", formatSeconds(end))
+		}
+		args = append(args, "-c", "copy", partPath)
+
+		stage := fmt.Sprintf("splitting %d/%d", i+1, len(bounds)-1)
+		if _, err := c.runFFmpeg(ctx, stage, end-start, args...); err != nil {
+			return nil, err
+		}
+		parts = append(parts, audioPart{path: partPath, offset: start})
+	}
+	return parts, nil
+}
+
+// transcribeParts transcribes every part and stitches the results together
+func (c *Client) transcribeParts(ctx context.Context, parts []audioPart) (*Result, error) {
+	if len(parts) == 1 && parts[0].offset == 0 {
+		return c.transcribeAudio(ctx, parts[0].path)
+	}
+
+	results := make([]*Result, 0, len(parts))
+	for i, part := range parts {
+		if c.progress != nil {
+			c.progress("transcribing parts", float64(i)/float64(len(parts))*100)
+		}
+		res, err := c.transcribeAudio(ctx, part.path)
+		if err != nil {
+			return nil, fmt.Errorf("part %d of %d: %w", i+1, len(parts), err)
+		}
+		results = append(results, res)
+	}
+	if c.progress != nil {
+		c.progress("transcribing parts", 100)
+	}
+
+	return mergeResults(parts, results), nil
+}
+
+// mergeResults joins part transcripts, shifting segment timestamps by the part offsets
+func mergeResults(parts []audioPart, results []*Result) *Result {
+	merged := &Result{}
+	texts := make([]string, 0, len(results))
+	for i, res := range results {
+		if text := strings.TrimSpace(res.Text); text != "" {
+			texts = append(texts, text)
+		}
+		if merged.Language == "" {
+			merged.Language = res.Language
+		}
+		offset := parts[i].offset.Milliseconds()
+		for _, seg := range res.Segments {
+			seg.Start += offset
+			seg.End += offset
+			merged.Segments = append(merged.Segments, seg)
+		}
+		for _, w := range res.Words {
+			w.Start += offset
+			w.End += offset
+			merged.Words = append(merged.Words, w)
+		}
+	}
+	merged.Text = strings.Join(texts, " ")
+	return merged
+}
+
+// parseSilences extracts silence intervals from ffmpeg silencedetect log output
+func parseSilences(output string) []silence {
+	var (
+		silences []silence
+		start    time.Duration
+		inside   bool
+	)
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if value, ok := fieldAfter(line, "silence_start:"); ok {
+			if d, err := parseSeconds(value); err == nil {
+				start, inside = max(d, 0), true
+			}
+			continue
+		}
+		if value, ok := fieldAfter(line, "silence_end:"); ok && inside {
+			if d, err := parseSeconds(value); err == nil {
+				silences = append(silences, silence{start: start, end: d})
+			}
+			inside = false
+		}
+	}
+	return silences
+}
+
+// fieldAfter returns the first whitespace-separated token following marker in line
+func fieldAfter(line, marker string) (string, bool) {
+	idx := strings.Index(line, marker)
+	if idx < 0 {
+		return "", false
+	}
+	fields := strings.Fields(line[idx+len(marker):])
+	if len(fields) == 0 {
+		return "", false
+	}
+	return fields[0], true
+}
+
+// planSplits returns count-1 cut points dividing the duration into roughly equal parts.
+// Each cut is moved to the middle of the nearest silence within a quarter of the part
+// length, so that words are not cut in half.
+func planSplits(duration time.Duration, count int, silences []silence) []time.Duration {
+	if count < 2 || duration <= 0 {
+		return nil
+	}
+
+	partLen := duration / time.Duration(count)
+	window := partLen / 4
+	cuts := make([]time.Duration, 0, count-1)
+	prev := time.Duration(0)
+
+	for i := 1; i < count; i++ {
+		target := partLen * time.Duration(i)
+		cut := target
+		best := window + 1
+		for _, s := range silences {
+			mid := s.start + (s.end-s.start)/2
+			dist := mid - target
+			if dist < 0 {
+				dist = -dist
+			}
+			if dist <= window && dist < best && mid > prev {
+				cut, best = mid, dist
+			}
+		}
+		cuts = append(cuts, cut)
+		prev = cut
+	}
+	return cuts
+}
+
+// largestPart returns the size of the biggest part file
+func largestPart(parts []audioPart) int64 {
+	var largest int64
+	for _, p := range parts {
+		if info, err := os.Stat(p.path); err == nil && info.Size() > largest {
+			largest = info.Size()
+		}
+	}
+	return largest
+}
+
+// formatSeconds formats a duration as decimal seconds for ffmpeg arguments
+func formatSeconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', 3, 64)
+}
@@ -0,0 +1,146 @@
+package transcribe
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSilences(t *testing.T) {
+	output := `Input #0, mp3, from 'audio.mp3':
+[silencedetect @ 0x5581] silence_start: 12.5
+[silencedetect @ 0x5581] silence_end: 13.5 | silence_duration: 1
+[silencedetect @ 0x5581] silence_end: 20 | silence_duration: 1
+[silencedetect @ 0x5581] silence_start: -0.01
+[silencedetect @ 0x5581] silence_end: 0.8 | silence_duration: 0.81
+size=N/A time=00:01:00.00 bitrate=N/A speed= 500x
+`
+	assert.Equal(t, []silence{
+		{start: 12500 * time.Millisecond, end: 13500 * time.Millisecond},
+		{start: 0, end: 800 * time.Millisecond},
+	}, parseSilences(output))
+	assert.Empty(t, parseSilences("no silence here"))
+}
+
+func TestPlanSplits(t *testing.T) {
+	tests := []struct {
+		name     string
+		duration time.Duration
+		count    int
+		silences []silence
+		want     []time.Duration
+	}{
+		{
+			name:     "single part",
+			duration: time.Minute,
+			count:    1,
+		},
+		{
+			name:     "no silences cuts evenly",
+			duration: 90 * time.Second,
+			count:    3,
+			want:     []time.Duration{30 * time.Second, 60 * time.Second},
+		},
+		{
+			name:     "nearest silence within window",
+			duration: 120 * time.Second,
+			count:    2,
+			silences: []silence{
+				{start: 40 * time.Second, end: 41 * time.Second},         // too far from 60s
+				{start: 55 * time.Second, end: 57 * time.Second},         // 4s away
+				{start: 62 * time.Second, end: 62500 * time.Millisecond}, // 2.25s away
+			},
+			want: []time.Duration{62250 * time.Millisecond},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, planSplits(tt.duration, tt.count, tt.silences))
+		})
+	}
+}
+
+func TestMergeResults(t *testing.T) {
+	parts := []audioPart{
+		{path: "part_000.mp3"},
+		{path: "part_001.mp3", offset: 61500 * time.Millisecond},
+	}
+	results := []*Result{
+		{Text: "First part.", Segments: []Segment{{Start: 0, End: 1000, Text: "First part."}}},
+		{
+			Text:     " Second part. ",
+			Segments: []Segment{{Start: 200, End: 1500, Text: "Second part."}},
+			Words:    []Word{{Start: 200, End: 800, Text: "Second", Confidence: 0.9}},
+		},
+	}
+
+	assert.Equal(t, &Result{
+		Text: "First part. Second part.",
+		Segments: []Segment{
+			{Start: 0, End: 1000, Text: "First part."},
+			{Start: 61700, End: 63000, Text: "Second part."},
+		},
+		Words: []Word{{Start: 61700, End: 62300, Text: "Second", Confidence: 0.9}},
+	}, mergeResults(parts, results))
+}
+
+func TestSplitIfTooLarge(t *testing.T) {
+	workspace := t.TempDir()
+	audioPath := filepath.Join(workspace, "audio.mp3")
+	require.NoError(t, os.WriteFile(audioPath, make([]byte, 2500), 0o600))
+
+	var splitArgs [][]string
+	execCommand = func(ctx context.Context, name string, arg ...string) *exec.Cmd {
+		if name == "ffprobe" {
+			return fakeCommand(ctx, "120.0", 0)
+		}
+		for _, a := range arg {
+			if a == "null" {
+				return exec.CommandContext(ctx, "sh", "-c",
+					`echo "[silencedetect @ 0x1] silence_start: 39.5" >&2; `+
+						`echo "[silencedetect @ 0x1] silence_end: 40.5 | silence_duration: 1" >&2`)
+			}
+		}
+		splitArgs = append(splitArgs, arg)
+		// write a part small enough to pass the limit
+		return exec.CommandContext(ctx, "sh", "-c", `printf 'part' > "$0"`, arg[len(arg)-1])
+	}
+	defer func() { execCommand = exec.CommandContext }()
+
+	client := New("test_key", 0)
+	client.maxFileSizeBytes = 1000
+
+	parts, err := client.splitIfTooLarge(context.Background(), audioPath, workspace)
+	require.NoError(t, err)
+	require.Len(t, parts, 3)
+	assert.Equal(t, time.Duration(0), parts[0].offset)
+	assert.Equal(t, 40*time.Second, parts[1].offset)
+	assert.Equal(t, 80*time.Second, parts[2].offset)
+
+	require.Len(t, splitArgs, 3)
+	assert.Equal(t, []string{
+		"-y", "-nostats", "-progress", "pipe:1",
+		"-i", audioPath, "-ss", "0.000", "-to", "40.000", "-c", "copy", filepath.Join(workspace, "part_000.mp3"),
+	}, splitArgs[0])
+	assert.Equal(t, []string{
+		"-y", "-nostats", "-progress", "pipe:1",
+		"-i", audioPath, "-ss", "80.000", "-c", "copy", filepath.Join(workspace, "part_002.mp3"),
+	}, splitArgs[2])
+}
+
+func TestSplitIfTooLarge_SmallFile(t *testing.T) {
+	audioPath := filepath.Join(t.TempDir(), "audio.mp3")
+	require.NoError(t, os.WriteFile(audioPath, []byte("small"), 0o600))
+
+	client := New("test_key", 1)
+	parts, err := client.splitIfTooLarge(context.Background(), audioPath, t.TempDir())
+	require.NoError(t, err)
+	assert.Equal(t, []audioPart{{path: audioPath}}, parts)
+}
@@ -1,61 +1,311 @@
 package transcribe
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	assemblyai "github.com/AssemblyAI/assemblyai-go-sdk"
+
+	"assemblyai-transcriber/internal/cache"
+	"assemblyai-transcriber/internal/config"
 )
 
-var execCommand = exec.Command
+var execCommand = exec.CommandContext
 
 // Client is a video transcription client for AssemblyAI.
 type Client struct {
 	apiKey           string
 	maxFileSizeBytes int
+	workDir          string
+	profile          Profile
+	options          Options
+	selection        Selection
+	cache            *cache.Cache
+	baseURL          string // AssemblyAI API URL, the SDK default when empty
+	progress         ProgressFunc
+}
+
+// Segment is a sentence-level part of a transcript; Start and End are in milliseconds.
+// Confidence is the average confidence of its words, from 0 to 1.
+type Segment struct {
+	Start      int64   `json:"start"`
+	End        int64   `json:"end"`
+	Text       string  `json:"text"`
+	Confidence float64 `json:"confidence,omitempty"`
+}
+
+// Word is a recognized word with its timing in milliseconds and confidence from 0 to 1
+type Word struct {
+	Start      int64   `json:"start"`
+	End        int64   `json:"end"`
+	Text       string  `json:"text"`
+	Confidence float64 `json:"confidence"`
+}
+
+// Result holds the transcript text and its timed segments.
+type Result struct {
+	Text     string    `json:"text"`
+	Segments []Segment `json:"segments,omitempty"`
+	Words    []Word    `json:"words,omitempty"`
+	Language string    `json:"language,omitempty"` // language code reported by AssemblyAI, e.g. "en_us"
+	Profile  string    `json:"profile,omitempty"`
+	Options  *Options  `json:"options,omitempty"`
+	Media    *Media    `json:"media,omitempty"`
 }
 
 // New creates a new transcription client
 func New(apiKey string, maxFileSizeMB int) *Client {
 	maxBytes := maxFileSizeMB * 1024 * 1024
-	return &Client{apiKey: apiKey, maxFileSizeBytes: maxBytes}
+	return &Client{
+		apiKey:           apiKey,
+		maxFileSizeBytes: maxBytes,
+		profile:          profiles[DefaultProfile],
+		options:          DefaultOptions(),
+	}
+}
+
+// NewFromConfig creates a new transcription client with settings from the application config.
+// An unknown audio profile falls back to the default one; callers validate it with LookupProfile.
+func NewFromConfig(apiKey string, cfg *config.Config) *Client {
+	c := New(apiKey, cfg.MaxAudioFileSizeMB)
+	c.SetWorkDir(cfg.AudioCacheDir)
+	if p, err := LookupProfile(cfg.AudioProfile); err == nil {
+		c.SetProfile(p)
+	}
+	c.SetOptions(OptionsFromConfig(cfg))
+	if cfg.CacheDir != "" {
+		c.SetCache(cache.New(cfg.CacheDir, int64(cfg.CacheMaxSizeMB)*1024*1024))
+	}
+	return c
+}
+
+// SetProfile sets the audio preprocessing profile
+func (c *Client) SetProfile(p Profile) {
+	c.profile = p
+}
+
+// SetOptions sets the AssemblyAI transcription options
+func (c *Client) SetOptions(opts Options) {
+	c.options = opts
+}
+
+// AddWordBoost adds words and phrases to the custom vocabulary of the transcription
+func (c *Client) AddWordBoost(words ...string) {
+	// copy, so that clients sharing the configured options do not see each other's words
+	c.options.WordBoost = slices.Clone(c.options.WordBoost)
+	c.options.AddWordBoost(words...)
+}
+
+// SetSelection sets the audio track and time range to transcribe
+func (c *Client) SetSelection(sel Selection) {
+	c.selection = sel
+}
+
+// SetWorkDir sets the parent directory for temporary per-job workspaces;
+// the system temp directory is used when it is empty
+func (c *Client) SetWorkDir(dir string) {
+	c.workDir = dir
+}
+
+// SetProgress sets a callback that receives the progress of audio processing
+func (c *Client) SetProgress(fn ProgressFunc) {
+	c.progress = fn
 }
 
-// TranscribeVideo performs video file transcription
-func (c *Client) TranscribeVideo(ctx context.Context, videoPath string) (string, error) {
-	// Extract audio from video
-	audioPath, err := c.extractAudio(videoPath)
+// TranscribeVideo performs transcription of a video or audio file. With a cache set,
+// audio and transcripts of previously processed content are reused.
+func (c *Client) TranscribeVideo(ctx context.Context, videoPath string) (*Result, error) {
+	media, err := c.probe(ctx, videoPath)
 	if err != nil {
-		return "", fmt.Errorf("audio extraction error: %v", err)
+		return nil, err
+	}
+
+	cacheKey := c.transcriptCacheKey(media)
+	if result, ok := c.cachedTranscript(cacheKey); ok {
+		return c.finish(result, media), nil
+	}
+
+	// every job gets its own workspace, so concurrent runs never share files
+	workspace, err := c.newWorkspace()
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(workspace)
+
+	parts, err := c.prepareAudio(ctx, videoPath, workspace, media)
+	if err != nil {
+		return nil, err
 	}
-	defer os.Remove(audioPath)
 
 	// Transcribe audio
-	return c.transcribeAudio(ctx, audioPath)
+	result, err := c.transcribeParts(ctx, parts)
+	if err != nil {
+		return nil, err
+	}
+	c.storeTranscript(cacheKey, result)
+
+	return c.finish(result, media), nil
+}
+
+// probe returns the input metadata. It is informational, so a file ffprobe cannot read
+// yields nil and fails later in extraction with a clearer error.
+func (c *Client) probe(ctx context.Context, path string) (*Media, error) {
+	media, err := ProbeMedia(ctx, path)
+	if err != nil && ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	return media, nil
 }
 
-// extractAudio extracts audio from video file using ffmpeg
-func (c *Client) extractAudio(videoPath string) (string, error) {
-	audioPath := "extracted_audio.mp3"
-	cmd := execCommand("ffmpeg", "-y", "-i", videoPath, "-vn", "-ar", "44.1k", "-ac", "2", "-ab", "128k", "-f", "mp3", audioPath)
+// prepareAudio extracts audio from the input into the workspace and splits it into parts
+// that fit the upload limit, with offsets relative to the original media
+func (c *Client) prepareAudio(ctx context.Context, inputPath, workspace string, media *Media) ([]audioPart, error) {
+	// Extract audio from video, audio files may be used as they are
+	audioPath, offset, err := c.extractAudioCached(ctx, inputPath, workspace, media)
+	if err != nil {
+		return nil, fmt.Errorf("audio extraction error: %w", err)
+	}
+
+	// Split audio that exceeds the upload limit
+	parts, err := c.splitIfTooLarge(ctx, audioPath, workspace)
+	if err != nil {
+		return nil, fmt.Errorf("audio split error: %w", err)
+	}
+	// a start time or trimmed leading silence shifts the audio, keep timestamps relative to the original media
+	for i := range parts {
+		parts[i].offset += offset
+	}
+	return parts, nil
+}
 
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
+// finish fills in how the result was produced
+func (c *Client) finish(result *Result, media *Media) *Result {
+	options := c.options
+	result.Profile = c.profile.Name
+	result.Options = &options
+	result.Media = media
+	return result
+}
 
-	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("ffmpeg error: %v, stderr: %s", err, stderr.String())
+// newWorkspace creates a unique temporary directory for a single transcription job
+func (c *Client) newWorkspace() (string, error) {
+	if c.workDir != "" {
+		if err := os.MkdirAll(c.workDir, 0o750); err != nil {
+			return "", fmt.Errorf("create audio cache directory: %w", err)
+		}
+	}
+	dir, err := os.MkdirTemp(c.workDir, "transcribe-*")
+	if err != nil {
+		return "", fmt.Errorf("create workspace: %w", err)
 	}
-	return audioPath, nil
+	return dir, nil
+}
+
+// extractAudio extracts the selected audio track from the input into the workspace using ffmpeg,
+// preprocessed according to the client profile. Audio files that need no processing are
+// returned as they are. It also returns the position of the extracted audio in the
+// original media, which is non-zero when a start time is selected or leading silence was trimmed.
+func (c *Client) extractAudio(ctx context.Context, inputPath, workspace string) (string, time.Duration, error) {
+	audioPath := filepath.Join(workspace, "audio"+c.profile.Ext)
+
+	// without a selection the probe is only needed for progress reporting and trimming,
+	// so extraction goes on when it fails
+	info, err := probeMedia(ctx, inputPath)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return "", 0, ctxErr
+		}
+		if c.selection != (Selection{}) {
+			return "", 0, err
+		}
+		info = &mediaInfo{}
+	}
+
+	stream := -1
+	if len(info.Streams) > 0 {
+		if stream, err = info.selectStream(c.selection); err != nil {
+			return "", 0, err
+		}
+		if info.isAudioFile() && c.profile.passthrough() && c.selection == (Selection{}) {
+			return inputPath, 0, nil
+		}
+	}
+
+	start, end := c.selection.Start, info.Duration
+	if c.selection.End > 0 && (end == 0 || c.selection.End < end) {
+		end = c.selection.End
+	}
+	if end > 0 && start >= end {
+		return "", 0, fmt.Errorf("start %s is not before end %s", start, end)
+	}
+
+	var mapArgs []string
+	if stream >= 0 {
+		mapArgs = []string{"-map", fmt.Sprintf("0:%d", stream)}
+	}
+
+	if c.profile.TrimSilence && end > 0 {
+		args := append(inputArgs(inputPath, start, end, info.Duration), "-vn")
+		args = append(append(args, mapArgs...), "-af", "silencedetect=noise="+silenceNoise+":d="+silenceMinLength, "-f", "null", "-")
+		logOutput, err := c.runFFmpeg(ctx, "detecting silence", end-start, args...)
+		if err != nil {
+			return "", 0, err
+		}
+		speechStart, speechEnd := speechBounds(end-start, parseSilences(logOutput))
+		start, end = start+speechStart, start+speechEnd
+	}
+
+	args := append(inputArgs(inputPath, start, end, info.Duration), mapArgs...)
+	args = append(args, c.profile.outputArgs(audioPath)...)
+	if _, err := c.runFFmpeg(ctx, "extracting audio", max(end-start, 0), args...); err != nil {
+		return "", 0, err
+	}
+	return audioPath, start, nil
+}
+
+// inputArgs returns ffmpeg input options reading the range from start to end of the input.
+// An end of zero or at the input duration reads to the end.
+func inputArgs(path string, start, end, duration time.Duration) []string {
+	var args []string
+	if start > 0 {
+		args = append(args, "-ss", formatSeconds(start))
+	}
+	args = append(args, "-i", path)
+	if end > 0 && (duration == 0 || end < duration) {
+		args = append(args, "-t", formatSeconds(end-start))
+	}
+	return args
 }
 
 // transcribeAudio performs transcription using AssemblyAI API
-func (c *Client) transcribeAudio(ctx context.Context, audioPath string) (string, error) {
-	client := assemblyai.NewClient(c.apiKey)
+func (c *Client) transcribeAudio(ctx context.Context, audioPath string) (*Result, error) {
+	client := c.apiClient()
+
+	transcriptID, err := c.submitAudio(ctx, client, audioPath)
+	if err != nil {
+		return nil, err
+	}
 
+	// Wait for transcription completion
+	transcript, err := client.Transcripts.Wait(ctx, transcriptID)
+	if err != nil {
+		return nil, fmt.Errorf("transcription wait error: %v", err)
+	}
+	if transcript.Status == assemblyai.TranscriptStatusError {
+		return nil, fmt.Errorf("transcription failed: %s", assemblyai.ToString(transcript.Error))
+	}
+
+	return resultFromTranscript(transcript), nil
+}
+
+// submitAudio uploads an audio file and starts its transcription without waiting for it
+func (c *Client) submitAudio(ctx context.Context, client *assemblyai.Client, audioPath string) (string, error) {
 	file, err := os.Open(filepath.Clean(audioPath))
 	if err != nil {
 		return "", fmt.Errorf("file open error: %v", err)
@@ -77,16 +327,84 @@ func (c *Client) transcribeAudio(ctx context.Context, audioPath string) (string,
 	}
 
 	// Start transcription
-	transcript, err := client.Transcripts.TranscribeFromURL(ctx, audioURL, nil)
+	transcript, err := client.Transcripts.SubmitFromURL(ctx, audioURL, c.options.params())
 	if err != nil {
 		return "", fmt.Errorf("transcription start error: %v", err)
 	}
+	return assemblyai.ToString(transcript.ID), nil
+}
 
-	// Wait for transcription completion
-	transcript, err = client.Transcripts.Wait(ctx, assemblyai.ToString(transcript.ID))
-	if err != nil {
-		return "", fmt.Errorf("transcription wait error: %v", err)
+// apiClient creates an AssemblyAI API client
+func (c *Client) apiClient() *assemblyai.Client {
+	opts := []assemblyai.ClientOption{assemblyai.WithAPIKey(c.apiKey)}
+	if c.baseURL != "" {
+		opts = append(opts, assemblyai.WithBaseURL(c.baseURL))
+	}
+	return assemblyai.NewClientWithOptions(opts...)
+}
+
+// resultFromTranscript converts a completed AssemblyAI transcript
+func resultFromTranscript(transcript assemblyai.Transcript) *Result {
+	words := buildWords(transcript.Words)
+	return &Result{
+		Text:     assemblyai.ToString(transcript.Text),
+		Segments: buildSegments(words),
+		Words:    words,
+		Language: string(transcript.LanguageCode),
+	}
+}
+
+// buildWords converts AssemblyAI words, dropping empty ones
+func buildWords(words []assemblyai.TranscriptWord) []Word {
+	var result []Word
+	for _, w := range words {
+		text := strings.TrimSpace(assemblyai.ToString(w.Text))
+		if text == "" {
+			continue
+		}
+		result = append(result, Word{
+			Start:      assemblyai.ToInt64(w.Start),
+			End:        assemblyai.ToInt64(w.End),
+			Text:       text,
+			Confidence: assemblyai.ToFloat64(w.Confidence),
+		})
+	}
+	return result
+}
+
+// buildSegments groups words into sentence-level segments, splitting after
+// sentence-ending punctuation. The segment confidence is the average of its words.
+func buildSegments(words []Word) []Segment {
+	var segments []Segment
+	var current []string
+	var start, end int64
+	var confidence float64
+
+	flush := func() {
+		segments = append(segments, Segment{
+			Start:      start,
+			End:        end,
+			Text:       strings.Join(current, " "),
+			Confidence: confidence / float64(len(current)),
+		})
+		current, confidence = current[:0], 0
+	}
+
+	for _, w := range words {
+		if len(current) == 0 {
+			start = w.Start
+		}
+		current = append(current, w.Text)
+		end = w.End
+		confidence += w.Confidence
+
+		if strings.HasSuffix(w.Text, ".") || strings.HasSuffix(w.Text, "?") || strings.HasSuffix(w.Text, "!") {
+			flush()
+		}
+	}
+	if len(current) > 0 {
+		flush()
 	}
 
-	return assemblyai.ToString(transcript.Text), nil
+	return segments
 }
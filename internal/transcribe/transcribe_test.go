@@ -1,27 +1,266 @@
 package transcribe
 
 import (
+	"context"
+	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	assemblyai "github.com/AssemblyAI/assemblyai-go-sdk"
 	"github.com/stretchr/testify/assert"
 )
 
+const videoProbe = `{"format": {"format_name": "mov,mp4,m4a,3gp,3g2,mj2", "duration": "%s"},
+"streams": [{"index": 0, "codec_type": "video"}, {"index": 1, "codec_type": "audio", "tags": {"language": "eng"}},
+{"index": 2, "codec_type": "audio", "tags": {"language": "ger"}}]}`
+
+// fakeCommand returns a shell command that prints output and exits with the given code
+func fakeCommand(ctx context.Context, output string, code int) *exec.Cmd {
+	return exec.CommandContext(ctx, "sh", "-c", fmt.Sprintf(`printf '%%s' "$0"; exit %d`, code), output)
+}
+
 func TestExtractAudio(t *testing.T) {
-	// Mock exec.Command
-	execCommand = func(name string, arg ...string) *exec.Cmd {
+	workspace := t.TempDir()
+	// Mock exec.CommandContext
+	execCommand = func(ctx context.Context, name string, arg ...string) *exec.Cmd {
+		if name == "ffprobe" {
+			return fakeCommand(ctx, fmt.Sprintf(videoProbe, "10.000000"), 0)
+		}
 		assert.Equal(t, "ffmpeg", name)
 		assert.Equal(t, []string{
-			"-y", "-i", "test.mp4",
+			"-y", "-nostats", "-progress", "pipe:1",
+			"-i", "test.mp4",
 			"-vn", "-ar", "44.1k",
 			"-ac", "2", "-ab", "128k",
-			"-f", "mp3", "extracted_audio.mp3",
+			"-f", "mp3", filepath.Join(workspace, "audio.mp3"),
 		}, arg)
-		return exec.Command("echo", "mocked")
+		return fakeCommand(ctx, "out_time_us=5000000\nprogress=continue\nout_time_us=10000000\nprogress=end\n", 0)
+	}
+	defer func() { execCommand = exec.CommandContext }()
+
+	var reported []float64
+	client := New("test_key", 100)
+	client.SetProgress(func(stage string, percent float64) {
+		assert.Equal(t, "extracting audio", stage)
+		reported = append(reported, percent)
+	})
+	audioPath, offset, err := client.extractAudio(context.Background(), "test.mp4", workspace)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(workspace, "audio.mp3"), audioPath)
+	assert.Equal(t, time.Duration(0), offset)
+	assert.Equal(t, []float64{50, 100, 100}, reported)
+}
+
+func TestExtractAudio_SpeechProfile(t *testing.T) {
+	workspace := t.TempDir()
+	var extractArgs []string
+	execCommand = func(ctx context.Context, name string, arg ...string) *exec.Cmd {
+		if name == "ffprobe" {
+			return fakeCommand(ctx, fmt.Sprintf(videoProbe, "60.0"), 0)
+		}
+		if arg[len(arg)-1] == "-" {
+			return exec.CommandContext(ctx, "sh", "-c",
+				`echo "[silencedetect @ 0x1] silence_start: 0" >&2; `+
+					`echo "[silencedetect @ 0x1] silence_end: 3.25 | silence_duration: 3.25" >&2; `+
+					`echo "[silencedetect @ 0x1] silence_start: 55" >&2; `+
+					`echo "[silencedetect @ 0x1] silence_end: 60 | silence_duration: 5" >&2`)
+		}
+		extractArgs = arg
+		return fakeCommand(ctx, "", 0)
+	}
+	defer func() { execCommand = exec.CommandContext }()
+
+	profile, err := LookupProfile("speech")
+	assert.NoError(t, err)
+	client := New("test_key", 100)
+	client.SetProfile(profile)
+
+	audioPath, offset, err := client.extractAudio(context.Background(), "test.mp4", workspace)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(workspace, "audio.ogg"), audioPath)
+	assert.Equal(t, 3*time.Second, offset)
+	assert.Equal(t, []string{
+		"-y", "-nostats", "-progress", "pipe:1",
+		"-ss", "3.000", "-i", "test.mp4", "-t", "52.250",
+		"-vn", "-ar", "16k", "-ac", "1", "-c:a", "libopus", "-ab", "32k",
+		"-af", "loudnorm=I=-16:TP=-1.5:LRA=11",
+		"-f", "ogg", audioPath,
+	}, extractArgs)
+}
+
+func TestExtractAudio_Selection(t *testing.T) {
+	workspace := t.TempDir()
+	var extractArgs []string
+	execCommand = func(ctx context.Context, name string, arg ...string) *exec.Cmd {
+		if name == "ffprobe" {
+			return fakeCommand(ctx, fmt.Sprintf(videoProbe, "600.0"), 0)
+		}
+		extractArgs = arg
+		return fakeCommand(ctx, "", 0)
+	}
+	defer func() { execCommand = exec.CommandContext }()
+
+	client := New("test_key", 100)
+	client.SetSelection(Selection{Language: "GER", Start: 90 * time.Second, End: 2 * time.Minute})
+
+	audioPath, offset, err := client.extractAudio(context.Background(), "test.mp4", workspace)
+	assert.NoError(t, err)
+	assert.Equal(t, 90*time.Second, offset)
+	assert.Equal(t, []string{
+		"-y", "-nostats", "-progress", "pipe:1",
+		"-ss", "90.000", "-i", "test.mp4", "-t", "30.000", "-map", "0:2",
+		"-vn", "-ar", "44.1k", "-ac", "2", "-ab", "128k", "-f", "mp3", audioPath,
+	}, extractArgs)
+
+	client.SetSelection(Selection{Track: 3})
+	_, _, err = client.extractAudio(context.Background(), "test.mp4", workspace)
+	assert.EqualError(t, err, "audio track 3 not found, input has 2")
+
+	client.SetSelection(Selection{Start: 11 * time.Minute})
+	_, _, err = client.extractAudio(context.Background(), "test.mp4", workspace)
+	assert.EqualError(t, err, "start 11m0s is not before end 10m0s")
+}
+
+func TestExtractAudio_AudioFile(t *testing.T) {
+	execCommand = func(ctx context.Context, name string, arg ...string) *exec.Cmd {
+		if name == "ffprobe" {
+			return fakeCommand(ctx, `{"format": {"format_name": "mp3", "duration": "30.0"}, "streams": [
+{"index": 0, "codec_type": "audio", "codec_name": "mp3"},
+{"index": 1, "codec_type": "video", "codec_name": "mjpeg", "disposition": {"attached_pic": 1}}]}`, 0)
+		}
+		t.Fatalf("unexpected %s call", name)
+		return nil
+	}
+	defer func() { execCommand = exec.CommandContext }()
+
+	client := New("test_key", 100)
+	audioPath, offset, err := client.extractAudio(context.Background(), "song.mp3", t.TempDir())
+	assert.NoError(t, err)
+	assert.Equal(t, "song.mp3", audioPath)
+	assert.Equal(t, time.Duration(0), offset)
+}
+
+func TestExtractAudio_Cancelled(t *testing.T) {
+	execCommand = func(ctx context.Context, name string, arg ...string) *exec.Cmd {
+		if name == "ffprobe" {
+			return fakeCommand(ctx, "10", 0)
+		}
+		return exec.CommandContext(ctx, "sleep", "10")
+	}
+	defer func() { execCommand = exec.CommandContext }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	client := New("test_key", 100)
+	_, _, err := client.extractAudio(ctx, "test.mp4", t.TempDir())
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestParseProgress(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		total  time.Duration
+		want   []float64
+	}{
+		{
+			name:   "microseconds",
+			output: "frame=1\nout_time_us=2500000\nprogress=continue\n",
+			total:  10 * time.Second,
+			want:   []float64{25},
+		},
+		{
+			name:   "clamped to 100",
+			output: "out_time_ms=12000000\n",
+			total:  10 * time.Second,
+			want:   []float64{100},
+		},
+		{
+			name:   "unknown duration reports only the end",
+			output: "out_time_us=2500000\nout_time_us=N/A\nprogress=end\n",
+			want:   []float64{100},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []float64
+			parseProgress(strings.NewReader(tt.output), tt.total, func(p float64) { got = append(got, p) })
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTranscribeVideo_RemovesWorkspace(t *testing.T) {
+	workDir := t.TempDir()
+	var workspace string
+	execCommand = func(ctx context.Context, name string, arg ...string) *exec.Cmd {
+		if name == "ffprobe" {
+			return fakeCommand(ctx, "", 1)
+		}
+		workspace = filepath.Dir(arg[len(arg)-1])
+		return fakeCommand(ctx, "", 1)
 	}
-	defer func() { execCommand = exec.Command }()
+	defer func() { execCommand = exec.CommandContext }()
+
+	client := New("test_key", 100)
+	client.SetWorkDir(workDir)
+
+	_, err := client.TranscribeVideo(context.Background(), "test.mp4")
+	assert.Error(t, err)
+	assert.Equal(t, workDir, filepath.Dir(workspace))
+	assert.NoDirExists(t, workspace)
+
+	entries, err := os.ReadDir(workDir)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
 
+func TestTranscribeVideo_ConcurrentWorkspaces(t *testing.T) {
 	client := New("test_key", 100)
-	_, err := client.extractAudio("test.mp4")
+	client.SetWorkDir(t.TempDir())
+
+	first, err := client.newWorkspace()
+	assert.NoError(t, err)
+	second, err := client.newWorkspace()
 	assert.NoError(t, err)
+	assert.NotEqual(t, first, second)
+}
+
+func TestBuildSegments(t *testing.T) {
+	word := func(text string, start, end int64, confidence float64) assemblyai.TranscriptWord {
+		return assemblyai.TranscriptWord{
+			Text:       assemblyai.String(text),
+			Start:      assemblyai.Int64(start),
+			End:        assemblyai.Int64(end),
+			Confidence: assemblyai.Float64(confidence),
+		}
+	}
+
+	words := buildWords([]assemblyai.TranscriptWord{
+		word("Hello", 0, 400, 0.9),
+		word("world.", 450, 900, 0.5),
+		word(" ", 950, 1000, 0),
+		word("How", 1200, 1400, 1),
+		word("are", 1450, 1600, 1),
+		word("you?", 1650, 2000, 0.7),
+		word("Fine", 2500, 2900, 0.8),
+	})
+	assert.Len(t, words, 6)
+	assert.Equal(t, Word{Start: 450, End: 900, Text: "world.", Confidence: 0.5}, words[1])
+
+	segments := buildSegments(words)
+	assert.Equal(t, []Segment{
+		{Start: 0, End: 900, Text: "Hello world.", Confidence: 0.7},
+		{Start: 1200, End: 2000, Text: "How are you?", Confidence: 0.9},
+		{Start: 2500, End: 2900, Text: "Fine", Confidence: 0.8},
+	}, segments)
+	assert.Empty(t, buildSegments(nil))
 }
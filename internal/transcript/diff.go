@@ -0,0 +1,192 @@
+package transcript
+
+import "strings"
+
+// diffContext is the number of unchanged words shown around a change
+const diffContext = 5
+
+// maxDiffEdits bounds the work of the word diff; texts that differ in more words are
+// shown as fully replaced
+const maxDiffEdits = 1000
+
+type diffKind int
+
+const (
+	diffEqual diffKind = iota
+	diffDeleted
+	diffInserted
+)
+
+type diffOp struct {
+	kind diffKind
+	word string
+}
+
+// WordDiff compares two texts word by word, ignoring whitespace, in the style of
+// git's word diff: removed words are shown as [-words-] and added ones as {+words+},
+// with a few words of context. Each group of nearby changes is on a line of its own.
+// The result is empty when the texts have the same words.
+func WordDiff(before, after string) string {
+	ops := diffWords(strings.Fields(before), strings.Fields(after))
+
+	var hunks [][2]int
+	for i, op := range ops {
+		if op.kind == diffEqual {
+			continue
+		}
+		start, end := max(i-diffContext, 0), min(i+1+diffContext, len(ops))
+		if n := len(hunks); n > 0 && start <= hunks[n-1][1] {
+			hunks[n-1][1] = end
+			continue
+		}
+		hunks = append(hunks, [2]int{start, end})
+	}
+
+	lines := make([]string, 0, len(hunks))
+	for _, h := range hunks {
+		lines = append(lines, renderHunk(ops, h[0], h[1]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderHunk formats ops[start:end], marking truncated context with "..."
+func renderHunk(ops []diffOp, start, end int) string {
+	var (
+		parts             []string
+		deleted, inserted []string
+	)
+	flush := func() {
+		var change string
+		if len(deleted) > 0 {
+			change = "[-" + strings.Join(deleted, " ") + "-]"
+		}
+		if len(inserted) > 0 {
+			change += "{+" + strings.Join(inserted, " ") + "+}"
+		}
+		if change != "" {
+			parts = append(parts, change)
+		}
+		deleted, inserted = nil, nil
+	}
+
+	if start > 0 {
+		parts = append(parts, "...")
+	}
+	for _, op := range ops[start:end] {
+		switch op.kind {
+		case diffDeleted:
+			deleted = append(deleted, op.word)
+		case diffInserted:
+			inserted = append(inserted, op.word)
+		default:
+			flush()
+			parts = append(parts, op.word)
+		}
+	}
+	flush()
+	if end < len(ops) {
+		parts = append(parts, "...")
+	}
+	return strings.Join(parts, " ")
+}
+
+// diffWords returns the edit script turning a into b. Common leading and trailing words
+// are matched directly, the rest with the Myers algorithm.
+func diffWords(a, b []string) []diffOp {
+	var prefix int
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	var suffix int
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b))
+	for _, w := range a[:prefix] {
+		ops = append(ops, diffOp{diffEqual, w})
+	}
+	ops = append(ops, myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, w := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{diffEqual, w})
+	}
+	return ops
+}
+
+// myers finds a shortest edit script between a and b, falling back to replacing all
+// of a with b when they differ in more than maxDiffEdits words
+func myers(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	limit := min(n+m, maxDiffEdits)
+	offset := limit + 1
+	v := make([]int, 2*limit+3)
+	var trace [][]int
+
+	for d := 0; d <= limit; d++ {
+		trace = append(trace, append([]int(nil), v...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(a, b, trace, offset)
+			}
+		}
+	}
+
+	ops := make([]diffOp, 0, n+m)
+	for _, w := range a {
+		ops = append(ops, diffOp{diffDeleted, w})
+	}
+	for _, w := range b {
+		ops = append(ops, diffOp{diffInserted, w})
+	}
+	return ops
+}
+
+// backtrack walks the saved states of myers back from the end to recover the edits
+func backtrack(a, b []string, trace [][]int, offset int) []diffOp {
+	var reversed []diffOp
+	x, y := len(a), len(b)
+	for d := len(trace) - 1; d > 0; d-- {
+		v := trace[d]
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x--
+			y--
+			reversed = append(reversed, diffOp{diffEqual, a[x]})
+		}
+		if x == prevX {
+			reversed = append(reversed, diffOp{diffInserted, b[prevY]})
+		} else {
+			reversed = append(reversed, diffOp{diffDeleted, a[prevX]})
+		}
+		x, y = prevX, prevY
+	}
+	for x > 0 && y > 0 {
+		x--
+		y--
+		reversed = append(reversed, diffOp{diffEqual, a[x]})
+	}
+
+	ops := make([]diffOp, len(reversed))
+	for i, op := range reversed {
+		ops[len(reversed)-1-i] = op
+	}
+	return ops
+}
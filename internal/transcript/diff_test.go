@@ -0,0 +1,48 @@
+package transcript
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWordDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		before string
+		after  string
+		want   string
+	}{
+		{"unchanged", "Hello world.", "Hello  world.\n", ""},
+		{"replaced", "Helo world.", "Hello world.", "[-Helo-]{+Hello+} world."},
+		{"inserted", "We use Kubernetes.", "We now use Kubernetes.", "We {+now+} use Kubernetes."},
+		{"deleted", "It is is done.", "It is done.", "It is [-is-] done."},
+		{"from empty", "", "New text", "{+New text+}"},
+		{
+			"context",
+			"one two three four five six seven eight nine ten",
+			"one two three four five six seven eight nine 10",
+			"... five six seven eight nine [-ten-]{+10+}",
+		},
+		{
+			"separate hunks",
+			"a b c d e f g h i j k l m n o p",
+			"A b c d e f g h i j k l m n o P",
+			"[-a-]{+A+} b c d e f ...\n... k l m n o [-p-]{+P+}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, WordDiff(tt.before, tt.after))
+		})
+	}
+}
+
+func TestWordDiffLargeRewrite(t *testing.T) {
+	before := strings.Repeat("a ", maxDiffEdits)
+	after := strings.Repeat("b ", maxDiffEdits)
+	diff := WordDiff(before, after)
+	assert.True(t, strings.HasPrefix(diff, "[-a a"))
+	assert.True(t, strings.HasSuffix(diff, "b b+}"))
+}
@@ -0,0 +1,154 @@
+package transcript
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"assemblyai-transcriber/internal/database"
+	"assemblyai-transcriber/internal/editor"
+	"assemblyai-transcriber/internal/export"
+)
+
+// ErrUnchanged is returned by Edit when the text was saved without changes
+var ErrUnchanged = errors.New("transcription was not changed")
+
+// Store defines database operations needed for editing transcriptions
+type Store interface {
+	GetTranscriptionRecord(id int64) (*database.Transcription, error)
+	GetSegments(transcriptionID int64) ([]database.Segment, error)
+	ReviseTranscription(id int64, edit database.TranscriptEdit) (*database.Revision, error)
+}
+
+// Service corrects transcriptions in a text editor
+type Service struct {
+	store Store
+	edit  func(path string) error
+}
+
+// New creates a new editing service using the default text editor
+func New(store Store) *Service {
+	return &Service{store: store, edit: editor.Edit}
+}
+
+// SetEditor sets the function that opens a file for editing and returns once it is saved
+func (s *Service) SetEditor(edit func(path string) error) {
+	s.edit = edit
+}
+
+// Edit opens a transcription in the text editor and saves the corrected text as a new
+// revision, marking its translations stale. Transcriptions with segments are edited one
+// segment per line so their timings are kept; with wholeText the plain text is edited
+// instead and the segments are dropped. It returns ErrUnchanged when nothing was changed.
+func (s *Service) Edit(id int64, wholeText bool) (*database.Revision, error) {
+	record, err := s.store.GetTranscriptionRecord(id)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving transcription %d: %w", id, err)
+	}
+	segments, err := s.store.GetSegments(id)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving segments of transcription %d: %w", id, err)
+	}
+
+	var edit database.TranscriptEdit
+	if wholeText || len(segments) == 0 {
+		edited, err := s.editFile(id, record.Text)
+		if err != nil {
+			return nil, err
+		}
+		edit.Text = strings.TrimSpace(edited)
+		if edit.Text == strings.TrimSpace(record.Text) {
+			return nil, ErrUnchanged
+		}
+		edit.DropSegments = len(segments) > 0
+	} else {
+		edited, err := s.editFile(id, formatSegments(record.FileName, segments))
+		if err != nil {
+			return nil, err
+		}
+		texts, err := parseSegments(edited, len(segments))
+		if err != nil {
+			return nil, err
+		}
+		parts := make([]string, 0, len(segments))
+		for i, seg := range segments {
+			if texts[i] != seg.Text {
+				seg.Text = texts[i]
+				edit.Segments = append(edit.Segments, seg)
+			}
+			if texts[i] != "" {
+				parts = append(parts, texts[i])
+			}
+		}
+		if len(edit.Segments) == 0 {
+			return nil, ErrUnchanged
+		}
+		edit.Text = strings.Join(parts, " ")
+	}
+
+	edit.Diff = WordDiff(record.Text, edit.Text)
+	rev, err := s.store.ReviseTranscription(id, edit)
+	if err != nil {
+		return nil, fmt.Errorf("error saving revision of transcription %d: %w", id, err)
+	}
+	return rev, nil
+}
+
+// editFile writes content to a temporary file, opens it in the editor and returns the result
+func (s *Service) editFile(id int64, content string) (string, error) {
+	tmpFile, err := os.CreateTemp("", fmt.Sprintf("transcript-%d-*.txt", id))
+	if err != nil {
+		return "", fmt.Errorf("error creating temp file: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.WriteString(content); err != nil {
+		_ = tmpFile.Close()
+		return "", fmt.Errorf("error writing temp file: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return "", fmt.Errorf("error closing temp file: %w", err)
+	}
+
+	if err := s.edit(tmpFile.Name()); err != nil {
+		return "", err
+	}
+
+	edited, err := os.ReadFile(tmpFile.Name())
+	if err != nil {
+		return "", fmt.Errorf("error reading edited file: %w", err)
+	}
+	return string(edited), nil
+}
+
+// formatSegments renders segments one per line, each prefixed with its start time
+func formatSegments(fileName string, segments []database.Segment) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Correct the transcription of %s, one segment per line.\n", fileName)
+	b.WriteString("# Keep the [time] prefixes and the order of the lines; lines starting with # are ignored.\n")
+	for _, seg := range segments {
+		fmt.Fprintf(&b, "[%s] %s\n", export.FormatDuration(seg.Start), seg.Text)
+	}
+	return b.String()
+}
+
+// parseSegments reads the segment texts back from an edited file in their original order
+func parseSegments(content string, count int) ([]string, error) {
+	var texts []string
+	for n, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		end := strings.Index(line, "]")
+		if !strings.HasPrefix(line, "[") || end < 0 {
+			return nil, fmt.Errorf("line %d: missing [time] prefix: %q", n+1, line)
+		}
+		texts = append(texts, strings.TrimSpace(line[end+1:]))
+	}
+	if len(texts) != count {
+		return nil, fmt.Errorf("expected %d segments, got %d; segments cannot be added or removed", count, len(texts))
+	}
+	return texts, nil
+}
@@ -0,0 +1,109 @@
+package transcript
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"assemblyai-transcriber/internal/database"
+)
+
+type fakeStore struct {
+	record   *database.Transcription
+	segments []database.Segment
+	edits    []database.TranscriptEdit
+}
+
+func (f *fakeStore) GetTranscriptionRecord(int64) (*database.Transcription, error) {
+	return f.record, nil
+}
+
+func (f *fakeStore) GetSegments(int64) ([]database.Segment, error) {
+	return f.segments, nil
+}
+
+func (f *fakeStore) ReviseTranscription(id int64, edit database.TranscriptEdit) (*database.Revision, error) {
+	f.edits = append(f.edits, edit)
+	return &database.Revision{TranscriptionID: id, Revision: len(f.edits) + 1, Text: edit.Text, Diff: edit.Diff}, nil
+}
+
+// replacingEditor returns an editor that replaces old with new in the edited file
+func replacingEditor(t *testing.T, old, new string) func(string) error {
+	return func(path string) error {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		return os.WriteFile(path, []byte(strings.ReplaceAll(string(data), old, new)), 0o600)
+	}
+}
+
+func TestEditSegments(t *testing.T) {
+	store := &fakeStore{
+		record: &database.Transcription{ID: 1, FileName: "talk.mp4", Text: "Helo world. Bye."},
+		segments: []database.Segment{
+			{Index: 0, Start: 0, End: 900, Text: "Helo world."},
+			{Index: 1, Start: 61000, End: 61500, Text: "Bye."},
+		},
+	}
+	svc := New(store)
+	var content string
+	svc.SetEditor(func(path string) error {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		content = string(data)
+		return replacingEditor(t, "Helo", "Hello")(path)
+	})
+
+	rev, err := svc.Edit(1, false)
+	require.NoError(t, err)
+	assert.Equal(t, 2, rev.Revision)
+	assert.Contains(t, content, "[0:00] Helo world.\n[1:01] Bye.\n")
+
+	require.Len(t, store.edits, 1)
+	edit := store.edits[0]
+	assert.Equal(t, "Hello world. Bye.", edit.Text)
+	assert.Equal(t, "[-Helo-]{+Hello+} world. Bye.", edit.Diff)
+	assert.Equal(t, []database.Segment{{Index: 0, Start: 0, End: 900, Text: "Hello world."}}, edit.Segments)
+	assert.False(t, edit.DropSegments)
+}
+
+func TestEditSegmentCountChanged(t *testing.T) {
+	store := &fakeStore{
+		record:   &database.Transcription{ID: 1, Text: "One. Two."},
+		segments: []database.Segment{{Index: 0, Text: "One."}, {Index: 1, Start: 1000, Text: "Two."}},
+	}
+	svc := New(store)
+	svc.SetEditor(replacingEditor(t, "[0:01] Two.", "[0:01] Two.\n[0:02] Three."))
+
+	_, err := svc.Edit(1, false)
+	require.ErrorContains(t, err, "expected 2 segments, got 3")
+	assert.Empty(t, store.edits)
+}
+
+func TestEditWholeText(t *testing.T) {
+	store := &fakeStore{
+		record:   &database.Transcription{ID: 1, Text: "We use kubernetes."},
+		segments: []database.Segment{{Index: 0, End: 900, Text: "We use kubernetes."}},
+	}
+	svc := New(store)
+	svc.SetEditor(replacingEditor(t, "kubernetes", "Kubernetes"))
+
+	_, err := svc.Edit(1, true)
+	require.NoError(t, err)
+	require.Len(t, store.edits, 1)
+	assert.Equal(t, "We use Kubernetes.", store.edits[0].Text)
+	assert.True(t, store.edits[0].DropSegments)
+	assert.Empty(t, store.edits[0].Segments)
+}
+
+func TestEditUnchanged(t *testing.T) {
+	store := &fakeStore{record: &database.Transcription{ID: 1, Text: "Nothing to fix."}}
+	svc := New(store)
+	svc.SetEditor(func(string) error { return nil })
+
+	_, err := svc.Edit(1, false)
+	require.ErrorIs(t, err, ErrUnchanged)
+	assert.Empty(t, store.edits)
+}
@@ -1,27 +1,43 @@
 package translation
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"assemblyai-transcriber/internal/database"
+	"assemblyai-transcriber/internal/language"
 	"assemblyai-transcriber/internal/openrouter"
 	"assemblyai-transcriber/internal/terms"
 )
 
-// Database defines database operations needed for translation
+// DefaultTargetLanguage is the language transcriptions are translated to by default
+const DefaultTargetLanguage = "ru"
+
+// fallbackSourceLanguage is assumed when the language of a transcription is unknown
+// and cannot be detected
+const fallbackSourceLanguage = "en"
+
+// ErrSameLanguage is returned by ProcessTranscription when the transcription is already
+// in the target language, so there is nothing to translate
+var ErrSameLanguage = errors.New("transcription is already in the target language")
+
+// Database defines database operations needed for translation, a subset of
+// interfaces.Repository
 type Database interface {
 	GetTranscription(int64) (string, error)
+	GetTranscriptionRecord(int64) (*database.Transcription, error)
 	GetTranslation(int64) (string, error)
 	SaveTerm(string, string) error
-	SaveTranslation(int64, string) error
+	InsertTranslation(*database.Translation) (int64, error)
 }
 
 // OpenRouter defines operations for text analysis and translation
 type OpenRouter interface {
 	AnalyzeTerms(string) (*openrouter.TermAnalysis, error)
-	TranslateText(string, []string) (string, error)
+	TranslateText(text string, terms []string, source, target string) (string, error)
 }
 
 // Service manages the translation workflow
@@ -29,6 +45,7 @@ type Service struct {
 	db          Database
 	openrouter  OpenRouter
 	termManager *terms.TermManager
+	target      string
 }
 
 // New creates a new translation service
@@ -37,24 +54,42 @@ func New(db Database, openRouterClient OpenRouter) *Service {
 		db:          db,
 		openrouter:  openRouterClient,
 		termManager: terms.New(),
+		target:      DefaultTargetLanguage,
 	}
 }
 
-// ProcessTranscription analyzes and translates a transcription
+// SetTargetLanguage sets the language code transcriptions are translated to
+func (s *Service) SetTargetLanguage(code string) {
+	s.target = code
+}
+
+// SetTermPolicy sets how found terms are resolved; the default asks the user interactively
+func (s *Service) SetTermPolicy(policy terms.Policy) {
+	s.termManager.SetPolicy(policy)
+}
+
+// ProcessTranscription analyzes and translates a transcription from its stored or detected
+// language. It returns ErrSameLanguage without translating when that is the target language.
 func (s *Service) ProcessTranscription(transcriptionID int64) error {
 	// get the transcription text
-	text, err := s.db.GetTranscription(transcriptionID)
+	record, err := s.db.GetTranscriptionRecord(transcriptionID)
 	if err != nil {
 		return fmt.Errorf("error retrieving transcription: %w", err)
 	}
+	text := record.Text
+
+	source := sourceLanguage(record)
+	if language.Same(source, s.target) {
+		return fmt.Errorf("%w (%s)", ErrSameLanguage, language.Name(source))
+	}
 
 	// analyze terms
 	if err := s.analyzeTerms(text); err != nil {
 		return fmt.Errorf("error analyzing terms: %w", err)
 	}
 
-	// process terms interactively
-	if err := s.termManager.ProcessTermsInteractive(); err != nil {
+	// resolve terms according to the policy (interactive by default)
+	if err := s.termManager.ProcessTerms(); err != nil {
 		return fmt.Errorf("error processing terms: %w", err)
 	}
 
@@ -64,13 +99,18 @@ func (s *Service) ProcessTranscription(transcriptionID int64) error {
 	}
 
 	// translate text
-	translatedText, err := s.translateText(text)
+	translatedText, err := s.translateText(text, source)
 	if err != nil {
 		return fmt.Errorf("error translating text: %w", err)
 	}
 
 	// save translation to database
-	if err := s.db.SaveTranslation(transcriptionID, translatedText); err != nil {
+	if _, err := s.db.InsertTranslation(&database.Translation{
+		TranscriptionID: transcriptionID,
+		TranslatedText:  translatedText,
+		Language:        s.target,
+		Model:           openrouter.Model,
+	}); err != nil {
 		return fmt.Errorf("error saving translation: %w", err)
 	}
 
@@ -117,15 +157,28 @@ func (s *Service) saveTerms() error {
 	return nil
 }
 
-// translateText translates the text using OpenRouter
-func (s *Service) translateText(text string) (string, error) {
-	fmt.Println("Translating text...")
+// sourceLanguage returns the language of a transcription, detecting it from the text
+// for transcriptions saved without one
+func sourceLanguage(record *database.Transcription) string {
+	if record.Language != "" {
+		return record.Language
+	}
+	if lang := language.Detect(record.Text); lang != "" {
+		return lang
+	}
+	return fallbackSourceLanguage
+}
+
+// translateText translates the text from the source language using OpenRouter
+func (s *Service) translateText(text, source string) (string, error) {
+	fmt.Printf("Translating text from %s to %s...\n", language.Name(source), language.Name(s.target))
 
 	// get list of untranslatable terms
 	untranslatableTerms := s.termManager.GetUntranslatableTerms()
 
 	// translate text
-	translatedText, err := s.openrouter.TranslateText(text, untranslatableTerms)
+	translatedText, err := s.openrouter.TranslateText(text, untranslatableTerms,
+		language.Name(source), language.Name(s.target))
 	if err != nil {
 		return "", fmt.Errorf("error translating text: %w", err)
 	}
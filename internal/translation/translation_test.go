@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"assemblyai-transcriber/internal/database"
+	"assemblyai-transcriber/internal/mocks"
 	"assemblyai-transcriber/internal/openrouter"
 
 	"github.com/stretchr/testify/require"
@@ -32,40 +33,17 @@ func TestGenerateFileName(t *testing.T) {
 	})
 }
 
-type mockDB struct {
-	getTranscriptionFunc func(int64) (string, error)
-	getTranslationFunc   func(int64) (string, error)
-	saveTermFunc         func(string, string) error
-	saveTranslationFunc  func(int64, string) error
-}
-
-func (m *mockDB) GetTranscription(id int64) (string, error) {
-	return m.getTranscriptionFunc(id)
-}
-
-func (m *mockDB) GetTranslation(id int64) (string, error) {
-	return m.getTranslationFunc(id)
-}
-
-func (m *mockDB) SaveTerm(term, desc string) error {
-	return m.saveTermFunc(term, desc)
-}
-
-func (m *mockDB) SaveTranslation(id int64, text string) error {
-	return m.saveTranslationFunc(id, text)
-}
-
 type mockOpenRouter struct {
 	analyzeTermsFunc  func(string) (*openrouter.TermAnalysis, error)
-	translateTextFunc func(string, []string) (string, error)
+	translateTextFunc func(string, []string, string, string) (string, error)
 }
 
 func (m *mockOpenRouter) AnalyzeTerms(text string) (*openrouter.TermAnalysis, error) {
 	return m.analyzeTermsFunc(text)
 }
 
-func (m *mockOpenRouter) TranslateText(text string, terms []string) (string, error) {
-	return m.translateTextFunc(text, terms)
+func (m *mockOpenRouter) TranslateText(text string, terms []string, source, target string) (string, error) {
+	return m.translateTextFunc(text, terms, source, target)
 }
 
 func TestNew(t *testing.T) {
@@ -79,18 +57,18 @@ func TestNew(t *testing.T) {
 }
 
 func TestProcessTranscription_Success(t *testing.T) {
-	db := &mockDB{
-		getTranscriptionFunc: func(id int64) (string, error) {
-			return "test text", nil
+	db := &mocks.RepositoryMock{
+		GetTranscriptionRecordFunc: func(id int64) (*database.Transcription, error) {
+			return &database.Transcription{ID: id, Text: "test text", Language: "de"}, nil
 		},
-		getTranslationFunc: func(id int64) (string, error) {
+		GetTranslationFunc: func(id int64) (string, error) {
 			return "translated text", nil
 		},
-		saveTermFunc: func(term, desc string) error {
+		SaveTermFunc: func(term, desc string) error {
 			return nil
 		},
-		saveTranslationFunc: func(id int64, text string) error {
-			return nil
+		InsertTranslationFunc: func(tr *database.Translation) (int64, error) {
+			return 1, nil
 		},
 	}
 
@@ -98,7 +76,9 @@ func TestProcessTranscription_Success(t *testing.T) {
 		analyzeTermsFunc: func(text string) (*openrouter.TermAnalysis, error) {
 			return &openrouter.TermAnalysis{}, nil
 		},
-		translateTextFunc: func(text string, terms []string) (string, error) {
+		translateTextFunc: func(text string, terms []string, source, target string) (string, error) {
+			require.Equal(t, "German", source)
+			require.Equal(t, "Russian", target)
 			return "translated text", nil
 		},
 	}
@@ -106,14 +86,61 @@ func TestProcessTranscription_Success(t *testing.T) {
 	tr := New(db, or)
 	err := tr.ProcessTranscription(1)
 	require.NoError(t, err)
+	require.Len(t, db.InsertTranslationCalls(), 1)
+	saved := db.InsertTranslationCalls()[0].T
+	require.Equal(t, int64(1), saved.TranscriptionID)
+	require.Equal(t, "ru", saved.Language)
+	require.Equal(t, openrouter.Model, saved.Model)
+}
+
+func TestProcessTranscription_DetectsLanguage(t *testing.T) {
+	var saved bool
+	db := &mocks.RepositoryMock{
+		GetTranscriptionRecordFunc: func(id int64) (*database.Transcription, error) {
+			return &database.Transcription{ID: id, Text: "This is the talk and it was about the release."}, nil
+		},
+		SaveTermFunc: func(term, desc string) error { return nil },
+		InsertTranslationFunc: func(tr *database.Translation) (int64, error) {
+			saved = true
+			return 1, nil
+		},
+	}
+	or := &mockOpenRouter{
+		analyzeTermsFunc: func(text string) (*openrouter.TermAnalysis, error) {
+			return &openrouter.TermAnalysis{}, nil
+		},
+		translateTextFunc: func(text string, terms []string, source, target string) (string, error) {
+			require.Equal(t, "English", source)
+			require.Equal(t, "German", target)
+			return "translated text", nil
+		},
+	}
+
+	tr := New(db, or)
+	tr.SetTargetLanguage("de")
+	require.NoError(t, tr.ProcessTranscription(1))
+	require.True(t, saved)
+}
+
+func TestProcessTranscription_SameLanguage(t *testing.T) {
+	db := &mocks.RepositoryMock{
+		GetTranscriptionRecordFunc: func(id int64) (*database.Transcription, error) {
+			return &database.Transcription{ID: id, Text: "Привет", Language: "ru"}, nil
+		},
+	}
+
+	tr := New(db, &mockOpenRouter{})
+	err := tr.ProcessTranscription(1)
+	require.ErrorIs(t, err, ErrSameLanguage)
+	require.EqualError(t, err, "transcription is already in the target language (Russian)")
 }
 
 func TestProcessTranscription_GetTranscriptionError(t *testing.T) {
-	db := &mockDB{
-		getTranscriptionFunc: func(id int64) (string, error) {
-			return "", fmt.Errorf("db error")
+	db := &mocks.RepositoryMock{
+		GetTranscriptionRecordFunc: func(id int64) (*database.Transcription, error) {
+			return nil, fmt.Errorf("db error")
 		},
-		getTranslationFunc: func(id int64) (string, error) {
+		GetTranslationFunc: func(id int64) (string, error) {
 			return "", nil
 		},
 	}
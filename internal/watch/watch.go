@@ -0,0 +1,238 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"assemblyai-transcriber/internal/savetodb"
+)
+
+const (
+	defaultInterval     = 10 * time.Second
+	defaultStableChecks = 2
+	doneDirName         = "done"
+	failedDirName       = "failed"
+	pendingDirName      = "pending"
+)
+
+// ProcessFunc handles a single file that has finished writing. A non-zero jobID means the
+// file was submitted to a job that completes later, see Watcher.Complete.
+type ProcessFunc func(ctx context.Context, path string) (jobID int64, err error)
+
+// Options configures the watcher.
+type Options struct {
+	Dir          string
+	DoneDir      string // defaults to Dir/done
+	FailedDir    string // defaults to Dir/failed
+	PendingDir   string // defaults to Dir/pending, holds submitted files until their job completes
+	Extensions   []string
+	Interval     time.Duration
+	StableChecks int // consecutive polls with unchanged size and mtime before a file is processed
+}
+
+// Result describes the outcome of processing a single file.
+type Result struct {
+	Path    string // original path of the file
+	MovedTo string // path after moving the file to the done, failed or pending directory
+	JobID   int64  // set when the file waits in the pending directory for this job
+	Err     error
+}
+
+// fileState tracks a file between polls to detect when it stops changing
+type fileState struct {
+	size    int64
+	modTime time.Time
+	stable  int
+}
+
+// Watcher polls a directory and processes media files once they stop growing.
+type Watcher struct {
+	opts    Options
+	process ProcessFunc
+	files   map[string]*fileState
+}
+
+// New creates a new watcher, filling in defaults for unset options
+func New(opts Options, process ProcessFunc) *Watcher {
+	if opts.DoneDir == "" {
+		opts.DoneDir = filepath.Join(opts.Dir, doneDirName)
+	}
+	if opts.FailedDir == "" {
+		opts.FailedDir = filepath.Join(opts.Dir, failedDirName)
+	}
+	if opts.PendingDir == "" {
+		opts.PendingDir = filepath.Join(opts.Dir, pendingDirName)
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = defaultInterval
+	}
+	if opts.StableChecks < 1 {
+		opts.StableChecks = defaultStableChecks
+	}
+	return &Watcher{
+		opts:    opts,
+		process: process,
+		files:   map[string]*fileState{},
+	}
+}
+
+// Run polls the directory until the context is cancelled, calling onResult for every
+// processed file
+func (w *Watcher) Run(ctx context.Context, onResult func(Result)) error {
+	ticker := time.NewTicker(w.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		results, err := w.Poll(ctx)
+		if err != nil {
+			return err
+		}
+		if onResult != nil {
+			for _, res := range results {
+				onResult(res)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll scans the directory once and processes files whose size and modification time
+// have not changed for the configured number of polls
+func (w *Watcher) Poll(ctx context.Context) ([]Result, error) {
+	paths, err := savetodb.FindMediaFiles(savetodb.BatchOptions{
+		Dir:        w.opts.Dir,
+		Extensions: w.opts.Extensions,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan directory: %w", err)
+	}
+
+	present := make(map[string]bool, len(paths))
+	var ready []string
+	for _, path := range paths {
+		present[path] = true
+		info, err := os.Stat(path)
+		if err != nil {
+			// the file may have been removed between listing and stat
+			continue
+		}
+
+		state, ok := w.files[path]
+		if !ok {
+			w.files[path] = &fileState{size: info.Size(), modTime: info.ModTime()}
+			continue
+		}
+		if state.size != info.Size() || !state.modTime.Equal(info.ModTime()) {
+			state.size, state.modTime, state.stable = info.Size(), info.ModTime(), 0
+			continue
+		}
+		state.stable++
+		if state.stable >= w.opts.StableChecks {
+			ready = append(ready, path)
+		}
+	}
+
+	// forget files that were removed by someone else
+	for path := range w.files {
+		if !present[path] {
+			delete(w.files, path)
+		}
+	}
+
+	results := make([]Result, 0, len(ready))
+	for _, path := range ready {
+		if ctx.Err() != nil {
+			break
+		}
+		results = append(results, w.handle(ctx, path))
+		delete(w.files, path)
+	}
+	return results, nil
+}
+
+// handle processes a single file and moves it to the done or failed directory, or to the
+// pending directory when it was submitted to a job
+func (w *Watcher) handle(ctx context.Context, path string) Result {
+	res := Result{Path: path}
+	res.JobID, res.Err = w.process(ctx, path)
+
+	// leave the file in place when processing was interrupted, it will be retried on restart
+	if res.Err != nil && errors.Is(res.Err, context.Canceled) {
+		return res
+	}
+
+	target, name := w.opts.DoneDir, filepath.Base(path)
+	switch {
+	case res.Err != nil:
+		target, res.JobID = w.opts.FailedDir, 0
+	case res.JobID != 0:
+		target, name = w.opts.PendingDir, pendingName(res.JobID, name)
+	}
+	movedTo, err := moveFile(path, target, name)
+	if err != nil {
+		res.Err = errors.Join(res.Err, fmt.Errorf("move file: %w", err))
+		return res
+	}
+	res.MovedTo = movedTo
+	return res
+}
+
+// Complete moves the file submitted to a job from the pending directory to the done
+// directory, or to the failed directory when err is set. fileName is the name of the file
+// when it was submitted. Jobs submitted by other programs have no pending file, Complete
+// then returns a result without MovedTo. It is safe to call while the watcher runs.
+func (w *Watcher) Complete(jobID int64, fileName string, err error) Result {
+	name := filepath.Base(fileName)
+	res := Result{Path: filepath.Join(w.opts.PendingDir, pendingName(jobID, name)), JobID: jobID, Err: err}
+	if _, statErr := os.Stat(res.Path); errors.Is(statErr, os.ErrNotExist) {
+		return res
+	}
+
+	target := w.opts.DoneDir
+	if err != nil {
+		target = w.opts.FailedDir
+	}
+	movedTo, moveErr := moveFile(res.Path, target, name)
+	if moveErr != nil {
+		res.Err = errors.Join(res.Err, fmt.Errorf("move file: %w", moveErr))
+		return res
+	}
+	res.MovedTo = movedTo
+	return res
+}
+
+// pendingName is the name of a file in the pending directory, prefixed with its job ID so
+// Complete finds it after a restart
+func pendingName(jobID int64, name string) string {
+	return fmt.Sprintf("%d_%s", jobID, name)
+}
+
+// moveFile moves a file into dir under base, adding a timestamp to the name if the target
+// exists
+func moveFile(path, dir, base string) (string, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return "", fmt.Errorf("create directory: %w", err)
+	}
+
+	target := filepath.Join(dir, base)
+	if _, err := os.Stat(target); err == nil {
+		ext := filepath.Ext(base)
+		name := strings.TrimSuffix(base, ext)
+		target = filepath.Join(dir, fmt.Sprintf("%s_%s%s", name, time.Now().Format("20060102-150405"), ext))
+	}
+
+	if err := os.Rename(path, target); err != nil {
+		return "", err
+	}
+	return target, nil
+}
@@ -0,0 +1,150 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatcher_Poll(t *testing.T) {
+	dir := t.TempDir()
+	okPath := filepath.Join(dir, "ok.mp4")
+	badPath := filepath.Join(dir, "bad.mp4")
+	require.NoError(t, os.WriteFile(okPath, []byte("video"), 0o600))
+	require.NoError(t, os.WriteFile(badPath, []byte("video"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("text"), 0o600))
+
+	var processed []string
+	w := New(Options{Dir: dir, StableChecks: 1}, func(ctx context.Context, path string) (int64, error) {
+		processed = append(processed, filepath.Base(path))
+		if filepath.Base(path) == "bad.mp4" {
+			return 0, fmt.Errorf("transcription failed")
+		}
+		return 0, nil
+	})
+
+	// first poll only records the files
+	results, err := w.Poll(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, results)
+
+	// second poll sees unchanged files and processes them
+	results, err = w.Poll(context.Background())
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	require.Equal(t, []string{"bad.mp4", "ok.mp4"}, processed)
+
+	require.Error(t, results[0].Err)
+	require.Equal(t, filepath.Join(dir, "failed", "bad.mp4"), results[0].MovedTo)
+	require.NoError(t, results[1].Err)
+	require.Equal(t, filepath.Join(dir, "done", "ok.mp4"), results[1].MovedTo)
+
+	require.FileExists(t, filepath.Join(dir, "done", "ok.mp4"))
+	require.FileExists(t, filepath.Join(dir, "failed", "bad.mp4"))
+	require.NoFileExists(t, okPath)
+	require.FileExists(t, filepath.Join(dir, "notes.txt"))
+}
+
+func TestWatcher_Poll_WaitsForStableSize(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "growing.mp4")
+	require.NoError(t, os.WriteFile(path, []byte("v"), 0o600))
+
+	calls := 0
+	w := New(Options{Dir: dir, StableChecks: 1}, func(ctx context.Context, path string) (int64, error) {
+		calls++
+		return 0, nil
+	})
+
+	_, err := w.Poll(context.Background())
+	require.NoError(t, err)
+
+	// the file is still being written
+	require.NoError(t, os.WriteFile(path, []byte("video data"), 0o600))
+	results, err := w.Poll(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, results)
+	require.Equal(t, 0, calls)
+
+	results, err = w.Poll(context.Background())
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, 1, calls)
+}
+
+func TestWatcher_Poll_CancelledLeavesFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "video.mp4")
+	require.NoError(t, os.WriteFile(path, []byte("video"), 0o600))
+
+	w := New(Options{Dir: dir, StableChecks: 1}, func(ctx context.Context, path string) (int64, error) {
+		return 0, fmt.Errorf("transcribe: %w", context.Canceled)
+	})
+	_, err := w.Poll(context.Background())
+	require.NoError(t, err)
+	results, err := w.Poll(context.Background())
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.ErrorIs(t, results[0].Err, context.Canceled)
+	require.Empty(t, results[0].MovedTo)
+	require.FileExists(t, path)
+}
+
+func TestWatcher_Complete(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"ok.mp4", "bad.mp4"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("video"), 0o600))
+	}
+
+	jobs := map[string]int64{"bad.mp4": 7, "ok.mp4": 8}
+	w := New(Options{Dir: dir, StableChecks: 1}, func(ctx context.Context, path string) (int64, error) {
+		return jobs[filepath.Base(path)], nil
+	})
+	_, err := w.Poll(context.Background())
+	require.NoError(t, err)
+	results, err := w.Poll(context.Background())
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	// submitted files wait in the pending directory and are not processed again
+	require.Equal(t, int64(7), results[0].JobID)
+	require.Equal(t, filepath.Join(dir, "pending", "7_bad.mp4"), results[0].MovedTo)
+	results, err = w.Poll(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, results)
+
+	// a new watcher finds the files of jobs submitted before a restart
+	w = New(Options{Dir: dir}, nil)
+	res := w.Complete(8, "ok.mp4", nil)
+	require.NoError(t, res.Err)
+	require.Equal(t, filepath.Join(dir, "done", "ok.mp4"), res.MovedTo)
+	res = w.Complete(7, "bad.mp4", fmt.Errorf("transcription failed"))
+	require.Error(t, res.Err)
+	require.Equal(t, filepath.Join(dir, "failed", "bad.mp4"), res.MovedTo)
+	require.NoFileExists(t, filepath.Join(dir, "pending", "7_bad.mp4"))
+
+	// jobs submitted by other programs have no pending file
+	res = w.Complete(9, "other.mp4", nil)
+	require.NoError(t, res.Err)
+	require.Empty(t, res.MovedTo)
+}
+
+func TestMoveFile_ExistingTarget(t *testing.T) {
+	dir := t.TempDir()
+	doneDir := filepath.Join(dir, "done")
+	require.NoError(t, os.MkdirAll(doneDir, 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(doneDir, "video.mp4"), []byte("old"), 0o600))
+
+	path := filepath.Join(dir, "video.mp4")
+	require.NoError(t, os.WriteFile(path, []byte("new"), 0o600))
+
+	target, err := moveFile(path, doneDir, "video.mp4")
+	require.NoError(t, err)
+	require.NotEqual(t, filepath.Join(doneDir, "video.mp4"), target)
+	require.FileExists(t, target)
+	require.FileExists(t, filepath.Join(doneDir, "video.mp4"))
+}
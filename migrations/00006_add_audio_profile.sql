@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE transcriptions ADD COLUMN audio_profile TEXT;
+
+-- +goose Down
+ALTER TABLE transcriptions DROP COLUMN audio_profile;
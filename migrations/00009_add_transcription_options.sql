@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE transcriptions ADD COLUMN transcription_options TEXT;
+
+-- +goose Down
+ALTER TABLE transcriptions DROP COLUMN transcription_options;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE transcriptions ADD COLUMN language TEXT;
+
+-- +goose Down
+ALTER TABLE transcriptions DROP COLUMN language;
@@ -0,0 +1,17 @@
+-- +goose Up
+ALTER TABLE segments ADD COLUMN confidence REAL;
+CREATE TABLE IF NOT EXISTS words (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    transcription_id INTEGER NOT NULL,
+    word_index INTEGER NOT NULL,
+    start_ms INTEGER NOT NULL,
+    end_ms INTEGER NOT NULL,
+    text TEXT NOT NULL,
+    confidence REAL NOT NULL,
+    FOREIGN KEY (transcription_id) REFERENCES transcriptions(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_words_transcription_id ON words(transcription_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS words;
+ALTER TABLE segments DROP COLUMN confidence;
@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS transcription_revisions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    transcription_id INTEGER NOT NULL,
+    revision INTEGER NOT NULL,
+    transcript_text TEXT NOT NULL,
+    diff TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (transcription_id) REFERENCES transcriptions(id) ON DELETE CASCADE,
+    UNIQUE (transcription_id, revision)
+);
+ALTER TABLE translations ADD COLUMN stale INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE translations DROP COLUMN stale;
+DROP TABLE IF EXISTS transcription_revisions;
@@ -0,0 +1,44 @@
+-- +goose Up
+-- transcripts is the unused table of the first schema; keep any rows it still holds
+INSERT INTO transcriptions (file_name, transcript_text, created_at)
+SELECT filename, text, created_at FROM transcripts ORDER BY id;
+DROP TABLE IF EXISTS transcripts;
+
+-- SQLite cannot change a foreign key in place, so translations is rebuilt; translations
+-- of missing transcriptions cannot be reached and are dropped
+CREATE TABLE translations_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    transcription_id INTEGER NOT NULL,
+    translated_text TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    stale INTEGER NOT NULL DEFAULT 0,
+    FOREIGN KEY (transcription_id) REFERENCES transcriptions(id) ON DELETE CASCADE
+);
+INSERT INTO translations_new (id, transcription_id, translated_text, created_at, stale)
+SELECT id, transcription_id, translated_text, created_at, stale FROM translations
+WHERE transcription_id IN (SELECT id FROM transcriptions);
+DROP TABLE translations;
+ALTER TABLE translations_new RENAME TO translations;
+CREATE INDEX IF NOT EXISTS idx_translations_transcription_id ON translations(transcription_id);
+
+-- +goose Down
+-- moved legacy rows stay in transcriptions
+CREATE TABLE translations_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    transcription_id INTEGER,
+    translated_text TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    stale INTEGER NOT NULL DEFAULT 0,
+    FOREIGN KEY (transcription_id) REFERENCES transcriptions(id)
+);
+INSERT INTO translations_old (id, transcription_id, translated_text, created_at, stale)
+SELECT id, transcription_id, translated_text, created_at, stale FROM translations;
+DROP INDEX IF EXISTS idx_translations_transcription_id;
+DROP TABLE translations;
+ALTER TABLE translations_old RENAME TO translations;
+CREATE TABLE IF NOT EXISTS transcripts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    filename TEXT NOT NULL,
+    text TEXT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,73 @@
+-- +goose Up
+-- FTS5 needs the sqlite_fts5 build tag of the SQLite driver, see the Makefile
+CREATE VIRTUAL TABLE transcriptions_fts USING fts5(transcript_text, content='transcriptions', content_rowid='id', tokenize='unicode61');
+CREATE VIRTUAL TABLE segments_fts USING fts5(text, content='segments', content_rowid='id', tokenize='unicode61');
+CREATE VIRTUAL TABLE translations_fts USING fts5(translated_text, content='translations', content_rowid='id', tokenize='unicode61');
+INSERT INTO transcriptions_fts(transcriptions_fts) VALUES ('rebuild');
+INSERT INTO segments_fts(segments_fts) VALUES ('rebuild');
+INSERT INTO translations_fts(translations_fts) VALUES ('rebuild');
+
+-- +goose StatementBegin
+CREATE TRIGGER transcriptions_fts_after_insert AFTER INSERT ON transcriptions BEGIN
+    INSERT INTO transcriptions_fts(rowid, transcript_text) VALUES (new.id, new.transcript_text);
+END;
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE TRIGGER transcriptions_fts_after_delete AFTER DELETE ON transcriptions BEGIN
+    INSERT INTO transcriptions_fts(transcriptions_fts, rowid, transcript_text) VALUES ('delete', old.id, old.transcript_text);
+END;
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE TRIGGER transcriptions_fts_after_update AFTER UPDATE ON transcriptions BEGIN
+    INSERT INTO transcriptions_fts(transcriptions_fts, rowid, transcript_text) VALUES ('delete', old.id, old.transcript_text);
+    INSERT INTO transcriptions_fts(rowid, transcript_text) VALUES (new.id, new.transcript_text);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER segments_fts_after_insert AFTER INSERT ON segments BEGIN
+    INSERT INTO segments_fts(rowid, text) VALUES (new.id, new.text);
+END;
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE TRIGGER segments_fts_after_delete AFTER DELETE ON segments BEGIN
+    INSERT INTO segments_fts(segments_fts, rowid, text) VALUES ('delete', old.id, old.text);
+END;
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE TRIGGER segments_fts_after_update AFTER UPDATE ON segments BEGIN
+    INSERT INTO segments_fts(segments_fts, rowid, text) VALUES ('delete', old.id, old.text);
+    INSERT INTO segments_fts(rowid, text) VALUES (new.id, new.text);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER translations_fts_after_insert AFTER INSERT ON translations BEGIN
+    INSERT INTO translations_fts(rowid, translated_text) VALUES (new.id, new.translated_text);
+END;
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE TRIGGER translations_fts_after_delete AFTER DELETE ON translations BEGIN
+    INSERT INTO translations_fts(translations_fts, rowid, translated_text) VALUES ('delete', old.id, old.translated_text);
+END;
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE TRIGGER translations_fts_after_update AFTER UPDATE ON translations BEGIN
+    INSERT INTO translations_fts(translations_fts, rowid, translated_text) VALUES ('delete', old.id, old.translated_text);
+    INSERT INTO translations_fts(rowid, translated_text) VALUES (new.id, new.translated_text);
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TRIGGER IF EXISTS translations_fts_after_update;
+DROP TRIGGER IF EXISTS translations_fts_after_delete;
+DROP TRIGGER IF EXISTS translations_fts_after_insert;
+DROP TRIGGER IF EXISTS segments_fts_after_update;
+DROP TRIGGER IF EXISTS segments_fts_after_delete;
+DROP TRIGGER IF EXISTS segments_fts_after_insert;
+DROP TRIGGER IF EXISTS transcriptions_fts_after_update;
+DROP TRIGGER IF EXISTS transcriptions_fts_after_delete;
+DROP TRIGGER IF EXISTS transcriptions_fts_after_insert;
+DROP TABLE IF EXISTS translations_fts;
+DROP TABLE IF EXISTS segments_fts;
+DROP TABLE IF EXISTS transcriptions_fts;
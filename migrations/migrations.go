@@ -0,0 +1,26 @@
+// Package migrations embeds the goose SQL migrations so they are applied by the binaries
+// without the source tree.
+package migrations
+
+import (
+	"embed"
+	"io/fs"
+)
+
+// FS holds the SQLite migration files
+//
+//go:embed *.sql
+var FS embed.FS
+
+//go:embed postgres/*.sql
+var postgres embed.FS
+
+// Postgres returns the PostgreSQL migration files. PostgreSQL databases start from a
+// consolidated schema instead of replaying the SQLite history.
+func Postgres() fs.FS {
+	sub, err := fs.Sub(postgres, "postgres")
+	if err != nil {
+		panic(err) // the directory is embedded, Sub only fails for invalid names
+	}
+	return sub
+}
@@ -0,0 +1,134 @@
+-- +goose Up
+-- PostgreSQL starts from the schema reached by the SQLite migrations 00001-00015; the
+-- full-text search uses generated tsvector columns instead of FTS tables
+CREATE TABLE transcriptions (
+    id BIGSERIAL PRIMARY KEY,
+    file_name TEXT NOT NULL,
+    transcript_text TEXT NOT NULL,
+    audio_profile TEXT,
+    transcription_options TEXT,
+    language TEXT,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    search_vector TSVECTOR GENERATED ALWAYS AS (to_tsvector('simple', transcript_text)) STORED
+);
+CREATE INDEX idx_transcriptions_search ON transcriptions USING GIN (search_vector);
+
+CREATE TABLE untranslatable_terms (
+    id BIGSERIAL PRIMARY KEY,
+    term TEXT NOT NULL UNIQUE,
+    description TEXT,
+    added_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE translations (
+    id BIGSERIAL PRIMARY KEY,
+    transcription_id BIGINT NOT NULL REFERENCES transcriptions(id) ON DELETE CASCADE,
+    translated_text TEXT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    stale BOOLEAN NOT NULL DEFAULT FALSE,
+    search_vector TSVECTOR GENERATED ALWAYS AS (to_tsvector('simple', translated_text)) STORED
+);
+CREATE INDEX idx_translations_transcription_id ON translations(transcription_id);
+CREATE INDEX idx_translations_search ON translations USING GIN (search_vector);
+
+CREATE TABLE segments (
+    id BIGSERIAL PRIMARY KEY,
+    transcription_id BIGINT NOT NULL REFERENCES transcriptions(id) ON DELETE CASCADE,
+    segment_index INTEGER NOT NULL,
+    start_ms BIGINT NOT NULL,
+    end_ms BIGINT NOT NULL,
+    text TEXT NOT NULL,
+    confidence DOUBLE PRECISION,
+    search_vector TSVECTOR GENERATED ALWAYS AS (to_tsvector('simple', text)) STORED
+);
+CREATE INDEX idx_segments_transcription_id ON segments(transcription_id);
+CREATE INDEX idx_segments_search ON segments USING GIN (search_vector);
+
+CREATE TABLE media (
+    id BIGSERIAL PRIMARY KEY,
+    transcription_id BIGINT NOT NULL UNIQUE REFERENCES transcriptions(id) ON DELETE CASCADE,
+    duration_ms BIGINT NOT NULL DEFAULT 0,
+    container TEXT NOT NULL DEFAULT '',
+    video_codec TEXT NOT NULL DEFAULT '',
+    audio_codec TEXT NOT NULL DEFAULT '',
+    bit_rate BIGINT NOT NULL DEFAULT 0,
+    audio_channels INTEGER NOT NULL DEFAULT 0,
+    file_size BIGINT NOT NULL DEFAULT 0,
+    sha256 TEXT NOT NULL DEFAULT '',
+    media_created_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_media_sha256 ON media(sha256);
+
+CREATE TABLE pending_transcriptions (
+    id BIGSERIAL PRIMARY KEY,
+    file_name TEXT NOT NULL,
+    transcript_ids TEXT NOT NULL,
+    submission TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE words (
+    id BIGSERIAL PRIMARY KEY,
+    transcription_id BIGINT NOT NULL REFERENCES transcriptions(id) ON DELETE CASCADE,
+    word_index INTEGER NOT NULL,
+    start_ms BIGINT NOT NULL,
+    end_ms BIGINT NOT NULL,
+    text TEXT NOT NULL,
+    confidence DOUBLE PRECISION NOT NULL
+);
+CREATE INDEX idx_words_transcription_id ON words(transcription_id);
+
+CREATE TABLE transcription_revisions (
+    id BIGSERIAL PRIMARY KEY,
+    transcription_id BIGINT NOT NULL REFERENCES transcriptions(id) ON DELETE CASCADE,
+    revision INTEGER NOT NULL,
+    transcript_text TEXT NOT NULL,
+    diff TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (transcription_id, revision)
+);
+
+-- names are unique regardless of case, like the NOCASE columns of SQLite
+CREATE TABLE tags (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL
+);
+CREATE UNIQUE INDEX idx_tags_name ON tags(lower(name));
+CREATE TABLE transcription_tags (
+    transcription_id BIGINT NOT NULL REFERENCES transcriptions(id) ON DELETE CASCADE,
+    tag_id BIGINT NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (transcription_id, tag_id)
+);
+CREATE INDEX idx_transcription_tags_tag_id ON transcription_tags(tag_id);
+
+CREATE TABLE collections (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX idx_collections_name ON collections(lower(name));
+CREATE TABLE collection_items (
+    collection_id BIGINT NOT NULL REFERENCES collections(id) ON DELETE CASCADE,
+    transcription_id BIGINT NOT NULL REFERENCES transcriptions(id) ON DELETE CASCADE,
+    added_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (collection_id, transcription_id)
+);
+CREATE INDEX idx_collection_items_transcription_id ON collection_items(transcription_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS collection_items;
+DROP TABLE IF EXISTS collections;
+DROP TABLE IF EXISTS transcription_tags;
+DROP TABLE IF EXISTS tags;
+DROP TABLE IF EXISTS transcription_revisions;
+DROP TABLE IF EXISTS words;
+DROP TABLE IF EXISTS pending_transcriptions;
+DROP TABLE IF EXISTS media;
+DROP TABLE IF EXISTS segments;
+DROP TABLE IF EXISTS translations;
+DROP TABLE IF EXISTS untranslatable_terms;
+DROP TABLE IF EXISTS transcriptions;
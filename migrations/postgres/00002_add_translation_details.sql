@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE translations ADD COLUMN language TEXT;
+ALTER TABLE translations ADD COLUMN model TEXT;
+
+-- +goose Down
+ALTER TABLE translations DROP COLUMN model;
+ALTER TABLE translations DROP COLUMN language;